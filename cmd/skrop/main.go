@@ -24,6 +24,8 @@ const (
 	tlsKeyFlag              = "tls-key"
 	insecureFlag            = "insecure"
 	experimentalUpgradeFlag = "experimental-upgrade"
+	vipsCacheMaxFlag        = "vips-cache-max"
+	vipsCacheMaxMemoryFlag  = "vips-cache-max-memory"
 )
 
 const (
@@ -44,6 +46,9 @@ const (
 	keyPathTLSUsage  = "path of the key"
 
 	experimentalUpgradeUsage = "enable experimental feature to handle upgrade protocol requests"
+
+	vipsCacheMaxUsage       = "number of operations kept in the cache of libvips, 0 for the default"
+	vipsCacheMaxMemoryUsage = "memory in bytes used by the cache of libvips, 0 for the default"
 )
 
 var fs *flag.FlagSet
@@ -56,6 +61,8 @@ var (
 	verbose             bool
 	experimentalUpgrade bool
 	routesFile          string
+	vipsCacheMax        int
+	vipsCacheMaxMemory  int
 )
 
 func usage() {
@@ -79,6 +86,8 @@ func init() {
 	fs.StringVar(&certPathTLS, tlsCertFlag, "", certPathTLSUsage)
 	fs.StringVar(&keyPathTLS, tlsKeyFlag, "", keyPathTLSUsage)
 	fs.BoolVar(&experimentalUpgrade, experimentalUpgradeFlag, false, experimentalUpgradeUsage)
+	fs.IntVar(&vipsCacheMax, vipsCacheMaxFlag, 0, vipsCacheMaxUsage)
+	fs.IntVar(&vipsCacheMaxMemory, vipsCacheMaxMemoryFlag, 0, vipsCacheMaxMemoryUsage)
 
 	err := fs.Parse(os.Args[1:])
 	if err != nil {
@@ -101,6 +110,11 @@ func main() {
 	}
 	log.Debug(fmt.Sprintf("Using routes-file %s", routesFile))
 
+	skropFilters.InitVips(skropFilters.VipsOptions{
+		CacheMax:       vipsCacheMax,
+		CacheMaxMemory: vipsCacheMaxMemory,
+	})
+
 	o := skipper.Options{
 		Address: address,
 		CustomDataClients: []routing.DataClient{
@@ -121,6 +135,58 @@ func main() {
 			skropFilters.NewBlur(),
 			skropFilters.NewOverlayImage(),
 			skropFilters.NewSharpen(),
+			skropFilters.NewRotate(),
+			skropFilters.NewFlip(),
+			skropFilters.NewFlop(),
+			skropFilters.NewGrayscale(),
+			skropFilters.NewBrightness(),
+			skropFilters.NewContrast(),
+			skropFilters.NewGamma(),
+			skropFilters.NewLevels(),
+			skropFilters.NewAutoContrast(),
+			skropFilters.NewNegate(),
+			skropFilters.NewThreshold(),
+			skropFilters.NewRoundedCorners(),
+			skropFilters.NewMask(),
+			skropFilters.NewDropShadow(),
+			skropFilters.NewOpacity(),
+			skropFilters.NewExtractChannel(),
+			skropFilters.NewConvolve(),
+			skropFilters.NewEmboss(),
+			skropFilters.NewEdges(),
+			skropFilters.NewDenoise(),
+			skropFilters.NewPosterize(),
+			skropFilters.NewDominantColor(),
+			skropFilters.NewBorder(),
+			skropFilters.NewTrim(),
+			skropFilters.NewEmbed(),
+			skropFilters.NewExtract(),
+			skropFilters.NewZoom(),
+			skropFilters.NewSmartCrop(),
+			skropFilters.NewWatermarkText(),
+			skropFilters.NewBackground(),
+			skropFilters.NewAutoOrient(),
+			skropFilters.NewPixelate(),
+			skropFilters.NewVignette(),
+			skropFilters.NewSaturation(),
+			skropFilters.NewHueRotate(),
+			skropFilters.NewTint(),
+			skropFilters.NewResizePercent(),
+			skropFilters.NewFit(),
+			skropFilters.NewResizeLongestEdge(),
+			skropFilters.NewDpr(),
+			skropFilters.NewInterpolator(),
+			skropFilters.NewCropOffset(),
+			skropFilters.NewCropAspect(),
+			skropFilters.NewConvert(),
+			skropFilters.NewStripMetadata(),
+			skropFilters.NewInterlace(),
+			skropFilters.NewPreserveProfile(),
+			skropFilters.NewLossless(),
+			skropFilters.NewPngCompression(),
+			skropFilters.NewAutoFormat(),
+			skropFilters.NewChromaSubsampling(),
+			skropFilters.NewMinDimension(),
 			skropFilters.NewFinalizeResponse(),
 			skropFilters.NewTransformFromQueryParams(),
 			skropFilters.NewLocalFileCache(cache.NewFileSystemCache()),
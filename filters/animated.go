@@ -0,0 +1,67 @@
+package filters
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrAnimatedImage is returned by the filters when the image is animated: bimg only processes the first frame
+// of the animated GIF and WebP images, so they are passed through untouched instead of losing their animation
+var ErrAnimatedImage = errors.New("processing skipped, the animated images are passed through untouched")
+
+// isAnimated tells if the GIF or WebP image has more than one frame
+func isAnimated(buf []byte) bool {
+	switch {
+	case bytes.HasPrefix(buf, []byte("GIF8")):
+		return gifFrames(buf) > 1
+	case len(buf) > 20 && bytes.HasPrefix(buf, []byte("RIFF")) && string(buf[8:16]) == "WEBPVP8X":
+		//the animation flag of the extended WebP header
+		return buf[20]&0x02 != 0
+	default:
+		return false
+	}
+}
+
+// gifFrames counts the image descriptors of the GIF, skipping the data of the frames without decoding it
+func gifFrames(buf []byte) int {
+	//the header and the logical screen descriptor, followed by the global color table
+	i := 13
+	if len(buf) < i {
+		return 0
+	}
+	if buf[10]&0x80 != 0 {
+		i += 3 << (buf[10]&0x07 + 1)
+	}
+
+	frames := 0
+	for i < len(buf) {
+		switch buf[i] {
+		case 0x21:
+			//the extension label, followed by the data sub-blocks
+			i = skipSubBlocks(buf, i+2)
+		case 0x2C:
+			frames++
+			if i+10 > len(buf) {
+				return frames
+			}
+			packed := buf[i+9]
+			i += 10
+			if packed&0x80 != 0 {
+				i += 3 << (packed&0x07 + 1)
+			}
+			//the minimum LZW code size, followed by the data sub-blocks
+			i = skipSubBlocks(buf, i+1)
+		default:
+			//the trailer or a malformed image
+			return frames
+		}
+	}
+	return frames
+}
+
+func skipSubBlocks(buf []byte, i int) int {
+	for i < len(buf) && buf[i] != 0 {
+		i += int(buf[i]) + 1
+	}
+	return i + 1
+}
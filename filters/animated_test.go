@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io/ioutil"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+)
+
+// animatedGIF encodes a GIF with the given number of frames, each one of a different color
+func animatedGIF(t *testing.T, frames int) []byte {
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 16, 16), palette.Plan9)
+		for p := range frame.Pix {
+			frame.Pix[p] = uint8(i * 40)
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestIsAnimated_GIF(t *testing.T) {
+	assert.True(t, isAnimated(animatedGIF(t, 3)))
+	assert.Equal(t, 3, gifFrames(animatedGIF(t, 3)))
+
+	assert.False(t, isAnimated(animatedGIF(t, 1)))
+}
+
+func TestIsAnimated_WebP(t *testing.T) {
+	header := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00")
+
+	assert.True(t, isAnimated(append(append([]byte{}, header...), 0x02, 0, 0, 0)))
+	assert.False(t, isAnimated(append(append([]byte{}, header...), 0x00, 0, 0, 0)))
+	assert.False(t, isAnimated([]byte("RIFF\x00\x00\x00\x00WEBPVP8 ")))
+}
+
+func TestIsAnimated_OtherTypes(t *testing.T) {
+	buf, _ := bimg.Read("../images/lisbon-tram.jpg")
+	assert.False(t, isAnimated(buf))
+
+	png, _ := encodePixels(uniformImage(color.NRGBA{A: 255}))
+	assert.False(t, isAnimated(png))
+}
+
+func TestHandleImageResponse_AnimatedPassthrough(t *testing.T) {
+	original := animatedGIF(t, 3)
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(original))
+
+	err := HandleImageResponse(fc, &resize{width: 8, height: 8})
+	assert.Equal(t, ErrAnimatedImage, err)
+
+	err = HandlePixelResponse(fc, &negate{active: true})
+	assert.Equal(t, ErrAnimatedImage, err)
+
+	FinalizeResponse(fc)
+
+	result := readResultImage(fc.Response().Body, t).Image()
+	assert.Equal(t, original, result)
+
+	anim, err := gif.DecodeAll(bytes.NewReader(result))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, anim.Image, 3, "all the frames should survive")
+}
@@ -0,0 +1,138 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// AutoContrastName is the name of the filter
+	AutoContrastName = "autoContrast"
+	// ContrastStretch stretches the tones of the image to the full range
+	ContrastStretch = "stretch"
+	// ContrastEqualize spreads the tones of the image evenly on the full range
+	ContrastEqualize = "equalize"
+)
+
+var contrastVariants = map[string]bool{ContrastStretch: true, ContrastEqualize: true}
+
+type autoContrast struct {
+	variant string
+}
+
+// NewAutoContrast creates a new filter of this type
+func NewAutoContrast() filters.Spec {
+	return &autoContrast{}
+}
+
+func (f *autoContrast) Name() string {
+	return AutoContrastName
+}
+
+func (f *autoContrast) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for auto contrast ", f)
+
+	//the histogram of the luminance is used for all the channels, so the colors keep their hue
+	var histogram [256]int
+	total := 0
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			histogram[luminance(row[i], row[i+1], row[i+2])]++
+			total++
+		}
+	}
+
+	var table [256]uint8
+	if f.variant == ContrastEqualize {
+		table = equalizeTable(&histogram, total)
+	} else {
+		table = stretchTable(&histogram)
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+// stretchTable maps the darkest tone of the histogram to black and the brightest one to white
+func stretchTable(histogram *[256]int) [256]uint8 {
+	low, high := 0, 255
+	for low < 255 && histogram[low] == 0 {
+		low++
+	}
+	for high > 0 && histogram[high] == 0 {
+		high--
+	}
+
+	var table [256]uint8
+	for i := range table {
+		if high <= low {
+			table[i] = uint8(i)
+			continue
+		}
+		table[i] = clampUint8(float64(i-low) * 255 / float64(high-low))
+	}
+	return table
+}
+
+// equalizeTable maps every tone to its rank in the cumulative histogram
+func equalizeTable(histogram *[256]int, total int) [256]uint8 {
+	var table [256]uint8
+
+	first := 0
+	for first < 255 && histogram[first] == 0 {
+		first++
+	}
+	if total <= histogram[first] {
+		for i := range table {
+			table[i] = uint8(i)
+		}
+		return table
+	}
+
+	cumulative := 0
+	for i := range table {
+		cumulative += histogram[i]
+		if i < first {
+			continue
+		}
+		table[i] = clampUint8(float64(cumulative-histogram[first]) * 255 / float64(total-histogram[first]))
+	}
+	return table
+}
+
+// CanBeMerged refuses merging, as the histogram depends on the image the previous pixel filters produce
+func (f *autoContrast) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *autoContrast) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *autoContrast) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) > 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	a := &autoContrast{variant: ContrastStretch}
+
+	if len(args) == 1 {
+		if a.variant, err = parse.EskipEnumArg(args[0], contrastVariants); err != nil {
+			return nil, parse.AtArg(err, AutoContrastName, 1, "variant")
+		}
+	}
+
+	return a, nil
+}
+
+func (f *autoContrast) Request(ctx filters.FilterContext) {}
+
+func (f *autoContrast) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
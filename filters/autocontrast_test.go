@@ -0,0 +1,99 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// lowContrastGradient is a gray gradient between 100 and 150
+func lowContrastGradient() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 51, 2))
+	for x := 0; x < 51; x++ {
+		gray := uint8(100 + x)
+		img.SetNRGBA(x, 0, color.NRGBA{R: gray, G: gray, B: gray, A: 255})
+		img.SetNRGBA(x, 1, color.NRGBA{R: gray, G: gray, B: gray, A: 255})
+	}
+	return img
+}
+
+func grayRange(img *image.NRGBA) (uint8, uint8) {
+	low, high := uint8(255), uint8(0)
+	for x := 0; x < img.Rect.Dx(); x++ {
+		v := img.NRGBAAt(x, 0).R
+		if v < low {
+			low = v
+		}
+		if v > high {
+			high = v
+		}
+	}
+	return low, high
+}
+
+func TestNewAutoContrast(t *testing.T) {
+	name := NewAutoContrast().Name()
+	assert.Equal(t, "autoContrast", name)
+}
+
+func TestAutoContrast_TransformPixels_Stretch(t *testing.T) {
+	result, err := (&autoContrast{variant: ContrastStretch}).TransformPixels(lowContrastGradient())
+
+	assert.Nil(t, err)
+	low, high := grayRange(result)
+	assert.Equal(t, uint8(0), low)
+	assert.Equal(t, uint8(255), high)
+	assert.Equal(t, uint8(128), result.NRGBAAt(25, 0).R)
+}
+
+func TestAutoContrast_TransformPixels_Equalize(t *testing.T) {
+	result, err := (&autoContrast{variant: ContrastEqualize}).TransformPixels(lowContrastGradient())
+
+	assert.Nil(t, err)
+	low, high := grayRange(result)
+	assert.Equal(t, uint8(0), low)
+	assert.Equal(t, uint8(255), high)
+}
+
+func TestAutoContrast_TransformPixels_Uniform(t *testing.T) {
+	c := color.NRGBA{R: 120, G: 120, B: 120, A: 255}
+
+	for _, variant := range []string{ContrastStretch, ContrastEqualize} {
+		result, err := (&autoContrast{variant: variant}).TransformPixels(uniformImage(c))
+
+		assert.Nil(t, err)
+		assert.Equal(t, uniformImage(c), result, "a uniform image has no contrast to stretch")
+	}
+}
+
+func TestAutoContrast_CanBeMerged(t *testing.T) {
+	f := autoContrast{variant: ContrastStretch}
+	assert.False(t, f.CanBeMerged(&autoContrast{variant: ContrastStretch}))
+}
+
+func TestAutoContrast_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewAutoContrast, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "stretch",
+		Args: []interface{}{"stretch"},
+		Err:  false,
+	}, {
+		Msg:  "equalize",
+		Args: []interface{}{"equalize"},
+		Err:  false,
+	}, {
+		Msg:  "unknown variant",
+		Args: []interface{}{"normalize"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"stretch", "equalize"},
+		Err:  true,
+	}})
+}
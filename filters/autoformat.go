@@ -0,0 +1,154 @@
+package filters
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// AutoFormatName is the name of the filter
+const AutoFormatName = "autoFormat"
+
+// autoFormat encodes the image as WebP when the client accepts it, keeping the source format otherwise.
+// The format is chosen for every request, based on its Accept header
+type autoFormat struct{}
+
+// NewAutoFormat creates a new filter of this type
+func NewAutoFormat() filters.Spec {
+	return &autoFormat{}
+}
+
+func (f *autoFormat) Name() string {
+	return AutoFormatName
+}
+
+func (f *autoFormat) CreateOptions(c *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for auto format ", f)
+
+	accept := ""
+	if c.filterContext != nil && *c.filterContext != nil {
+		accept = (*c.filterContext).Request().Header.Get("Accept")
+	}
+
+	return &bimg.Options{Type: acceptedType(accept, bimg.DetermineImageType(c.Image.Image()))}, nil
+}
+
+func (f *autoFormat) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//only the output type is changed
+	return true
+}
+
+func (f *autoFormat) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	if self.Type != bimg.UNKNOWN {
+		other.Type = self.Type
+	}
+	return other
+}
+
+func (f *autoFormat) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &autoFormat{}, nil
+}
+
+func (f *autoFormat) Request(ctx filters.FilterContext) {}
+
+func (f *autoFormat) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	//the type is the merged one or, if the image was transformed, the one of the image
+	imageType := ctx.StateBag()[skropOptions].(*bimg.Options).Type
+	if imageType == bimg.UNKNOWN {
+		imageType = bimg.DetermineImageType(ctx.StateBag()[skropImage].(*bimg.Image).Image())
+	}
+
+	setImageTypeHeaders(ctx, imageType)
+	addVary(ctx, "Accept")
+}
+
+// acceptedType returns WebP if the client accepts it at least as much as the source type, otherwise it
+// returns UNKNOWN, so the source type is kept. AVIF is not supported by bimg, so it is never chosen
+func acceptedType(accept string, source bimg.ImageType) bimg.ImageType {
+	if source == bimg.WEBP || !bimg.IsTypeSupportedSave(bimg.WEBP) {
+		return bimg.UNKNOWN
+	}
+
+	//the wildcards are not enough, as the clients not supporting WebP send them too
+	webp, explicit := acceptQuality(accept, "image/webp")
+	if !explicit || webp == 0 {
+		return bimg.UNKNOWN
+	}
+
+	if sourceQuality, _ := acceptQuality(accept, "image/"+bimg.ImageTypeName(source)); webp < sourceQuality {
+		return bimg.UNKNOWN
+	}
+
+	return bimg.WEBP
+}
+
+// acceptQuality returns the q-value of the most specific media range of the Accept header matching the
+// media type, and if the media type is listed explicitly
+func acceptQuality(accept string, mediaType string) (float64, bool) {
+	quality, specificity := 0.0, -1
+	mainType := strings.SplitN(mediaType, "/", 2)[0]
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		params := strings.Split(mediaRange, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+
+		var s int
+		switch name {
+		case mediaType:
+			s = 2
+		case mainType + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s > specificity {
+			quality, specificity = mediaRangeQuality(params[1:]), s
+		}
+	}
+
+	return quality, specificity == 2
+}
+
+// mediaRangeQuality parses the q parameter of a media range, 1 if it is missing or invalid
+func mediaRangeQuality(params []string) float64 {
+	for _, param := range params {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 || strings.ToLower(kv[0]) != "q" {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 1
+		}
+		return q
+	}
+	return 1
+}
+
+// addVary adds the header to the Vary header of the response, unless it is already there
+func addVary(ctx filters.FilterContext, header string) {
+	rsp := ctx.Response()
+	for _, value := range rsp.Header["Vary"] {
+		for _, h := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(h), header) {
+				return
+			}
+		}
+	}
+	rsp.Header.Add("Vary", header)
+}
@@ -0,0 +1,112 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewAutoFormat(t *testing.T) {
+	name := NewAutoFormat().Name()
+	assert.Equal(t, "autoFormat", name)
+}
+
+func TestAutoFormat_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewAutoFormat, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{"webp"},
+		Err:  true,
+	}})
+}
+
+func TestAutoFormat_Merge(t *testing.T) {
+	s := autoFormat{}
+
+	opt := s.Merge(&bimg.Options{Width: 100, Type: bimg.PNG}, &bimg.Options{Type: bimg.WEBP})
+	assert.Equal(t, bimg.WEBP, opt.Type)
+	assert.Equal(t, 100, opt.Width)
+
+	//the source type is kept
+	opt = s.Merge(&bimg.Options{Type: bimg.PNG}, &bimg.Options{})
+	assert.Equal(t, bimg.PNG, opt.Type)
+}
+
+func TestAcceptedType(t *testing.T) {
+	for _, test := range []struct {
+		accept   string
+		source   bimg.ImageType
+		expected bimg.ImageType
+	}{
+		{"", bimg.JPEG, bimg.UNKNOWN},
+		{"image/webp", bimg.JPEG, bimg.WEBP},
+		{"image/avif,image/webp,image/apng,image/*,*/*;q=0.8", bimg.JPEG, bimg.WEBP},
+		{"image/png,image/svg+xml,image/*;q=0.8,video/*;q=0.8,*/*;q=0.5", bimg.PNG, bimg.UNKNOWN},
+		{"image/*", bimg.JPEG, bimg.UNKNOWN},
+		{"*/*", bimg.JPEG, bimg.UNKNOWN},
+		{"image/webp;q=0", bimg.JPEG, bimg.UNKNOWN},
+		{"image/webp;q=0.5,image/jpeg", bimg.JPEG, bimg.UNKNOWN},
+		{"image/jpeg;q=0.5, image/webp;q=0.9", bimg.JPEG, bimg.WEBP},
+		{"image/webp;q=0.8,image/*;q=0.8", bimg.PNG, bimg.WEBP},
+		{"IMAGE/WEBP; Q=0.7, image/*;q=0.9", bimg.PNG, bimg.UNKNOWN},
+		{"image/webp", bimg.WEBP, bimg.UNKNOWN},
+	} {
+		assert.Equal(t, test.expected, acceptedType(test.accept, test.source), test.accept)
+	}
+}
+
+func TestAcceptQuality(t *testing.T) {
+	q, explicit := acceptQuality("image/*;q=0.8,*/*;q=0.5", "image/webp")
+	assert.Equal(t, 0.8, q)
+	assert.False(t, explicit)
+
+	q, explicit = acceptQuality("text/html,*/*;q=0.5", "image/webp")
+	assert.Equal(t, 0.5, q)
+	assert.False(t, explicit)
+
+	q, explicit = acceptQuality("image/webp;level=1;q=0.3,image/*", "image/webp")
+	assert.Equal(t, 0.3, q)
+	assert.True(t, explicit)
+
+	q, _ = acceptQuality("text/html", "image/webp")
+	assert.Equal(t, 0.0, q)
+}
+
+func TestAutoFormat_Response_WebP(t *testing.T) {
+	fc := createDefaultContext(t, "http://localhost:9090/images/bag.png")
+	fc.Request().RequestURI = "/images/bag.png"
+	fc.Request().Header.Set("Accept", "image/webp,image/*,*/*;q=0.8")
+
+	(&autoFormat{}).Response(fc)
+
+	assert.Equal(t, bimg.WEBP, fc.FStateBag[skropOptions].(*bimg.Options).Type)
+	assert.Equal(t, "image/webp", fc.Response().Header.Get("Content-Type"))
+	assert.Equal(t, "Accept", fc.Response().Header.Get("Vary"))
+}
+
+func TestAutoFormat_Response_SourceType(t *testing.T) {
+	fc := createDefaultContext(t, "http://localhost:9090/images/bag.png")
+	fc.Request().RequestURI = "/images/bag.png"
+	fc.Request().Header.Set("Accept", "image/png,image/*;q=0.8")
+	fc.Response().Header.Set("Vary", "Accept-Encoding")
+
+	(&autoFormat{}).Response(fc)
+
+	assert.Equal(t, bimg.UNKNOWN, fc.FStateBag[skropOptions].(*bimg.Options).Type)
+	assert.Equal(t, "image/png", fc.Response().Header.Get("Content-Type"))
+	assert.Equal(t, []string{"Accept-Encoding", "Accept"}, fc.Response().Header["Vary"])
+}
+
+func TestAddVary(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.Response().Header.Set("Vary", "accept, Origin")
+
+	addVary(fc, "Accept")
+
+	assert.Equal(t, []string{"accept, Origin"}, fc.Response().Header["Vary"])
+}
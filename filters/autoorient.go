@@ -0,0 +1,53 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// AutoOrientName is the name of the filter
+const AutoOrientName = "autoOrient"
+
+// autoOrient leaves the pixels as they are: bimg rotates the image based on its EXIF orientation while the pixels
+// are decoded, and the orientation is dropped when they are encoded again. This way the filters after it, like
+// overlayImage, compute the positions on the rotated image, instead of on the pixels as they are stored
+type autoOrient struct{}
+
+// NewAutoOrient creates a new filter of this type
+func NewAutoOrient() filters.Spec {
+	return &autoOrient{}
+}
+
+func (f *autoOrient) Name() string {
+	return AutoOrientName
+}
+
+func (f *autoOrient) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for auto orient ", f)
+	return img, nil
+}
+
+func (f *autoOrient) CanBeMerged(other PixelFilter) bool {
+	return true
+}
+
+func (f *autoOrient) Merge(other PixelFilter) PixelFilter {
+	//the pixels are oriented once they are decoded, so the other filter is enough
+	return other
+}
+
+func (f *autoOrient) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &autoOrient{}, nil
+}
+
+func (f *autoOrient) Request(ctx filters.FilterContext) {}
+
+func (f *autoOrient) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
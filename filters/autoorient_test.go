@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// exifRotatedImage is stored as 40x20, with the EXIF orientation 6, so it is displayed as 20x40
+func exifRotatedImage() []byte {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 40, 20)), nil)
+
+	app1 := []byte{
+		0xFF, 0xE1, 0x00, 0x22,
+		'E', 'x', 'i', 'f', 0, 0,
+		//big endian TIFF header, with the first IFD right after it
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08,
+		//one entry: orientation, short, count 1, value 6
+		0x00, 0x01,
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x06, 0x00, 0x00,
+		//no next IFD
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	jpg := buf.Bytes()
+	return append(append(append([]byte{}, jpg[:2]...), app1...), jpg[2:]...)
+}
+
+func TestNewAutoOrient(t *testing.T) {
+	name := NewAutoOrient().Name()
+	assert.Equal(t, "autoOrient", name)
+}
+
+func TestAutoOrient_Name(t *testing.T) {
+	c := autoOrient{}
+	assert.Equal(t, "autoOrient", c.Name())
+}
+
+func TestAutoOrient_TransformPixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+
+	result, err := (&autoOrient{}).TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, img, result)
+}
+
+func TestAutoOrient_OverlaysOnRotatedImage(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(exifRotatedImage())
+	fc.FStateBag[hasMergedFilters] = false
+	HandlePixelResponse(fc, &autoOrient{})
+
+	if err := applyPixelFilters(fc); err != nil {
+		t.Fatal(err)
+	}
+
+	oriented := fc.FStateBag[skropImage].(*bimg.Image)
+	size, _ := oriented.Size()
+	assert.Equal(t, bimg.ImageSize{Width: 20, Height: 40}, size)
+
+	//the star is placed in the bottom right corner of the image as it is displayed
+	overArr, _ := readImage("../images/star.png")
+	overSize, _ := bimg.NewImage(overArr).Size()
+	o := &overlay{file: "../images/star.png", opacity: 1,
+		horizontalGravity: bimg.GravityEast, verticalGravity: bimg.GravitySouth}
+	options, _ := o.CreateOptions(buildParameters(nil, oriented))
+
+	assert.Equal(t, 20-overSize.Width, options.WatermarkImage.Left)
+	assert.Equal(t, 40-overSize.Height, options.WatermarkImage.Top)
+}
+
+func TestAutoOrient_CanBeMerged(t *testing.T) {
+	f := autoOrient{}
+	queued := &negate{active: true}
+
+	assert.True(t, f.CanBeMerged(queued))
+	assert.Equal(t, queued, f.Merge(queued))
+}
+
+func TestAutoOrient_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewAutoOrient, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,73 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// BackgroundName is the name of the filter
+const BackgroundName = "background"
+
+type background struct {
+	color bimg.Color
+}
+
+// NewBackground creates a new filter of this type
+func NewBackground() filters.Spec {
+	return &background{}
+}
+
+func (f *background) Name() string {
+	return BackgroundName
+}
+
+func (f *background) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for background ", f)
+
+	return &bimg.Options{
+		Background: f.color}, nil
+}
+
+func (f *background) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	zero := bimg.Color{}
+
+	//it can be merged if the background was not set or if it is set to the same value
+	return other.Background == zero || other.Background == self.Background
+}
+
+func (f *background) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Background = self.Background
+	return other
+}
+
+func (f *background) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	parsed, err := parse.EskipColorArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	c := bimgColor(parsed)
+
+	//bimg does not flatten the image on black, so the closest color is used
+	if c == bimg.ColorBlack {
+		c.B = 1
+	}
+
+	return &background{color: c}, nil
+}
+
+func (f *background) Request(ctx filters.FilterContext) {}
+
+func (f *background) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	//the transparency is flattened, so the image can be converted to any type
+	delete(ctx.StateBag(), skropAlpha)
+}
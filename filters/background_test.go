@@ -0,0 +1,116 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewBackground(t *testing.T) {
+	name := NewBackground().Name()
+	assert.Equal(t, "background", name)
+}
+
+func TestBackground_Name(t *testing.T) {
+	c := background{}
+	assert.Equal(t, "background", c.Name())
+}
+
+func TestBackground_CreateOptions(t *testing.T) {
+	c := background{color: bimg.Color{R: 240, G: 0, B: 200}}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.PNGImage()))
+
+	assert.Equal(t, &bimg.Options{Background: bimg.Color{R: 240, G: 0, B: 200}}, options)
+}
+
+func TestBackground_FlattensToJPEG(t *testing.T) {
+	//an opaque black square in the middle of a transparent image
+	pixels := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 15; x++ {
+			pixels.SetNRGBA(x, y, color.NRGBA{A: 255})
+		}
+	}
+	buf, _ := encodePixels(pixels)
+
+	c := background{color: bimg.Color{R: 255, G: 255, B: 255}}
+	options, _ := c.CreateOptions(buildParameters(nil, bimg.NewImage(buf)))
+	options.Type = bimg.JPEG
+	result, err := transformImage(bimg.NewImage(buf), options, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flattened, err := decodePixels(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []image.Point{{0, 0}, {19, 0}, {0, 19}, {19, 19}, {2, 10}, {10, 2}} {
+		c := flattened.NRGBAAt(p.X, p.Y)
+		assert.True(t, c.R > 245 && c.G > 245 && c.B > 245, "the transparency should be white at %v, not %v", p, c)
+	}
+}
+
+func TestBackground_Response_ClearsAlpha(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropAlpha] = true
+
+	NewBackground().(*background).Response(fc)
+
+	assert.False(t, hasAlpha(fc))
+}
+
+func TestBackground_CanBeMerged_True(t *testing.T) {
+	s := background{}
+	self := &bimg.Options{Background: bimg.Color{R: 240, G: 0, B: 200}}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 200, Type: bimg.JPEG}, self))
+	assert.True(t, s.CanBeMerged(&bimg.Options{Background: bimg.Color{R: 240, G: 0, B: 200}}, self))
+}
+
+func TestBackground_CanBeMerged_False(t *testing.T) {
+	s := background{}
+	self := &bimg.Options{Background: bimg.Color{R: 240, G: 0, B: 200}}
+
+	assert.False(t, s.CanBeMerged(&bimg.Options{Background: bimg.Color{R: 10, G: 153, B: 200}}, self))
+}
+
+func TestBackground_Merge(t *testing.T) {
+	s := background{}
+	self := &bimg.Options{Background: bimg.Color{R: 240, G: 0, B: 200}}
+
+	opt := s.Merge(&bimg.Options{Type: bimg.JPEG}, self)
+
+	assert.Equal(t, &bimg.Options{Type: bimg.JPEG, Background: bimg.Color{R: 240, G: 0, B: 200}}, opt)
+}
+
+func TestBackground_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewBackground, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{"#ffffff"},
+		Err:  false,
+	}, {
+		Msg:  "invalid color",
+		Args: []interface{}{"white"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"#ffffff", "#000000"},
+		Err:  true,
+	}})
+}
+
+func TestBackground_CreateFilter_Black(t *testing.T) {
+	f, _ := NewBackground().CreateFilter([]interface{}{"#000000"})
+
+	assert.NotEqual(t, bimg.ColorBlack, f.(*background).color, "bimg does not flatten on black")
+}
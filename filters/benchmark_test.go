@@ -0,0 +1,51 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// benchmarkRoute processes the landscape image with the filters of the route, as skipper does, reporting the
+// decode/encode passes of the image
+func benchmarkRoute(b *testing.B, specs ...interface{}) {
+	route := createRoute(b, append([]interface{}{NewFinalizeResponse(), []interface{}{}}, specs...)...)
+	passes := 0
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		fc := createContext(b, "GET", "url", imagefiltertest.LandscapeImageFile, map[string]interface{}{})
+		for j := len(route) - 1; j >= 0; j-- {
+			route[j].Response(fc)
+		}
+		passes += Passes(fc)
+	}
+
+	b.ReportMetric(float64(passes)/float64(b.N), "passes/op")
+}
+
+func BenchmarkCrop(b *testing.B) {
+	benchmarkRoute(b, NewCrop(), []interface{}{400.0, 300.0, North})
+}
+
+func BenchmarkOverlayImage(b *testing.B) {
+	benchmarkRoute(b, NewOverlayImage(), []interface{}{"../images/star.png", 0.8, "SE"})
+}
+
+// BenchmarkChain_SinglePass merges all the filters in a single transformation
+func BenchmarkChain_SinglePass(b *testing.B) {
+	benchmarkRoute(b,
+		NewCrop(), []interface{}{400.0, 300.0, North},
+		NewOverlayImage(), []interface{}{"../images/star.png", 0.8, "SE"},
+		NewQuality(), []interface{}{80.0})
+}
+
+// BenchmarkChain_MultiPass needs a transformation for every filter, as none of them can be merged
+func BenchmarkChain_MultiPass(b *testing.B) {
+	benchmarkRoute(b,
+		NewOverlayImage(), []interface{}{"../images/star.png", 0.8, "SE"},
+		NewCrop(), []interface{}{400.0, 300.0, North},
+		NewResize(), []interface{}{800.0, 600.0, "ignoreAspectRatio"})
+}
@@ -57,6 +57,9 @@ func (r *blur) CreateFilter(args []interface{}) (filters.Filter, error) {
 	if err != nil {
 		return nil, err
 	}
+	if f.Sigma <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
 
 	if len(args) == 2 {
 		f.MinAmpl, err = parse.EskipFloatArg(args[1])
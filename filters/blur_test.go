@@ -55,6 +55,22 @@ func TestBlur_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestBlur_CanBeMerged_WithCrop(t *testing.T) {
+	s := blur{}
+	opt := &bimg.Options{Width: 100, Height: 350, Crop: true}
+	self := &bimg.Options{GaussianBlur: bimg.GaussianBlur{Sigma: 0.5, MinAmpl: 1.7}}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestBlur_CanBeMerged_SameParams(t *testing.T) {
+	s := blur{}
+	opt := &bimg.Options{GaussianBlur: bimg.GaussianBlur{Sigma: 0.5, MinAmpl: 1.7}}
+	self := &bimg.Options{GaussianBlur: bimg.GaussianBlur{Sigma: 0.5, MinAmpl: 1.7}}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
 func TestBlur_Merge(t *testing.T) {
 	s := blur{}
 	self := &bimg.Options{GaussianBlur: bimg.GaussianBlur{Sigma: 0.5, MinAmpl: 1.7}}
@@ -73,6 +89,18 @@ func TestBlur_CreateFilter(t *testing.T) {
 		Msg:  "two args",
 		Args: []interface{}{25.0, 35.0},
 		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{25.0},
+		Err:  false,
+	}, {
+		Msg:  "zero sigma",
+		Args: []interface{}{0.0, 35.0},
+		Err:  true,
+	}, {
+		Msg:  "negative sigma",
+		Args: []interface{}{-2.5},
+		Err:  true,
 	}, {
 		Msg:  "type error",
 		Args: []interface{}{"abc", 2.6},
@@ -0,0 +1,82 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// BorderName is the name of the filter
+const BorderName = "border"
+
+type border struct {
+	width int
+	color color.NRGBA
+}
+
+// NewBorder creates a new filter of this type
+func NewBorder() filters.Spec {
+	return &border{}
+}
+
+func (f *border) Name() string {
+	return BorderName
+}
+
+func (f *border) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for border ", f)
+
+	//the canvas is expanded, so nothing of the image is covered by the border
+	bounds := img.Bounds()
+	result := image.NewNRGBA(image.Rect(0, 0, bounds.Dx()+2*f.width, bounds.Dy()+2*f.width))
+	draw.Draw(result, result.Bounds(), image.NewUniform(f.color), image.ZP, draw.Src)
+	draw.Draw(result, bounds.Sub(bounds.Min).Add(image.Pt(f.width, f.width)), img, bounds.Min, draw.Src)
+
+	return result, nil
+}
+
+func (f *border) CanBeMerged(other PixelFilter) bool {
+	o, ok := other.(*border)
+	return ok && o.color == f.color
+}
+
+func (f *border) Merge(other PixelFilter) PixelFilter {
+	//two borders of the same color are a single thicker border
+	return &border{width: other.(*border).width + f.width, color: f.color}
+}
+
+func (f *border) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 2 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	b := &border{}
+
+	b.width, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if b.width <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c, err := parse.EskipColorArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	b.color = color.NRGBA{R: c.R, G: c.G, B: c.B, A: 255}
+
+	return b, nil
+}
+
+func (f *border) Request(ctx filters.FilterContext) {}
+
+func (f *border) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
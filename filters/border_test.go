@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewBorder(t *testing.T) {
+	name := NewBorder().Name()
+	assert.Equal(t, "border", name)
+}
+
+func TestBorder_Name(t *testing.T) {
+	c := border{}
+	assert.Equal(t, "border", c.Name())
+}
+
+func TestBorder_TransformPixels(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	inside := color.NRGBA{R: 10, G: 20, B: 30, A: 255}
+	f := border{width: 2, color: red}
+
+	result, err := f.TransformPixels(uniformImage(inside))
+
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 8, 8), result.Bounds(), "the canvas should be expanded")
+	assert.Equal(t, red, result.NRGBAAt(0, 0))
+	assert.Equal(t, red, result.NRGBAAt(1, 4))
+	assert.Equal(t, red, result.NRGBAAt(7, 6))
+	assert.Equal(t, inside, result.NRGBAAt(2, 2))
+	assert.Equal(t, inside, result.NRGBAAt(5, 5))
+}
+
+func TestBorder_CanBeMerged(t *testing.T) {
+	f := border{width: 2, color: color.NRGBA{R: 255, A: 255}}
+	assert.True(t, f.CanBeMerged(&border{width: 5, color: color.NRGBA{R: 255, A: 255}}))
+	assert.False(t, f.CanBeMerged(&border{width: 2, color: color.NRGBA{G: 255, A: 255}}))
+	assert.False(t, f.CanBeMerged(&negate{active: true}))
+}
+
+func TestBorder_Merge(t *testing.T) {
+	f := border{width: 2, color: color.NRGBA{R: 255, A: 255}}
+	merged := f.Merge(&border{width: 5, color: color.NRGBA{R: 255, A: 255}})
+	assert.Equal(t, &border{width: 7, color: color.NRGBA{R: 255, A: 255}}, merged)
+	assert.Equal(t, 2, f.width)
+}
+
+func TestBorder_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewBorder, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "two args",
+		Args: []interface{}{5.0, "#ff0000"},
+		Err:  false,
+	}, {
+		Msg:  "short color",
+		Args: []interface{}{5.0, "f00"},
+		Err:  false,
+	}, {
+		Msg:  "zero width",
+		Args: []interface{}{0.0, "#ff0000"},
+		Err:  true,
+	}, {
+		Msg:  "invalid color",
+		Args: []interface{}{5.0, "red"},
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{5.0},
+		Err:  true,
+	}})
+}
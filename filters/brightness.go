@@ -0,0 +1,82 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// BrightnessName is the name of the filter
+	BrightnessName = "brightness"
+	maxBrightness  = 3.0
+)
+
+type brightness struct {
+	factor float64
+}
+
+// NewBrightness creates a new filter of this type
+func NewBrightness() filters.Spec {
+	return &brightness{}
+}
+
+func (f *brightness) Name() string {
+	return BrightnessName
+}
+
+func (f *brightness) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for brightness ", f)
+
+	//a factor of 1.0 leaves the image unchanged
+	if f.factor == 1 {
+		return img, nil
+	}
+
+	var table [256]uint8
+	for i := range table {
+		table[i] = clampUint8(float64(i) * f.factor)
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+func (f *brightness) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*brightness)
+	return ok
+}
+
+func (f *brightness) Merge(other PixelFilter) PixelFilter {
+	return &brightness{factor: other.(*brightness).factor * f.factor}
+}
+
+func (f *brightness) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	b := &brightness{}
+
+	b.factor, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if b.factor < 0 {
+		b.factor = 0
+	} else if b.factor > maxBrightness {
+		b.factor = maxBrightness
+	}
+
+	return b, nil
+}
+
+func (f *brightness) Request(ctx filters.FilterContext) {}
+
+func (f *brightness) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewBrightness(t *testing.T) {
+	name := NewBrightness().Name()
+	assert.Equal(t, "brightness", name)
+}
+
+func TestBrightness_Name(t *testing.T) {
+	c := brightness{}
+	assert.Equal(t, "brightness", c.Name())
+}
+
+func TestBrightness_TransformPixels(t *testing.T) {
+	f := brightness{factor: 1.5}
+	img := uniformImage(color.NRGBA{R: 100, G: 200, B: 0, A: 128})
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 150, G: 255, B: 0, A: 128}, result.NRGBAAt(1, 1))
+}
+
+func TestBrightness_TransformPixels_NoOp(t *testing.T) {
+	f := brightness{factor: 1}
+	img := uniformImage(color.NRGBA{R: 100, G: 200, B: 0, A: 255})
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 100, G: 200, B: 0, A: 255}, result.NRGBAAt(1, 1))
+}
+
+func TestBrightness_CanBeMerged_True(t *testing.T) {
+	f := brightness{factor: 1.5}
+	assert.True(t, f.CanBeMerged(&brightness{factor: 1}))
+}
+
+func TestBrightness_CanBeMerged_False(t *testing.T) {
+	f := brightness{factor: 1.5}
+	assert.False(t, f.CanBeMerged(&fakePixelFilter{}))
+}
+
+func TestBrightness_Merge(t *testing.T) {
+	f := brightness{factor: 1.5}
+
+	merged := f.Merge(&brightness{factor: 0.5})
+
+	assert.Equal(t, 0.75, merged.(*brightness).factor)
+	assert.Equal(t, 1.5, f.factor, "the filter itself should not change")
+}
+
+func TestBrightness_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewBrightness, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.2},
+		Err:  false,
+	}, {
+		Msg:  "type error",
+		Args: []interface{}{"bright"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{1.2, 1.3},
+		Err:  true,
+	}})
+}
+
+func TestBrightness_CreateFilter_Clamp(t *testing.T) {
+	f, _ := NewBrightness().CreateFilter([]interface{}{5.0})
+	assert.Equal(t, 3.0, f.(*brightness).factor)
+
+	f, _ = NewBrightness().CreateFilter([]interface{}{-1.0})
+	assert.Equal(t, 0.0, f.(*brightness).factor)
+}
+
+func uniformImage(c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
@@ -0,0 +1,48 @@
+package filters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zalando/skipper/filters"
+)
+
+var (
+	processingTimeoutMutex sync.RWMutex
+	processingTimeout      time.Duration
+)
+
+// SetProcessingTimeout limits how long the image of a request can be processed, 0 disabling the limit. libvips
+// cannot be interrupted, so the processing stops before the next transformation of the image
+func SetProcessingTimeout(timeout time.Duration) {
+	processingTimeoutMutex.Lock()
+	defer processingTimeoutMutex.Unlock()
+	processingTimeout = timeout
+}
+
+// setDeadline sets the deadline of the processing of the image, when the response is read
+func setDeadline(ctx filters.FilterContext) {
+	processingTimeoutMutex.RLock()
+	timeout := processingTimeout
+	processingTimeoutMutex.RUnlock()
+
+	if timeout > 0 {
+		ctx.StateBag()[skropDeadline] = time.Now().Add(timeout)
+	}
+}
+
+// checkCanceled tells if the image still needs to be processed: the client can be gone, or the processing can
+// have lasted too long
+func checkCanceled(ctx filters.FilterContext) error {
+	if err := ctx.Request().Context().Err(); err != nil {
+		return fmt.Errorf("processing stopped: %w", err)
+	}
+
+	if deadline, ok := ctx.StateBag()[skropDeadline].(time.Time); ok && time.Now().After(deadline) {
+		return fmt.Errorf("processing stopped: %w", context.DeadlineExceeded)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestHandleImageResponse_Canceled(t *testing.T) {
+	fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	fc.FRequest = fc.FRequest.WithContext(canceled)
+
+	start := time.Now()
+	err := HandleImageResponse(fc, &resize{width: 100, height: 100})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, time.Since(start) < time.Second, "the processing should stop right away")
+	assert.Equal(t, 0, Passes(fc), "the image should not be transformed")
+	assert.Equal(t, http.StatusGatewayTimeout, fc.Response().StatusCode)
+}
+
+func TestFinalizeResponse_Timeout(t *testing.T) {
+	SetProcessingTimeout(time.Nanosecond)
+	defer SetProcessingTimeout(0)
+
+	fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+	initOnce(fc)
+	time.Sleep(time.Millisecond)
+
+	FinalizeResponse(fc)
+
+	assert.Equal(t, 0, Passes(fc))
+	assert.Equal(t, http.StatusGatewayTimeout, fc.Response().StatusCode)
+}
+
+func TestCheckCanceled(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+	assert.Nil(t, checkCanceled(fc))
+
+	fc.FStateBag[skropDeadline] = time.Now().Add(time.Hour)
+	assert.Nil(t, checkCanceled(fc))
+
+	fc.FStateBag[skropDeadline] = time.Now().Add(-time.Second)
+	assert.True(t, errors.Is(checkCanceled(fc), context.DeadlineExceeded))
+}
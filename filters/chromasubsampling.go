@@ -0,0 +1,123 @@
+package filters
+
+import (
+	"errors"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ChromaSubsamplingName is the name of the filter
+const ChromaSubsamplingName = "chromaSubsampling"
+
+const (
+	// Subsampling444 keeps the colors of every pixel
+	Subsampling444 = "4:4:4"
+	// Subsampling422 halves the horizontal resolution of the colors
+	Subsampling422 = "4:2:2"
+	// Subsampling420 halves the horizontal and the vertical resolution of the colors
+	Subsampling420 = "4:2:0"
+	// libvips turns the chroma subsampling off from this JPEG quality on
+	noSubsamplingQuality = 90
+)
+
+// ErrSubsampling422NotSupported is returned for the 4:2:2 chroma subsampling, which libvips cannot produce
+var ErrSubsampling422NotSupported = errors.New("the 4:2:2 chroma subsampling is not supported, only 4:4:4 and 4:2:0 are")
+
+// chromaSubsampling chooses the chroma subsampling of the JPEG images. bimg has no option for it and libvips
+// turns the subsampling off from the quality 90 on, so the quality is raised to 90 for 4:4:4 and lowered to 89
+// for 4:2:0 when the image is encoded
+type chromaSubsampling struct {
+	mode string
+}
+
+// NewChromaSubsampling creates a new filter of this type
+func NewChromaSubsampling() filters.Spec {
+	return &chromaSubsampling{}
+}
+
+func (f *chromaSubsampling) Name() string {
+	return ChromaSubsamplingName
+}
+
+func (f *chromaSubsampling) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for chroma subsampling ", f)
+
+	//the quality is only adjusted once the image is encoded
+	return &bimg.Options{}, nil
+}
+
+func (f *chromaSubsampling) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return true
+}
+
+func (f *chromaSubsampling) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	return other
+}
+
+func (f *chromaSubsampling) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	mode, err := parse.EskipStringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case Subsampling444, Subsampling420:
+		return &chromaSubsampling{mode: mode}, nil
+	case Subsampling422:
+		return nil, ErrSubsampling422NotSupported
+	default:
+		return nil, filters.ErrInvalidFilterParameters
+	}
+}
+
+func (f *chromaSubsampling) Request(ctx filters.FilterContext) {}
+
+func (f *chromaSubsampling) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	ctx.StateBag()[skropChromaSubsampling] = f.mode
+}
+
+// checkChromaSubsampling adjusts the quality of the JPEG images to the chroma subsampling
+func checkChromaSubsampling(ctx filters.FilterContext, image *bimg.Image, opts *bimg.Options) {
+	mode, ok := ctx.StateBag()[skropChromaSubsampling].(string)
+	if !ok {
+		return
+	}
+
+	imageType := opts.Type
+	if imageType == bimg.UNKNOWN {
+		imageType = bimg.DetermineImageType(image.Image())
+	}
+
+	if imageType != bimg.JPEG {
+		log.Warnf("The chroma subsampling is ignored, as the image is encoded as %s instead of JPEG", bimg.ImageTypeName(imageType))
+		return
+	}
+
+	quality := opts.Quality
+	if quality == 0 {
+		quality = Quality
+	}
+
+	if mode == Subsampling444 && quality < noSubsamplingQuality {
+		quality = noSubsamplingQuality
+	} else if mode == Subsampling420 && quality >= noSubsamplingQuality {
+		quality = noSubsamplingQuality - 1
+	}
+
+	//the default quality is adjusted silently, but the one asked for is not expected to change
+	if opts.Quality != 0 && opts.Quality != quality {
+		log.Warnf("The quality %d is changed to %d for the %s chroma subsampling", opts.Quality, quality, mode)
+	}
+	opts.Quality = quality
+}
@@ -0,0 +1,117 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewChromaSubsampling(t *testing.T) {
+	name := NewChromaSubsampling().Name()
+	assert.Equal(t, "chromaSubsampling", name)
+}
+
+func TestChromaSubsampling_CanBeMerged(t *testing.T) {
+	s := chromaSubsampling{mode: Subsampling444}
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 100, Quality: 80}, &bimg.Options{}))
+
+	opt := s.Merge(&bimg.Options{Width: 100, Quality: 80}, &bimg.Options{})
+	assert.Equal(t, &bimg.Options{Width: 100, Quality: 80}, opt)
+}
+
+func TestChromaSubsampling_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewChromaSubsampling, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "4:4:4",
+		Args: []interface{}{"4:4:4"},
+		Err:  false,
+	}, {
+		Msg:  "4:2:0",
+		Args: []interface{}{"4:2:0"},
+		Err:  false,
+	}, {
+		Msg:  "4:2:2",
+		Args: []interface{}{"4:2:2"},
+		Err:  true,
+	}, {
+		Msg:  "invalid mode",
+		Args: []interface{}{"4:1:1"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"4:4:4", "4:2:0"},
+		Err:  true,
+	}})
+
+	_, err := NewChromaSubsampling().CreateFilter([]interface{}{"4:2:2"})
+	assert.Equal(t, ErrSubsampling422NotSupported, err)
+}
+
+func TestCheckChromaSubsampling(t *testing.T) {
+	image := imagefiltertest.LandscapeImage()
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	fc.FStateBag[skropChromaSubsampling] = Subsampling444
+	opts := &bimg.Options{Quality: 70}
+	checkChromaSubsampling(fc, image, opts)
+	assert.Equal(t, 90, opts.Quality)
+
+	opts = &bimg.Options{Quality: 95}
+	checkChromaSubsampling(fc, image, opts)
+	assert.Equal(t, 95, opts.Quality)
+
+	fc.FStateBag[skropChromaSubsampling] = Subsampling420
+	opts = &bimg.Options{}
+	checkChromaSubsampling(fc, image, opts)
+	assert.Equal(t, 89, opts.Quality, "the default quality turns the subsampling off")
+
+	opts = &bimg.Options{Quality: 60}
+	checkChromaSubsampling(fc, image, opts)
+	assert.Equal(t, 60, opts.Quality)
+
+	opts = &bimg.Options{Quality: 95, Type: bimg.PNG}
+	checkChromaSubsampling(fc, image, opts)
+	assert.Equal(t, 95, opts.Quality, "only the JPEG images are subsampled")
+}
+
+func TestCheckChromaSubsampling_WarnsOfChangedQuality(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	image := imagefiltertest.LandscapeImage()
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropChromaSubsampling] = Subsampling444
+
+	checkChromaSubsampling(fc, image, &bimg.Options{})
+	assert.Nil(t, hook.LastEntry(), "the default quality is changed without warning")
+
+	checkChromaSubsampling(fc, image, &bimg.Options{Quality: 70})
+	if assert.NotNil(t, hook.LastEntry()) {
+		assert.Equal(t, log.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, "The quality 70 is changed to 90 for the 4:4:4 chroma subsampling", hook.LastEntry().Message)
+	}
+}
+
+func TestChromaSubsampling_Response(t *testing.T) {
+	size := func(mode string) int {
+		fc := createContext(t, "GET", "url", imagefiltertest.PortraitImageFile, map[string]interface{}{})
+
+		f, _ := NewChromaSubsampling().CreateFilter([]interface{}{mode})
+		f.Response(fc)
+		FinalizeResponse(fc)
+
+		return len(readResultImage(fc.Response().Body, t).Image())
+	}
+
+	full, subsampled := size(Subsampling444), size(Subsampling420)
+
+	assert.NotZero(t, subsampled)
+	assert.True(t, subsampled < full, "the subsampled image should be smaller, %d >= %d", subsampled, full)
+}
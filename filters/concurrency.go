@@ -0,0 +1,64 @@
+package filters
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrTooBusy is returned when the image cannot be processed before the concurrency timeout, as too many images
+// are processed concurrently
+var ErrTooBusy = errors.New("processing failed, too many images are processed concurrently")
+
+var (
+	concurrencyMutex sync.RWMutex
+	//every libvips operation holds a slot while it runs
+	vipsSlots          = make(chan struct{}, runtime.NumCPU())
+	concurrencyTimeout time.Duration
+)
+
+// SetMaxConcurrency limits the number of libvips operations running concurrently, the others waiting for a free
+// slot. The default is the number of CPUs, values lower than 1 are ignored. The operations already running keep
+// the slots of the previous limit
+func SetMaxConcurrency(n int) {
+	if n < 1 {
+		return
+	}
+
+	concurrencyMutex.Lock()
+	defer concurrencyMutex.Unlock()
+	vipsSlots = make(chan struct{}, n)
+}
+
+// SetConcurrencyTimeout limits how long an operation waits for a free slot before failing with ErrTooBusy.
+// By default, or with a timeout of 0, the operations wait as long as needed
+func SetConcurrencyTimeout(timeout time.Duration) {
+	concurrencyMutex.Lock()
+	defer concurrencyMutex.Unlock()
+	concurrencyTimeout = timeout
+}
+
+// acquireSlot waits for a free slot to run a libvips operation, and returns the func releasing it
+func acquireSlot() (func(), error) {
+	concurrencyMutex.RLock()
+	slots, timeout := vipsSlots, concurrencyTimeout
+	concurrencyMutex.RUnlock()
+
+	release := func() { <-slots }
+
+	if timeout <= 0 {
+		slots <- struct{}{}
+		return release, nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-timer.C:
+		return nil, ErrTooBusy
+	}
+}
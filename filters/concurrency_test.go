@@ -0,0 +1,89 @@
+package filters
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+)
+
+func withConcurrency(t *testing.T, n int, timeout time.Duration) {
+	slots, previousTimeout := vipsSlots, concurrencyTimeout
+	t.Cleanup(func() {
+		vipsSlots, concurrencyTimeout = slots, previousTimeout
+	})
+
+	SetMaxConcurrency(n)
+	SetConcurrencyTimeout(timeout)
+}
+
+func TestAcquireSlot_Serializes(t *testing.T) {
+	const limit = 2
+	withConcurrency(t, limit, 0)
+
+	var running, maxRunning, done int32
+	var wg sync.WaitGroup
+	for i := 0; i < limit+1; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := acquireSlot()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+
+			n := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(limit), maxRunning, "no more than the limit should run concurrently")
+	assert.Equal(t, int32(limit+1), done, "the request over the limit should wait and then run")
+}
+
+func TestAcquireSlot_Timeout(t *testing.T) {
+	withConcurrency(t, 1, 10*time.Millisecond)
+
+	release, err := acquireSlot()
+	assert.Nil(t, err)
+
+	_, err = acquireSlot()
+	assert.Equal(t, ErrTooBusy, err)
+
+	release()
+	release, err = acquireSlot()
+	assert.Nil(t, err)
+	release()
+}
+
+func TestProcessImage_HoldsSlot(t *testing.T) {
+	withConcurrency(t, 1, 10*time.Millisecond)
+
+	release, err := acquireSlot()
+	assert.Nil(t, err)
+	defer release()
+
+	_, err = processImage(bimg.NewImage([]byte("not processed")), bimg.Options{})
+	assert.Equal(t, ErrTooBusy, err, "the libvips calls should wait for a free slot")
+}
+
+func TestSetMaxConcurrency_Invalid(t *testing.T) {
+	withConcurrency(t, 3, 0)
+
+	SetMaxConcurrency(0)
+	assert.Equal(t, 3, cap(vipsSlots))
+}
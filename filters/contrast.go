@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// ContrastName is the name of the filter
+	ContrastName = "contrast"
+	maxContrast  = 3.0
+)
+
+type contrast struct {
+	factor float64
+}
+
+// NewContrast creates a new filter of this type
+func NewContrast() filters.Spec {
+	return &contrast{}
+}
+
+func (f *contrast) Name() string {
+	return ContrastName
+}
+
+func (f *contrast) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for contrast ", f)
+
+	//a factor of 1.0 leaves the image unchanged
+	if f.factor == 1 {
+		return img, nil
+	}
+
+	//the values are stretched or compressed around the middle gray
+	var table [256]uint8
+	for i := range table {
+		table[i] = clampUint8((float64(i)-128)*f.factor + 128)
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+func (f *contrast) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*contrast)
+	return ok
+}
+
+func (f *contrast) Merge(other PixelFilter) PixelFilter {
+	return &contrast{factor: other.(*contrast).factor * f.factor}
+}
+
+func (f *contrast) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &contrast{}
+
+	c.factor, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if c.factor < 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	} else if c.factor > maxContrast {
+		c.factor = maxContrast
+	}
+
+	return c, nil
+}
+
+func (f *contrast) Request(ctx filters.FilterContext) {}
+
+func (f *contrast) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
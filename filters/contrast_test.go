@@ -0,0 +1,88 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewContrast(t *testing.T) {
+	name := NewContrast().Name()
+	assert.Equal(t, "contrast", name)
+}
+
+func TestContrast_Name(t *testing.T) {
+	c := contrast{}
+	assert.Equal(t, "contrast", c.Name())
+}
+
+func TestContrast_TransformPixels(t *testing.T) {
+	f := contrast{factor: 2}
+	img := uniformImage(color.NRGBA{R: 100, G: 128, B: 200, A: 255})
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 72, G: 128, B: 255, A: 255}, result.NRGBAAt(0, 0))
+}
+
+func TestContrast_TransformPixels_NoOp(t *testing.T) {
+	f := contrast{factor: 1}
+	img, err := decodePixels(imagefiltertest.PNGImage().Image())
+	assert.Nil(t, err)
+	original := append([]uint8(nil), img.Pix...)
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, original, result.Pix)
+}
+
+func TestContrast_CanBeMerged_True(t *testing.T) {
+	f := contrast{factor: 1.5}
+	assert.True(t, f.CanBeMerged(&contrast{factor: 1}))
+}
+
+func TestContrast_CanBeMerged_False(t *testing.T) {
+	f := contrast{factor: 1.5}
+	assert.False(t, f.CanBeMerged(&brightness{factor: 1.5}))
+}
+
+func TestContrast_Merge(t *testing.T) {
+	f := contrast{factor: 1.5}
+
+	merged := f.Merge(&contrast{factor: 2})
+
+	assert.Equal(t, 3.0, merged.(*contrast).factor)
+}
+
+func TestContrast_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewContrast, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.2},
+		Err:  false,
+	}, {
+		Msg:  "negative factor",
+		Args: []interface{}{-0.5},
+		Err:  true,
+	}, {
+		Msg:  "type error",
+		Args: []interface{}{"high"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{1.2, 1.3},
+		Err:  true,
+	}})
+}
+
+func TestContrast_CreateFilter_Clamp(t *testing.T) {
+	f, _ := NewContrast().CreateFilter([]interface{}{5.0})
+	assert.Equal(t, 3.0, f.(*contrast).factor)
+}
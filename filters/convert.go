@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"errors"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ConvertName is the name of the filter
+const ConvertName = "convert"
+
+// ErrAVIFNotSupported is returned when converting the image to AVIF, which is not one of the types of bimg
+var ErrAVIFNotSupported = errors.New("the AVIF format is not supported, it needs a bimg version supporting it")
+
+// the output formats supported by the filter
+var convertFormats = map[string]bimg.ImageType{
+	"jpeg": bimg.JPEG,
+	"png":  bimg.PNG,
+	"webp": bimg.WEBP,
+	"tiff": bimg.TIFF,
+}
+
+type convert struct {
+	imageType bimg.ImageType
+}
+
+// NewConvert creates a new filter of this type
+func NewConvert() filters.Spec {
+	return &convert{}
+}
+
+func (f *convert) Name() string {
+	return ConvertName
+}
+
+func (f *convert) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for convert ", f)
+
+	return &bimg.Options{Type: f.imageType}, nil
+}
+
+func (f *convert) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//only the output type is changed, so the filter can always be merged, the last conversion winning
+	return true
+}
+
+func (f *convert) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Type = self.Type
+	return other
+}
+
+func (f *convert) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	format, err := parse.EskipStringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "avif" {
+		return nil, ErrAVIFNotSupported
+	}
+
+	imageType, ok := convertFormats[format]
+	if !ok || !bimg.IsTypeSupportedSave(imageType) {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &convert{imageType: imageType}, nil
+}
+
+func (f *convert) Request(ctx filters.FilterContext) {}
+
+func (f *convert) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	setImageTypeHeaders(ctx, f.imageType)
+}
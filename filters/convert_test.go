@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewConvert(t *testing.T) {
+	name := NewConvert().Name()
+	assert.Equal(t, "convert", name)
+}
+
+func TestConvert_CreateOptions(t *testing.T) {
+	c := convert{imageType: bimg.WEBP}
+	options, _ := c.CreateOptions(nil)
+	assert.Equal(t, bimg.WEBP, options.Type)
+}
+
+func TestConvert_CanBeMerged(t *testing.T) {
+	c := convert{imageType: bimg.WEBP}
+	self := &bimg.Options{Type: bimg.WEBP}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Width: 100, Crop: true}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Type: bimg.PNG}, self))
+}
+
+func TestConvert_Merge(t *testing.T) {
+	c := convert{imageType: bimg.WEBP}
+	self := &bimg.Options{Type: bimg.WEBP}
+
+	opt := c.Merge(&bimg.Options{Width: 100, Type: bimg.PNG}, self)
+
+	assert.Equal(t, bimg.WEBP, opt.Type)
+	assert.Equal(t, 100, opt.Width)
+}
+
+func TestConvert_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewConvert, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "jpeg",
+		Args: []interface{}{"jpeg"},
+		Err:  false,
+	}, {
+		Msg:  "png",
+		Args: []interface{}{"png"},
+		Err:  false,
+	}, {
+		Msg:  "webp",
+		Args: []interface{}{"webp"},
+		Err:  false,
+	}, {
+		Msg:  "tiff",
+		Args: []interface{}{"tiff"},
+		Err:  false,
+	}, {
+		Msg:  "format not supported",
+		Args: []interface{}{"gif"},
+		Err:  true,
+	}, {
+		Msg:  "not a string",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"jpeg", "webp"},
+		Err:  true,
+	}})
+}
+
+func TestConvert_CreateFilter_AVIF(t *testing.T) {
+	_, err := NewConvert().CreateFilter([]interface{}{"avif"})
+	assert.Equal(t, ErrAVIFNotSupported, err)
+}
+
+func TestConvert_Response(t *testing.T) {
+	for format, mime := range map[string]string{
+		"jpeg": "image/jpeg",
+		"png":  "image/png",
+		"webp": "image/webp",
+		"tiff": "image/tiff",
+	} {
+		fc := createDefaultContext(t, "http://localhost:9090/images/bag.png")
+		fc.Request().RequestURI = "/images/bag.png"
+		fc.FStateBag[hasMergedFilters] = false
+
+		f, _ := NewConvert().CreateFilter([]interface{}{format})
+		f.Response(fc)
+		FinalizeResponse(fc)
+
+		result := readResultImage(fc.Response().Body, t)
+
+		assert.Equal(t, mime, "image/"+bimg.DetermineImageTypeName(result.Image()), format)
+		assert.Equal(t, mime, fc.Response().Header.Get("Content-Type"), format)
+	}
+}
@@ -79,16 +79,20 @@ func (f *convertImageType) Response(ctx filters.FilterContext) {
 		return
 	}
 
+	setImageTypeHeaders(ctx, f.imageType)
+}
+
+// setImageTypeHeaders sets the content type and the file name of the response to the ones of the image type
+func setImageTypeHeaders(ctx filters.FilterContext, imageType bimg.ImageType) {
 	resp := ctx.Response()
 
-	fileType := bimg.ImageTypeName(f.imageType)
+	fileType := bimg.ImageTypeName(imageType)
 
 	contentType := fmt.Sprintf("image/%s", fileType)
 	contentDisp := fmt.Sprintf("inline;filename=%s.%s", extractFileName(ctx), fileType)
 
 	resp.Header.Set("Content-Type", contentType)
 	resp.Header.Set("Content-Disposition", contentDisp)
-
 }
 
 func extractFileName(ctx filters.FilterContext) string {
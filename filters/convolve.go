@@ -0,0 +1,123 @@
+package filters
+
+import (
+	"image"
+	"math"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ConvolveName is the name of the filter
+const ConvolveName = "convolve"
+
+type convolve struct {
+	kernel [9]float64
+	scale  float64
+	offset float64
+}
+
+// NewConvolve creates a new filter of this type
+func NewConvolve() filters.Spec {
+	return &convolve{}
+}
+
+func (f *convolve) Name() string {
+	return ConvolveName
+}
+
+func (f *convolve) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for convolve ", f)
+
+	//the colors are computed from the original pixels, the edges being extended, and the transparency is kept
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var sums [3]float64
+			for k, weight := range f.kernel {
+				sx := clampInt(x+k%3-1, 0, width-1)
+				sy := clampInt(y+k/3-1, 0, height-1)
+				i := sy*img.Stride + sx*4
+				sums[0] += float64(img.Pix[i]) * weight
+				sums[1] += float64(img.Pix[i+1]) * weight
+				sums[2] += float64(img.Pix[i+2]) * weight
+			}
+
+			i := y*img.Stride + x*4
+			o := y*result.Stride + x*4
+			result.Pix[o] = clampUint8(sums[0]/f.scale + f.offset)
+			result.Pix[o+1] = clampUint8(sums[1]/f.scale + f.offset)
+			result.Pix[o+2] = clampUint8(sums[2]/f.scale + f.offset)
+			result.Pix[o+3] = img.Pix[i+3]
+		}
+	}
+
+	return result, nil
+}
+
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+// CanBeMerged refuses merging, as two kernels are only equivalent to a bigger one
+func (f *convolve) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *convolve) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *convolve) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 3 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &convolve{}
+
+	values, err := parse.EskipStringArrayArg(args[0])
+	if err != nil {
+		return nil, parse.AtArg(err, ConvolveName, 1, "kernel")
+	}
+	if len(values) != len(c.kernel) {
+		log.Errorf("Failed to create the convolve filter, the kernel has %d values instead of 9", len(values))
+		return nil, filters.ErrInvalidFilterParameters
+	}
+	for i, value := range values {
+		c.kernel[i], err = strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(c.kernel[i]) || math.IsInf(c.kernel[i], 0) {
+			log.Errorf("Failed to create the convolve filter, the value %q of the kernel is not a number", value)
+			return nil, filters.ErrInvalidFilterParameters
+		}
+	}
+
+	if c.scale, err = parse.EskipNumberArg(args[1]); err != nil {
+		return nil, parse.AtArg(err, ConvolveName, 2, "scale")
+	}
+	if c.scale == 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	if c.offset, err = parse.EskipNumberArg(args[2]); err != nil {
+		return nil, parse.AtArg(err, ConvolveName, 3, "offset")
+	}
+
+	return c, nil
+}
+
+func (f *convolve) Request(ctx filters.FilterContext) {}
+
+func (f *convolve) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,99 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// checkerboard is an image of alternating colors, with some transparency
+func checkerboard() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+			if (x+y)%2 == 0 {
+				c = color.NRGBA{R: 10, G: 150, B: 250, A: 128}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestNewConvolve(t *testing.T) {
+	name := NewConvolve().Name()
+	assert.Equal(t, "convolve", name)
+}
+
+func TestConvolve_TransformPixels_Identity(t *testing.T) {
+	f := &convolve{kernel: [9]float64{0, 0, 0, 0, 1, 0, 0, 0, 0}, scale: 1}
+
+	result, err := f.TransformPixels(checkerboard())
+
+	assert.Nil(t, err)
+	assert.Equal(t, checkerboard(), result)
+}
+
+func TestConvolve_TransformPixels_Blur(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	f := &convolve{kernel: [9]float64{1, 1, 1, 1, 1, 1, 1, 1, 1}, scale: 9}
+
+	result, err := f.TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result, "the average of a uniform image is the same image")
+}
+
+func TestConvolve_TransformPixels_Offset(t *testing.T) {
+	c := color.NRGBA{R: 100, G: 100, B: 100, A: 255}
+	f := &convolve{kernel: [9]float64{0, 0, 0, 0, 1, 0, 0, 0, 0}, scale: 2, offset: 10}
+
+	result, err := f.TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 60, G: 60, B: 60, A: 255}, result.NRGBAAt(2, 2))
+}
+
+func TestConvolve_CanBeMerged(t *testing.T) {
+	f := convolve{scale: 1}
+	assert.False(t, f.CanBeMerged(&convolve{scale: 1}))
+}
+
+func TestConvolve_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewConvolve, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "sharpen kernel",
+		Args: []interface{}{"0,-1,0,-1,5,-1,0,-1,0", 1.0, 0.0},
+		Err:  false,
+	}, {
+		Msg:  "too few values",
+		Args: []interface{}{"0,-1,0,-1,5,-1,0,-1", 1.0, 0.0},
+		Err:  true,
+	}, {
+		Msg:  "not a number in the kernel",
+		Args: []interface{}{"0,-1,0,-1,a,-1,0,-1,0", 1.0, 0.0},
+		Err:  true,
+	}, {
+		Msg:  "zero scale",
+		Args: []interface{}{"0,-1,0,-1,5,-1,0,-1,0", 0.0, 0.0},
+		Err:  true,
+	}, {
+		Msg:  "missing offset",
+		Args: []interface{}{"0,-1,0,-1,5,-1,0,-1,0", 1.0},
+		Err:  true,
+	}})
+}
+
+func TestConvolve_CreateFilter_Kernel(t *testing.T) {
+	f, err := NewConvolve().CreateFilter([]interface{}{"0, -1, 0, -1, 5, -1, 0, -1, 0", 1.0, 0.0})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &convolve{kernel: [9]float64{0, -1, 0, -1, 5, -1, 0, -1, 0}, scale: 1}, f)
+}
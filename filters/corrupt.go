@@ -0,0 +1,49 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrCorruptImage is returned when the original image cannot be decoded, like a truncated upload. The image is
+// rejected with the status 415, as its content is not a valid image of its type
+var ErrCorruptImage = errors.New("processing failed, the image is corrupt or truncated")
+
+// checkDecodable reads the size of the image from its header, so the images missing their dimensions are
+// rejected before libvips processes them
+func checkDecodable(buf []byte) (bimg.ImageSize, error) {
+	size, err := readImageSize(bimg.NewImage(buf))
+	if err != nil {
+		return size, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+	if size.Width <= 0 || size.Height <= 0 {
+		return size, fmt.Errorf("%w: the image has no pixels", ErrCorruptImage)
+	}
+	return size, nil
+}
+
+// recoverCorrupt turns the panic of bimg while decoding the image into an ErrCorruptImage error, so a corrupt
+// image does not stop the process. It needs to be deferred by the functions calling bimg
+func recoverCorrupt(err *error) {
+	if r := recover(); r != nil {
+		log.Errorf("bimg panicked while processing the image: %v\n%s", r, debug.Stack())
+		*err = fmt.Errorf("%w: %v", ErrCorruptImage, r)
+	}
+}
+
+// processImage transforms the image with bimg, holding a concurrency slot and recovering from its panics.
+// Every libvips transformation goes through it
+func processImage(image *bimg.Image, o bimg.Options) (buf []byte, err error) {
+	release, err := acquireSlot()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer recoverCorrupt(&err)
+	return image.Process(o)
+}
@@ -0,0 +1,72 @@
+package filters
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+	"github.com/zalando-stups/skrop/messages"
+)
+
+func TestCheckDecodable(t *testing.T) {
+	buf, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+
+	size, err := checkDecodable(buf)
+	assert.Nil(t, err)
+	assert.True(t, size.Width > 0)
+
+	_, err = checkDecodable(buf[:20])
+	assert.True(t, errors.Is(err, ErrCorruptImage))
+	_, err = checkDecodable(nil)
+	assert.True(t, errors.Is(err, ErrCorruptImage))
+}
+
+func TestInitResponse_TruncatedJPEG(t *testing.T) {
+	buf, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(buf[:100]))
+
+	initResponse(fc)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, fc.Response().StatusCode)
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, messages.ErrorCorruptImage, string(body))
+	assert.Nil(t, fc.FStateBag[skropImage])
+}
+
+func TestHandleImageResponse_ZeroByteBody(t *testing.T) {
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(nil))
+
+	err := HandleImageResponse(fc, &resize{width: 100, height: 100})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, fc.Response().StatusCode)
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, messages.Error404, string(body))
+}
+
+func TestInitResponse_RecoversFromPanic(t *testing.T) {
+	read := readImageSize
+	defer func() { readImageSize = read }()
+	readImageSize = func(image *bimg.Image) (size bimg.ImageSize, err error) {
+		defer recoverCorrupt(&err)
+		panic("index out of range")
+	}
+
+	fc := createContext(t, "GET", "url", imagefiltertest.LandscapeImageFile, map[string]interface{}{})
+
+	assert.NotPanics(t, func() { initResponse(fc) })
+	assert.Equal(t, http.StatusUnsupportedMediaType, fc.Response().StatusCode)
+}
+
+func TestProcessImage_RecoversFromPanic(t *testing.T) {
+	_, err := processImage(nil, bimg.Options{})
+
+	assert.True(t, errors.Is(err, ErrCorruptImage))
+}
@@ -1,6 +1,9 @@
 package filters
 
 import (
+	"fmt"
+	"math"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/zalando-stups/skrop/parse"
 	"github.com/zalando/skipper/filters"
@@ -11,9 +14,14 @@ import (
 const CropName = "crop"
 
 type crop struct {
-	width    int
-	height   int
-	cropType string
+	width         int
+	height        int
+	widthPercent  float64
+	heightPercent float64
+	cropType      string
+	strategy      string
+	focusX        float64
+	focusY        float64
 }
 
 // NewCrop creates a new filter of this type
@@ -25,19 +33,70 @@ func (f *crop) Name() string {
 	return CropName
 }
 
-func (f *crop) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+func (f *crop) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
 	log.Debug("Create options for crop ", f)
 
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := f.width, f.height
+
+	//the percentages are of the size of the image
+	if f.widthPercent > 0 {
+		width = scaleDimension(size.Width, f.widthPercent/100)
+	}
+	if f.heightPercent > 0 {
+		height = scaleDimension(size.Height, f.heightPercent/100)
+	}
+
+	if width > size.Width || height > size.Height {
+		if strictCropBounds {
+			return nil, fmt.Errorf("the crop of %dx%d exceeds the image of %dx%d", width, height, size.Width, size.Height)
+		}
+		log.Debugf("The crop of %dx%d is clamped to the image of %dx%d", width, height, size.Width, size.Height)
+		width = int(math.Min(float64(width), float64(size.Width)))
+		height = int(math.Min(float64(height), float64(size.Height)))
+	}
+
+	if f.cropType == Smart && f.strategy == Entropy {
+		return entropyCropOptions(imageContext.Image, size, width, height)
+	}
+
+	if f.cropType == Focus {
+		return focusCropOptions(size, width, height, f.focusX, f.focusY), nil
+	}
+
 	return &bimg.Options{
-		Width:   f.width,
-		Height:  f.height,
+		Width:   width,
+		Height:  height,
 		Gravity: cropTypeToGravity[f.cropType],
 		Crop:    true}, nil
 }
 
 func (f *crop) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
-	return (other.Width == 0 && other.Height == 0 && !other.Crop) ||
-		(other.Width == self.Width && other.Height == self.Height && other.Crop == self.Crop)
+	//the entropy is found on the pixels as they are stored, so a pending rotation or flip needs to be applied first
+	if f.cropType == Smart && f.strategy == Entropy && (other.Rotate != 0 || other.Flip || other.Flop) {
+		return false
+	}
+
+	//the entropy and the focus crops resize and extract, so they need options not resizing, cropping or
+	//extracting anything
+	if self.AreaWidth > 0 {
+		return orientationCanBeMerged(other) && !other.Crop && !other.Embed && !other.Trim &&
+			other.Top == 0 && other.Left == 0
+	}
+	return cropCanBeMerged(other, self)
+}
+
+// cropCanBeMerged tells if the crop can replace the options merged so far. A crop of a different size or gravity
+// needs to be applied on the result of the previous crop, so it is not merged. bimg does only one of crop and trim,
+// so a pending trim or extraction is not merged either
+func cropCanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return !other.Trim && !extractPending(other) && ((other.Width == 0 && other.Height == 0 && !other.Crop) ||
+		(other.Width == self.Width && other.Height == self.Height && other.Crop == self.Crop &&
+			other.Gravity == self.Gravity))
 }
 
 func (f *crop) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
@@ -45,41 +104,128 @@ func (f *crop) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
 	other.Height = self.Height
 	other.Gravity = self.Gravity
 	other.Crop = self.Crop
+	if self.AreaWidth > 0 {
+		other.Force = self.Force
+		other.Top = self.Top
+		other.Left = self.Left
+		other.AreaWidth = self.AreaWidth
+		other.AreaHeight = self.AreaHeight
+	}
 	return other
 }
 
 func (f *crop) CreateFilter(args []interface{}) (filters.Filter, error) {
 	var err error
 
-	if len(args) < 2 || len(args) > 3 {
+	if len(args) < 2 || len(args) > 5 {
 		return nil, filters.ErrInvalidFilterParameters
 	}
 
-	c := &crop{cropType: Center}
+	c := &crop{cropType: Center, strategy: Attention}
 
-	c.width, err = parse.EskipIntArg(args[0])
+	c.width, c.widthPercent, err = parseCropDimension(args[0])
 
 	if err != nil {
-		return nil, err
+		return nil, parse.AtArg(err, CropName, 1, "width")
 	}
 
-	c.height, err = parse.EskipIntArg(args[1])
+	c.height, c.heightPercent, err = parseCropDimension(args[1])
 
 	if err != nil {
-		return nil, err
+		return nil, parse.AtArg(err, CropName, 2, "height")
 	}
 
-	if len(args) == 3 {
-		if cropType, ok := args[2].(string); ok && cropTypes[cropType] {
-			c.cropType = cropType
-		} else {
+	if len(args) >= 3 {
+		if c.cropType, err = parse.EskipEnumArg(args[2], cropTypes); err != nil {
+			return nil, parse.AtArg(err, CropName, 3, "type")
+		}
+		if c.cropType == Smart && !smartCropSupported() {
+			log.Error(ErrSmartCropNotSupported.Error())
+			return nil, ErrSmartCropNotSupported
+		}
+	}
+
+	//the focal point is only used by the focus crop
+	if c.cropType == Focus || len(args) == 5 {
+		if c.cropType != Focus || len(args) != 5 {
 			return nil, filters.ErrInvalidFilterParameters
 		}
+		if c.focusX, err = parseFocalCoordinate(args[3]); err != nil {
+			return nil, parse.AtArg(err, CropName, 4, "focusX")
+		}
+		if c.focusY, err = parseFocalCoordinate(args[4]); err != nil {
+			return nil, parse.AtArg(err, CropName, 5, "focusY")
+		}
+		return c, nil
+	}
+
+	//the strategy is only used by the smart crop
+	if len(args) == 4 {
+		if c.cropType != Smart {
+			return nil, filters.ErrInvalidFilterParameters
+		}
+		if c.strategy, err = parse.EskipEnumArg(args[3], smartCropStrategies); err != nil {
+			return nil, parse.AtArg(err, CropName, 4, "strategy")
+		}
 	}
 
 	return c, nil
 }
 
+// parseCropDimension parses the dimension either in pixels or as a percentage of the image, like "50%"
+func parseCropDimension(arg interface{}) (int, float64, error) {
+	value, percentage, err := parse.EskipPercentageArg(arg)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !percentage {
+		pixels, err := parse.EskipIntArg(arg)
+		return pixels, 0, err
+	}
+
+	if value <= 0 || value > 100 {
+		return 0, 0, filters.ErrInvalidFilterParameters
+	}
+
+	return 0, value, nil
+}
+
+// parseFocalCoordinate parses the coordinate of the focal point, as a fraction of the size of the image
+func parseFocalCoordinate(arg interface{}) (float64, error) {
+	value, err := parse.EskipFloatArg(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	if value < 0 || value > 1 {
+		return 0, filters.ErrInvalidFilterParameters
+	}
+
+	return value, nil
+}
+
+// focusCropOptions resizes the image to cover the width and the height and extracts the window centered on the
+// focal point, moving it back inside the image when the focal point is close to the edges
+func focusCropOptions(size bimg.ImageSize, width, height int, focusX, focusY float64) *bimg.Options {
+	_, scaledWidth, scaledHeight := coverSize(size, width, height)
+
+	return &bimg.Options{
+		Width:      scaledWidth,
+		Height:     scaledHeight,
+		Force:      true,
+		Left:       focusOffset(focusX, scaledWidth, width),
+		Top:        focusOffset(focusY, scaledHeight, height),
+		AreaWidth:  width,
+		AreaHeight: height}
+}
+
+// focusOffset returns the start of the window centered on the focal point, clamped to stay inside the image
+func focusOffset(focus float64, size, window int) int {
+	offset := int(math.Floor(focus*float64(size) - float64(window)/2 + 0.5))
+	return int(math.Max(0, math.Min(float64(offset), float64(size-window))))
+}
+
 func (f *crop) Request(ctx filters.FilterContext) {}
 
 func (f *crop) Response(ctx filters.FilterContext) {
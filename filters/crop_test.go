@@ -1,9 +1,14 @@
 package filters
 
 import (
+	"errors"
+	"image"
+	"image/color"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/zalando-stups/skrop/filters/imagefiltertest"
 	"github.com/h2non/bimg"
+	"github.com/zalando/skipper/filters"
 	"testing"
 )
 
@@ -22,7 +27,7 @@ func TestCrop_Name(t *testing.T) {
 
 func TestCrop_CreateOptions(t *testing.T) {
 	c := crop{width: 800, height: 600, cropType: North}
-	options, _ := c.CreateOptions(nil)
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
 
 	assert.Equal(t, 800, options.Width)
 	assert.Equal(t, 600, options.Height)
@@ -30,6 +35,77 @@ func TestCrop_CreateOptions(t *testing.T) {
 	assert.Equal(t, bimg.GravityNorth, options.Gravity)
 }
 
+func TestCrop_CreateOptions_Smart(t *testing.T) {
+	c := crop{width: 800, height: 600, cropType: Smart}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
+
+	assert.Equal(t, true, options.Crop)
+	assert.Equal(t, bimg.GravitySmart, options.Gravity)
+}
+
+func TestCrop_CreateOptions_Entropy(t *testing.T) {
+	c := crop{width: 10, height: 10, cropType: Smart, strategy: Entropy}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(40, 10, 30)))
+
+	assert.Equal(t, false, options.Crop)
+	assert.Equal(t, 30, options.Left)
+	assert.Equal(t, 10, options.AreaWidth)
+	assert.Equal(t, 10, options.AreaHeight)
+}
+
+func TestCrop_CreateOptions_OverSized(t *testing.T) {
+	for _, cropType := range []string{North, South, East, West, Center} {
+		c := crop{width: 5000, height: 5000, cropType: cropType}
+		options, err := c.CreateOptions(buildParameters(nil, detailedImage(100, 100, 100)))
+
+		assert.Nil(t, err)
+		assert.Equal(t, &bimg.Options{Width: 100, Height: 100, Gravity: cropTypeToGravity[cropType], Crop: true}, options)
+	}
+}
+
+func TestCrop_CreateOptions_OverSizedWidth(t *testing.T) {
+	for _, cropType := range []string{North, South, East, West, Center} {
+		c := crop{width: 5000, height: 50, cropType: cropType}
+		options, err := c.CreateOptions(buildParameters(nil, detailedImage(100, 100, 100)))
+
+		assert.Nil(t, err)
+		assert.Equal(t, &bimg.Options{Width: 100, Height: 50, Gravity: cropTypeToGravity[cropType], Crop: true}, options)
+	}
+}
+
+func TestCrop_CreateOptions_OverSizedStrict(t *testing.T) {
+	strictCropBounds = true
+	defer func() { strictCropBounds = false }()
+
+	c := crop{width: 5000, height: 50, cropType: Center}
+	_, err := c.CreateOptions(buildParameters(nil, detailedImage(100, 100, 100)))
+	assert.NotNil(t, err)
+
+	c = crop{width: 100, height: 50, cropType: Center}
+	_, err = c.CreateOptions(buildParameters(nil, detailedImage(100, 100, 100)))
+	assert.Nil(t, err)
+}
+
+func TestCrop_CanBeMerged_Entropy(t *testing.T) {
+	s := crop{}
+	self := &bimg.Options{Width: 40, Height: 10, Force: true, Left: 30, AreaWidth: 10, AreaHeight: 10}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{Quality: 80}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Width: 100, Height: 350, Crop: true}, self))
+
+	opt := s.Merge(&bimg.Options{Quality: 80}, self)
+	assert.Equal(t, &bimg.Options{Quality: 80, Width: 40, Height: 10, Force: true, Left: 30, AreaWidth: 10, AreaHeight: 10}, opt)
+}
+
+func TestCrop_CanBeMerged_EntropyAfterRotate(t *testing.T) {
+	s := crop{cropType: Smart, strategy: Entropy}
+	self := &bimg.Options{Width: 40, Height: 10, Force: true, Left: 30, AreaWidth: 10, AreaHeight: 10}
+
+	assert.False(t, s.CanBeMerged(&bimg.Options{Rotate: bimg.D90}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Flip: true}, self))
+	assert.True(t, (&crop{cropType: Focus}).CanBeMerged(&bimg.Options{Rotate: bimg.D90}, self))
+}
+
 func TestCrop_CanBeMerged_True(t *testing.T) {
 	s := crop{}
 	opt := &bimg.Options{}
@@ -46,6 +122,105 @@ func TestCrop_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestCrop_CanBeMerged_SameCrop(t *testing.T) {
+	s := crop{}
+	opt := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravityNorth, Crop: true}
+	self := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravityNorth, Crop: true}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestCrop_CanBeMerged_DifferentGravity(t *testing.T) {
+	s := crop{}
+	opt := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravityCentre, Crop: true}
+	self := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravityNorth, Crop: true}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestCrop_CanBeMerged_Trim(t *testing.T) {
+	cropOptions := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravityNorth, Crop: true}
+	trimOptions := &bimg.Options{Trim: true, Threshold: 10}
+
+	//neither the trim before the crop, nor the one after it is merged
+	assert.False(t, (&crop{}).CanBeMerged(trimOptions, cropOptions))
+	assert.False(t, (&trim{}).CanBeMerged(cropOptions, trimOptions))
+}
+
+func TestCrop_CanBeMerged_PendingExtract(t *testing.T) {
+	s := crop{}
+	opt := &bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}
+	self := &bimg.Options{Width: 10, Height: 10, Gravity: bimg.GravityCentre, Crop: true}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestCrop_AfterExtract(t *testing.T) {
+	//the left half of the image is black, the right half is white
+	pixels := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			v := uint8(255)
+			if x < 20 {
+				v = 0
+			}
+			pixels.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	buf, _ := encodePixels(pixels)
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(buf)
+	fc.FStateBag[hasMergedFilters] = false
+
+	(&extract{top: 0, left: 20, width: 20, height: 20}).Response(fc)
+	(&crop{width: 10, height: 10, cropType: Center}).Response(fc)
+	FinalizeResponse(fc)
+
+	result, err := decodePixels(readResultImage(fc.Response().Body, t).Image())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the crop is taken from the white extracted region, not from the center of the image
+	assert.Equal(t, image.Rect(0, 0, 10, 10), result.Bounds())
+	assert.Equal(t, uint8(255), result.NRGBAAt(0, 0).R)
+	assert.Equal(t, uint8(255), result.NRGBAAt(9, 9).R)
+}
+
+func TestCrop_ChainedCrops(t *testing.T) {
+	//the top quarter of the image is black, the rest is white
+	pixels := image.NewNRGBA(image.Rect(0, 0, 400, 400))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 400; x++ {
+			v := uint8(255)
+			if y < 100 {
+				v = 0
+			}
+			pixels.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	buf, _ := encodePixels(pixels)
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(buf)
+	fc.FStateBag[hasMergedFilters] = false
+
+	(&crop{width: 200, height: 200, cropType: Center}).Response(fc)
+	(&crop{width: 100, height: 100, cropType: North}).Response(fc)
+	FinalizeResponse(fc)
+
+	result, err := decodePixels(readResultImage(fc.Response().Body, t).Image())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the second crop is taken from the top of the centered crop, not from the top of the image
+	assert.Equal(t, 100, result.Rect.Dx())
+	assert.Equal(t, 100, result.Rect.Dy())
+	assert.Equal(t, uint8(255), result.NRGBAAt(50, 0).R)
+}
+
 func TestCrop_Merge(t *testing.T) {
 	s := crop{}
 	self := &bimg.Options{Width: 100, Height: 350, Gravity: 2, Crop: true}
@@ -58,6 +233,25 @@ func TestCrop_Merge(t *testing.T) {
 	assert.Equal(t, self.Crop, opt.Crop)
 }
 
+func TestCrop_CreateFilter_ArgError(t *testing.T) {
+	_, err := NewCrop().CreateFilter([]interface{}{800.0, "abc"})
+
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+	assert.EqualError(t, err, `crop: argument 2 (height): expected number or percentage, got string "abc"`)
+}
+
+func TestCrop_CreateFilter_StrategyAndFocusArgErrors(t *testing.T) {
+	if smartCropSupported() {
+		_, err := NewCrop().CreateFilter([]interface{}{800.0, 600.0, Smart, "random"})
+		assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+		assert.EqualError(t, err, `crop: argument 4 (strategy): expected one of attention, entropy, got string "random"`)
+	}
+
+	_, err := NewCrop().CreateFilter([]interface{}{800.0, 600.0, Focus, 0.5, "top"})
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+	assert.EqualError(t, err, `crop: argument 5 (focusY): expected number, got string "top"`)
+}
+
 func TestCrop_CreateFilter(t *testing.T) {
 	imagefiltertest.TestCreate(t, NewCrop, []imagefiltertest.CreateTestItem{{
 		"no args",
@@ -72,12 +266,159 @@ func TestCrop_CreateFilter(t *testing.T) {
 		[]interface{}{800.0, 600.0, North},
 		false,
 	}, {
-		"more than 3 args",
-		[]interface{}{800.0, 600.0, North, "whaaat?"},
+		"smart crop",
+		[]interface{}{800.0, 600.0, Smart},
+		!smartCropSupported(),
+	}, {
+		"smart crop with the entropy strategy",
+		[]interface{}{800.0, 600.0, Smart, Entropy},
+		!smartCropSupported(),
+	}, {
+		"smart crop with the attention strategy",
+		[]interface{}{800.0, 600.0, Smart, Attention},
+		!smartCropSupported(),
+	}, {
+		"strategy without smart crop",
+		[]interface{}{800.0, 600.0, North, Entropy},
+		true,
+	}, {
+		"invalid strategy",
+		[]interface{}{800.0, 600.0, Smart, "whaaat?"},
+		true,
+	}, {
+		"more than 4 args",
+		[]interface{}{800.0, 600.0, Smart, Entropy, "whaaat?"},
 		true,
 	}, {
 		"less than 2 args",
 		[]interface{}{800.0},
 		true,
+	}, {
+		"percentages",
+		[]interface{}{"50%", "50%", Center},
+		false,
+	}, {
+		"pixels and percentage",
+		[]interface{}{100.0, "50%", Center},
+		false,
+	}, {
+		"percentage over 100",
+		[]interface{}{"150%", 100.0},
+		true,
+	}, {
+		"zero percentage",
+		[]interface{}{"0%", 100.0},
+		true,
+	}, {
+		"pixels as a string",
+		[]interface{}{"100", 100.0},
+		true,
+	}, {
+		"focus crop",
+		[]interface{}{800.0, 600.0, Focus, 0.25, 0.75},
+		false,
+	}, {
+		"focus crop without focal point",
+		[]interface{}{800.0, 600.0, Focus},
+		true,
+	}, {
+		"focus crop with a strategy",
+		[]interface{}{800.0, 600.0, Focus, Entropy},
+		true,
+	}, {
+		"focal point out of the image",
+		[]interface{}{800.0, 600.0, Focus, 1.5, 0.5},
+		true,
+	}, {
+		"negative focal point",
+		[]interface{}{800.0, 600.0, Focus, 0.5, -0.1},
+		true,
+	}, {
+		"focal point without focus crop",
+		[]interface{}{800.0, 600.0, Center, 0.5, 0.5},
+		true,
+	}, {
+		"pixels not integer",
+		[]interface{}{100.5, 100.0},
+		true,
 	}})
 }
+
+func TestCrop_CreateOptions_Percentages(t *testing.T) {
+	c := crop{widthPercent: 50, heightPercent: 25, cropType: Center}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, 200, options.Width)
+	assert.Equal(t, 75, options.Height)
+	assert.Equal(t, true, options.Crop)
+}
+
+func TestCrop_CreateOptions_PixelsAndPercentage(t *testing.T) {
+	f, _ := NewCrop().CreateFilter([]interface{}{100.0, "50%", Center})
+	options, _ := f.(*crop).CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, 100, options.Width)
+	assert.Equal(t, 150, options.Height)
+}
+
+func TestCrop_CreateOptions_Focus(t *testing.T) {
+	c := crop{width: 100, height: 100, cropType: Focus, focusX: 0.5, focusY: 0.5}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, &bimg.Options{Width: 133, Height: 100, Force: true, Left: 17, Top: 0, AreaWidth: 100, AreaHeight: 100}, options)
+}
+
+func TestCrop_CreateOptions_FocusTopLeftCorner(t *testing.T) {
+	c := crop{width: 100, height: 100, cropType: Focus, focusX: 0, focusY: 0}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(300, 400, 300)))
+
+	assert.Equal(t, 0, options.Left)
+	assert.Equal(t, 0, options.Top)
+}
+
+func TestCrop_CreateOptions_FocusBottomRightCorner(t *testing.T) {
+	c := crop{width: 100, height: 100, cropType: Focus, focusX: 1, focusY: 1}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(300, 400, 300)))
+
+	assert.Equal(t, 100, options.Width)
+	assert.Equal(t, 133, options.Height)
+	assert.Equal(t, 0, options.Left)
+	assert.Equal(t, 33, options.Top)
+}
+
+func TestCrop_CreateOptions_FocusSmallImage(t *testing.T) {
+	c := crop{width: 100, height: 100, cropType: Focus, focusX: 1, focusY: 1}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(40, 30, 40)))
+
+	assert.Equal(t, &bimg.Options{Width: 40, Height: 30, Force: true, AreaWidth: 40, AreaHeight: 30}, options)
+}
+
+func TestCrop_CreateOptions_FocusReadsSizeOnce(t *testing.T) {
+	reads, restore := countSizeReads()
+	defer restore()
+
+	c := crop{width: 100, height: 100, cropType: Focus, focusX: 0.5, focusY: 0.5}
+	_, err := c.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *reads, "the focus crop should reuse the size read for the crop")
+}
+
+func TestCrop_CreateOptions_EntropyReadsSizeOnce(t *testing.T) {
+	reads, restore := countSizeReads()
+	defer restore()
+
+	c := crop{width: 10, height: 10, cropType: Smart, strategy: Entropy}
+	_, err := c.CreateOptions(buildParameters(nil, detailedImage(40, 10, 30)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *reads, "the entropy crop should reuse the size read for the crop")
+}
+
+func TestCrop_CanBeMerged_Focus(t *testing.T) {
+	s := crop{cropType: Focus}
+	self := &bimg.Options{Width: 133, Height: 100, Force: true, Left: 17, AreaWidth: 100, AreaHeight: 100}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{Quality: 80}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Width: 200, Height: 100}, self))
+}
@@ -0,0 +1,100 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// CropAspectName is the name of the filter
+const CropAspectName = "cropAspect"
+
+type cropAspect struct {
+	widthRatio  int
+	heightRatio int
+	gravity     string
+}
+
+// NewCropAspect creates a new filter of this type
+func NewCropAspect() filters.Spec {
+	return &cropAspect{}
+}
+
+func (f *cropAspect) Name() string {
+	return CropAspectName
+}
+
+func (f *cropAspect) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for crop aspect ", f)
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	//the largest rectangle with the ratio keeps either the whole width or the whole height
+	width := size.Width
+	height := size.Width * f.heightRatio / f.widthRatio
+	if height > size.Height {
+		width = size.Height * f.widthRatio / f.heightRatio
+		height = size.Height
+	}
+
+	return &bimg.Options{
+		Left:       gravityOffset(horizontalGravity[f.gravity], bimg.GravityWest, bimg.GravityEast, size.Width-width),
+		Top:        gravityOffset(verticalGravity[f.gravity], bimg.GravityNorth, bimg.GravitySouth, size.Height-height),
+		AreaWidth:  width,
+		AreaHeight: height}, nil
+}
+
+func (f *cropAspect) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//the rectangle depends on the size of the image, so the resizes need to be applied first
+	return other.Width == 0 && other.Height == 0 && extractCanBeMerged(other, self)
+}
+
+func (f *cropAspect) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Top = self.Top
+	other.Left = self.Left
+	other.AreaWidth = self.AreaWidth
+	other.AreaHeight = self.AreaHeight
+	return other
+}
+
+func (f *cropAspect) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 3 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &cropAspect{}
+
+	c.widthRatio, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c.heightRatio, err = parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if c.widthRatio <= 0 || c.heightRatio <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	if gravity, ok := args[2].(string); ok && gravityType[gravity] {
+		c.gravity = gravity
+	} else {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return c, nil
+}
+
+func (f *cropAspect) Request(ctx filters.FilterContext) {}
+
+func (f *cropAspect) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
@@ -0,0 +1,88 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewCropAspect(t *testing.T) {
+	name := NewCropAspect().Name()
+	assert.Equal(t, "cropAspect", name)
+}
+
+func TestCropAspect_Name(t *testing.T) {
+	c := cropAspect{}
+	assert.Equal(t, "cropAspect", c.Name())
+}
+
+func TestCropAspect_CreateOptions_Landscape(t *testing.T) {
+	c := cropAspect{widthRatio: 16, heightRatio: 9, gravity: CC}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(1000, 1000, 1000)))
+
+	assert.Equal(t, &bimg.Options{Left: 0, Top: 219, AreaWidth: 1000, AreaHeight: 562}, options)
+}
+
+func TestCropAspect_CreateOptions_Portrait(t *testing.T) {
+	c := cropAspect{widthRatio: 9, heightRatio: 16, gravity: CC}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(1000, 1000, 1000)))
+
+	assert.Equal(t, &bimg.Options{Left: 219, Top: 0, AreaWidth: 562, AreaHeight: 1000}, options)
+}
+
+func TestCropAspect_CreateOptions_Gravity(t *testing.T) {
+	c := cropAspect{widthRatio: 16, heightRatio: 9, gravity: SW}
+	options, _ := c.CreateOptions(buildParameters(nil, detailedImage(1000, 1000, 1000)))
+
+	assert.Equal(t, 0, options.Left)
+	assert.Equal(t, 438, options.Top)
+
+	c = cropAspect{widthRatio: 1, heightRatio: 1, gravity: NE}
+	options, _ = c.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, &bimg.Options{Left: 100, Top: 0, AreaWidth: 300, AreaHeight: 300}, options)
+}
+
+func TestCropAspect_CanBeMerged(t *testing.T) {
+	c := cropAspect{}
+	self := &bimg.Options{Top: 219, AreaWidth: 1000, AreaHeight: 562}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{Quality: 80}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 500}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 500, Height: 500, Crop: true}, self))
+}
+
+func TestCropAspect_Merge(t *testing.T) {
+	c := cropAspect{}
+	self := &bimg.Options{Top: 219, AreaWidth: 1000, AreaHeight: 562}
+
+	merged := c.Merge(&bimg.Options{Quality: 80}, self)
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Top: 219, AreaWidth: 1000, AreaHeight: 562}, merged)
+}
+
+func TestCropAspect_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewCropAspect, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{16.0, 9.0, CC},
+		Err:  false,
+	}, {
+		Msg:  "zero ratio",
+		Args: []interface{}{0.0, 9.0, CC},
+		Err:  true,
+	}, {
+		Msg:  "invalid gravity",
+		Args: []interface{}{16.0, 9.0, "center"},
+		Err:  true,
+	}, {
+		Msg:  "two args",
+		Args: []interface{}{16.0, 9.0},
+		Err:  true,
+	}})
+}
@@ -37,7 +37,7 @@ func (f *cropByFocalPoint) Name() string {
 func (f *cropByFocalPoint) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
 	log.Debug("Create options for crop by focal point ", f)
 
-	imageSize, err := imageContext.Image.Size()
+	imageSize, err := imageContext.ImageSize()
 
 	if err != nil {
 		return nil, err
@@ -27,7 +27,7 @@ func (f *cropByHeight) Name() string {
 func (f *cropByHeight) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
 	log.Debug("Create options for crop by height ", f)
 
-	imageSize, err := imageContext.Image.Size()
+	imageSize, err := imageContext.ImageSize()
 
 	if err != nil {
 		return nil, err
@@ -41,8 +41,7 @@ func (f *cropByHeight) CreateOptions(imageContext *ImageFilterContext) (*bimg.Op
 }
 
 func (f *cropByHeight) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
-	return (other.Width == 0 && other.Height == 0 && !other.Crop) ||
-		(other.Width == self.Width && other.Height == self.Height && other.Crop == self.Crop)
+	return cropCanBeMerged(other, self)
 }
 
 func (f *cropByHeight) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
@@ -69,11 +68,16 @@ func (f *cropByHeight) CreateFilter(args []interface{}) (filters.Filter, error)
 	}
 
 	if len(args) == 2 {
-		if cropType, ok := args[1].(string); ok && cropTypes[cropType] {
+		//the focus crop needs a focal point, which only the crop takes
+		if cropType, ok := args[1].(string); ok && cropTypes[cropType] && cropType != Focus {
 			c.cropType = cropType
 		} else {
 			return nil, filters.ErrInvalidFilterParameters
 		}
+		if c.cropType == Smart && !smartCropSupported() {
+			log.Error(ErrSmartCropNotSupported.Error())
+			return nil, ErrSmartCropNotSupported
+		}
 	}
 
 	return c, nil
@@ -0,0 +1,88 @@
+package filters
+
+import (
+	"fmt"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// CropOffsetName is the name of the filter
+const CropOffsetName = "cropOffset"
+
+// cropOffset extracts the area like extract, checking that it is inside the image
+type cropOffset struct {
+	extract
+}
+
+// NewCropOffset creates a new filter of this type
+func NewCropOffset() filters.Spec {
+	return &cropOffset{}
+}
+
+func (f *cropOffset) Name() string {
+	return CropOffsetName
+}
+
+func (f *cropOffset) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for crop offset ", f)
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	if f.left+f.width > size.Width || f.top+f.height > size.Height {
+		return nil, fmt.Errorf("the area %dx%d at %d,%d exceeds the image of %dx%d",
+			f.width, f.height, f.left, f.top, size.Width, size.Height)
+	}
+
+	return f.extract.CreateOptions(imageContext)
+}
+
+func (f *cropOffset) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//the area is checked against the image, so the resizes need to be applied first
+	return other.Width == 0 && other.Height == 0 && f.extract.CanBeMerged(other, self)
+}
+
+func (f *cropOffset) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 4 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &cropOffset{}
+
+	c.left, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c.top, err = parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	c.width, err = parse.EskipIntArg(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	c.height, err = parse.EskipIntArg(args[3])
+	if err != nil {
+		return nil, err
+	}
+
+	if c.top < 0 || c.left < 0 || c.width <= 0 || c.height <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return c, nil
+}
+
+func (f *cropOffset) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewCropOffset(t *testing.T) {
+	name := NewCropOffset().Name()
+	assert.Equal(t, "cropOffset", name)
+}
+
+func TestCropOffset_Name(t *testing.T) {
+	c := cropOffset{}
+	assert.Equal(t, "cropOffset", c.Name())
+}
+
+func TestCropOffset_CreateOptions_Quadrants(t *testing.T) {
+	image := detailedImage(400, 400, 400)
+
+	for _, quadrant := range []struct{ left, top int }{{0, 0}, {200, 0}, {0, 200}, {200, 200}} {
+		f, _ := NewCropOffset().CreateFilter([]interface{}{float64(quadrant.left), float64(quadrant.top), 200.0, 200.0})
+		options, err := f.(*cropOffset).CreateOptions(buildParameters(nil, image))
+
+		assert.Nil(t, err)
+		assert.Equal(t, &bimg.Options{Left: quadrant.left, Top: quadrant.top, AreaWidth: 200, AreaHeight: 200}, options)
+	}
+}
+
+func TestCropOffset_CreateOptions_OutOfBounds(t *testing.T) {
+	image := detailedImage(400, 400, 400)
+
+	c := cropOffset{extract{left: 300, top: 0, width: 200, height: 200}}
+	_, err := c.CreateOptions(buildParameters(nil, image))
+	assert.NotNil(t, err)
+
+	c = cropOffset{extract{left: 0, top: 201, width: 200, height: 200}}
+	_, err = c.CreateOptions(buildParameters(nil, image))
+	assert.NotNil(t, err)
+}
+
+func TestCropOffset_CanBeMerged(t *testing.T) {
+	c := cropOffset{}
+	self := &bimg.Options{Left: 200, Top: 200, AreaWidth: 200, AreaHeight: 200}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{Quality: 80}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 200, Height: 200, Crop: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 800}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Left: 0, Top: 0, AreaWidth: 100, AreaHeight: 100}, self))
+}
+
+func TestCropOffset_Merge(t *testing.T) {
+	c := cropOffset{}
+	self := &bimg.Options{Left: 200, Top: 200, AreaWidth: 200, AreaHeight: 200}
+
+	merged := c.Merge(&bimg.Options{Quality: 80}, self)
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Left: 200, Top: 200, AreaWidth: 200, AreaHeight: 200}, merged)
+}
+
+func TestCropOffset_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewCropOffset, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "four args",
+		Args: []interface{}{200.0, 0.0, 200.0, 200.0},
+		Err:  false,
+	}, {
+		Msg:  "negative offset",
+		Args: []interface{}{-1.0, 0.0, 200.0, 200.0},
+		Err:  true,
+	}, {
+		Msg:  "zero width",
+		Args: []interface{}{0.0, 0.0, 0.0, 200.0},
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{0.0, 0.0, 200.0},
+		Err:  true,
+	}})
+}
+
+func TestCropOffset_CreateFilter_LeftFirst(t *testing.T) {
+	f, _ := NewCropOffset().CreateFilter([]interface{}{10.0, 20.0, 200.0, 100.0})
+	c := f.(*cropOffset)
+
+	assert.Equal(t, 10, c.left)
+	assert.Equal(t, 20, c.top)
+}
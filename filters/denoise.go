@@ -0,0 +1,99 @@
+package filters
+
+import (
+	"image"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// DenoiseName is the name of the filter
+	DenoiseName = "denoise"
+	//the cost of the median grows with the square of the window
+	maxDenoiseWindow = 15
+)
+
+type denoise struct {
+	window int
+}
+
+// NewDenoise creates a new filter of this type
+func NewDenoise() filters.Spec {
+	return &denoise{}
+}
+
+func (f *denoise) Name() string {
+	return DenoiseName
+}
+
+func (f *denoise) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for denoise ", f)
+
+	if f.window == 1 {
+		return img, nil
+	}
+
+	//every channel gets the median of the window around the pixel, the edges being extended
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	radius := f.window / 2
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	window := make([]int, 0, f.window*f.window)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			o := y*result.Stride + x*4
+			for c := 0; c < 4; c++ {
+				window = window[:0]
+				for dy := -radius; dy <= radius; dy++ {
+					sy := clampInt(y+dy, 0, height-1)
+					for dx := -radius; dx <= radius; dx++ {
+						sx := clampInt(x+dx, 0, width-1)
+						window = append(window, int(img.Pix[sy*img.Stride+sx*4+c]))
+					}
+				}
+				sort.Ints(window)
+				result.Pix[o+c] = uint8(window[len(window)/2])
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// CanBeMerged refuses merging, as two medians are not the median of a bigger window
+func (f *denoise) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *denoise) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *denoise) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	d := &denoise{}
+
+	if d.window, err = parse.EskipIntArg(args[0]); err != nil {
+		return nil, parse.AtArg(err, DenoiseName, 1, "windowSize")
+	}
+	if d.window <= 0 || d.window%2 == 0 || d.window > maxDenoiseWindow {
+		log.Errorf("Failed to create the denoise filter, the window %d is not an odd size up to %d",
+			d.window, maxDenoiseWindow)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return d, nil
+}
+
+func (f *denoise) Request(ctx filters.FilterContext) {}
+
+func (f *denoise) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewDenoise(t *testing.T) {
+	name := NewDenoise().Name()
+	assert.Equal(t, "denoise", name)
+}
+
+func TestDenoise_TransformPixels_SaltAndPepper(t *testing.T) {
+	flat := color.NRGBA{R: 120, G: 90, B: 60, A: 255}
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.SetNRGBA(x, y, flat)
+		}
+	}
+	//isolated white and black pixels
+	for _, p := range []image.Point{{1, 1}, {5, 2}, {8, 7}, {3, 6}} {
+		img.SetNRGBA(p.X, p.Y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+	for _, p := range []image.Point{{7, 1}, {2, 4}, {6, 8}} {
+		img.SetNRGBA(p.X, p.Y, color.NRGBA{A: 255})
+	}
+
+	result, err := (&denoise{window: 3}).TransformPixels(img)
+
+	assert.Nil(t, err)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			assert.Equal(t, flat, result.NRGBAAt(x, y))
+		}
+	}
+}
+
+func TestDenoise_TransformPixels_NoOp(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	result, err := (&denoise{window: 1}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result)
+}
+
+func TestDenoise_CanBeMerged(t *testing.T) {
+	f := denoise{window: 3}
+	assert.False(t, f.CanBeMerged(&denoise{window: 3}))
+}
+
+func TestDenoise_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewDenoise, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "odd window",
+		Args: []interface{}{3.0},
+		Err:  false,
+	}, {
+		Msg:  "even window",
+		Args: []interface{}{4.0},
+		Err:  true,
+	}, {
+		Msg:  "negative window",
+		Args: []interface{}{-3.0},
+		Err:  true,
+	}, {
+		Msg:  "too big window",
+		Args: []interface{}{17.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{3.0, 5.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,172 @@
+package filters
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// DominantColorName is the name of the filter
+	DominantColorName = "dominantColor"
+	// DominantColorHeader is the response header with the dominant color of the image, as #rrggbb
+	DominantColorHeader = "X-Dominant-Color"
+	// DominantColorModal is the most frequent color of the image
+	DominantColorModal = "modal"
+	// DominantColorAverage is the average color of the image
+	DominantColorAverage = "average"
+	skropDominantColor   = "skDominantColor"
+)
+
+var dominantColorModes = map[string]bool{DominantColorModal: true, DominantColorAverage: true}
+
+type dominantColor struct {
+	mode   string
+	swatch bool
+}
+
+// NewDominantColor creates a new filter of this type
+func NewDominantColor() filters.Spec {
+	return &dominantColor{}
+}
+
+func (f *dominantColor) Name() string {
+	return DominantColorName
+}
+
+func (f *dominantColor) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) > 2 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	d := &dominantColor{mode: DominantColorModal}
+
+	if len(args) > 0 {
+		if d.mode, err = parse.EskipEnumArg(args[0], dominantColorModes); err != nil {
+			return nil, parse.AtArg(err, DominantColorName, 1, "mode")
+		}
+	}
+
+	if len(args) > 1 {
+		if d.swatch, err = parse.EskipBoolArg(args[1]); err != nil {
+			return nil, parse.AtArg(err, DominantColorName, 2, "swatch")
+		}
+	}
+
+	return d, nil
+}
+
+func (f *dominantColor) Request(ctx filters.FilterContext) {}
+
+// Response only marks the color to be computed, as it is the color of the output image, after all the
+// transformations, when the response is finalized
+func (f *dominantColor) Response(ctx filters.FilterContext) {
+	start := time.Now()
+	err := f.handleResponse(ctx)
+	logFilter(ctx, DominantColorName, time.Since(start), err)
+}
+
+func (f *dominantColor) handleResponse(ctx filters.FilterContext) error {
+	if processingStopped(ctx) {
+		return fmt.Errorf("processing skipped, as the backend/filter reported %d status code", ctx.Response().StatusCode)
+	}
+
+	initOnce(ctx)
+
+	if processingStopped(ctx) {
+		return fmt.Errorf("processing failed, the image could not be read: %d status code", ctx.Response().StatusCode)
+	}
+
+	if ctx.StateBag()[skropAnimated] == true {
+		return ErrAnimatedImage
+	}
+
+	if ctx.StateBag()[skropNotImage] == true {
+		return ErrNotAnImage
+	}
+
+	if ctx.StateBag()[skropSkipped] == true {
+		log.Debug("Dominant color skipped, the image is smaller than the minimum dimensions")
+		return nil
+	}
+
+	ctx.StateBag()[skropDominantColor] = f
+	return nil
+}
+
+// applyDominantColor sets the header with the dominant color of the output image, when requested, and replaces the
+// image with a 1x1 swatch of the color if asked to
+func applyDominantColor(ctx filters.FilterContext, buf []byte) ([]byte, error) {
+	f, ok := ctx.StateBag()[skropDominantColor].(*dominantColor)
+	if !ok {
+		return buf, nil
+	}
+
+	pixels, err := decodePixels(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	c := f.colorOf(pixels)
+	ctx.Response().Header.Set(DominantColorHeader, fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+
+	if !f.swatch {
+		return buf, nil
+	}
+
+	swatch := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	swatch.SetNRGBA(0, 0, c)
+	ctx.Response().Header.Set("Content-Type", "image/png")
+	return encodePixels(swatch)
+}
+
+// colorOf returns the dominant color of the image, the transparent pixels are not counted
+func (f *dominantColor) colorOf(img *image.NRGBA) color.NRGBA {
+	//the colors are grouped by their 4 most significant bits per channel, so the close shades count as one
+	var sums [4096][4]int
+
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			r, g, b, a := int(row[i]), int(row[i+1]), int(row[i+2]), int(row[i+3])
+			if a == 0 {
+				continue
+			}
+
+			bucket := &sums[0]
+			if f.mode == DominantColorModal {
+				bucket = &sums[r>>4<<8|g>>4<<4|b>>4]
+			}
+			bucket[0] += r * a
+			bucket[1] += g * a
+			bucket[2] += b * a
+			bucket[3] += a
+		}
+	}
+
+	dominant := &sums[0]
+	for i := range sums {
+		if sums[i][3] > dominant[3] {
+			dominant = &sums[i]
+		}
+	}
+
+	if dominant[3] == 0 {
+		return color.NRGBA{A: 255}
+	}
+
+	weight := float64(dominant[3])
+	return color.NRGBA{
+		R: clampUint8(float64(dominant[0]) / weight),
+		G: clampUint8(float64(dominant[1]) / weight),
+		B: clampUint8(float64(dominant[2]) / weight),
+		A: 255,
+	}
+}
@@ -0,0 +1,128 @@
+package filters
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+var (
+	red  = color.NRGBA{R: 200, G: 30, B: 30, A: 255}
+	blue = color.NRGBA{R: 20, G: 40, B: 220, A: 255}
+)
+
+// mostlyRed is a 10x10 image, with 70 red pixels and 30 blue ones
+func mostlyRed() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			if y < 7 {
+				img.SetNRGBA(x, y, red)
+			} else {
+				img.SetNRGBA(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+func mostlyRedPNG(t *testing.T) []byte {
+	buf, err := encodePixels(mostlyRed())
+	assert.Nil(t, err)
+	return buf
+}
+
+func TestNewDominantColor(t *testing.T) {
+	name := NewDominantColor().Name()
+	assert.Equal(t, "dominantColor", name)
+}
+
+func TestDominantColor_ColorOf(t *testing.T) {
+	c := (&dominantColor{mode: DominantColorModal}).colorOf(mostlyRed())
+	assert.Equal(t, red, c)
+
+	c = (&dominantColor{mode: DominantColorAverage}).colorOf(mostlyRed())
+	assert.Equal(t, color.NRGBA{R: 146, G: 33, B: 87, A: 255}, c)
+}
+
+func TestDominantColor_ColorOf_Transparent(t *testing.T) {
+	img := mostlyRed()
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 10; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 200, G: 30, B: 30})
+		}
+	}
+
+	c := (&dominantColor{mode: DominantColorModal}).colorOf(img)
+	assert.Equal(t, blue, c)
+
+	c = (&dominantColor{mode: DominantColorAverage}).colorOf(uniformImage(color.NRGBA{}))
+	assert.Equal(t, color.NRGBA{A: 255}, c)
+}
+
+func TestDominantColor_Response(t *testing.T) {
+	buf := mostlyRedPNG(t)
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(buf))
+
+	f, err := NewDominantColor().CreateFilter(nil)
+	assert.Nil(t, err)
+	f.Response(fc)
+	FinalizeResponse(fc)
+
+	assert.Equal(t, "#c81e1e", fc.Response().Header.Get(DominantColorHeader))
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, buf, body, "the image should be unchanged")
+}
+
+func TestDominantColor_Response_Swatch(t *testing.T) {
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(mostlyRedPNG(t)))
+
+	f, err := NewDominantColor().CreateFilter([]interface{}{"modal", "true"})
+	assert.Nil(t, err)
+	f.Response(fc)
+	FinalizeResponse(fc)
+
+	assert.Equal(t, "#c81e1e", fc.Response().Header.Get(DominantColorHeader))
+	assert.Equal(t, "image/png", fc.Response().Header.Get("Content-Type"))
+
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	swatch, err := decodePixels(body)
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1, 1), swatch.Bounds())
+	assert.Equal(t, red, swatch.NRGBAAt(0, 0))
+}
+
+func TestDominantColor_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewDominantColor, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "average",
+		Args: []interface{}{"average"},
+		Err:  false,
+	}, {
+		Msg:  "modal with swatch",
+		Args: []interface{}{"modal", "true"},
+		Err:  false,
+	}, {
+		Msg:  "unknown mode",
+		Args: []interface{}{"median"},
+		Err:  true,
+	}, {
+		Msg:  "invalid swatch",
+		Args: []interface{}{"modal", "yes please"},
+		Err:  true,
+	}, {
+		Msg:  "too many args",
+		Args: []interface{}{"modal", "true", 1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,95 @@
+package filters
+
+import (
+	"math"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// DprName is the name of the filter
+const DprName = "dpr"
+
+const maxDpr = 4.0
+
+type dpr struct {
+	ratio float64
+}
+
+// NewDpr creates a new filter of this type
+func NewDpr() filters.Spec {
+	return &dpr{}
+}
+
+func (f *dpr) Name() string {
+	return DprName
+}
+
+// CreateOptions scales the image itself, which is only used when there are no dimensions merged so far to scale
+func (f *dpr) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for dpr ", f)
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bimg.Options{
+		Width:  scaleDimension(size.Width, f.ratio),
+		Height: scaleDimension(size.Height, f.ratio),
+		Force:  true}, nil
+}
+
+func (f *dpr) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//the area to extract is only scaled together with the resize happening before the extraction
+	return other.Width > 0 || other.Height > 0
+}
+
+// Merge scales the dimensions merged so far by the ratio. As the responses are processed in the reverse order
+// of the route, these are the dimensions of the filters following dpr in the route
+func (f *dpr) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Width = f.scale(other.Width)
+	other.Height = f.scale(other.Height)
+	other.Top = f.scale(other.Top)
+	other.Left = f.scale(other.Left)
+	other.AreaWidth = f.scale(other.AreaWidth)
+	other.AreaHeight = f.scale(other.AreaHeight)
+	return other
+}
+
+func (f *dpr) scale(dimension int) int {
+	if dimension == 0 {
+		return 0
+	}
+	return scaleDimension(dimension, f.ratio)
+}
+
+func (f *dpr) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	d := &dpr{}
+
+	d.ratio, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if d.ratio <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	d.ratio = math.Min(d.ratio, maxDpr)
+
+	return d, nil
+}
+
+func (f *dpr) Request(ctx filters.FilterContext) {}
+
+func (f *dpr) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
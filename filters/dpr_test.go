@@ -0,0 +1,86 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewDpr(t *testing.T) {
+	name := NewDpr().Name()
+	assert.Equal(t, "dpr", name)
+}
+
+func TestDpr_Name(t *testing.T) {
+	d := dpr{}
+	assert.Equal(t, "dpr", d.Name())
+}
+
+func TestDpr_CreateOptions(t *testing.T) {
+	d := dpr{ratio: 2}
+	options, _ := d.CreateOptions(buildParameters(nil, detailedImage(40, 30, 40)))
+
+	assert.Equal(t, &bimg.Options{Width: 80, Height: 60, Force: true}, options)
+}
+
+func TestDpr_CanBeMerged(t *testing.T) {
+	d := dpr{ratio: 2}
+
+	assert.True(t, d.CanBeMerged(&bimg.Options{Width: 100, Height: 100, Crop: true}, &bimg.Options{}))
+	assert.True(t, d.CanBeMerged(&bimg.Options{Width: 100}, &bimg.Options{}))
+	assert.False(t, d.CanBeMerged(&bimg.Options{}, &bimg.Options{}))
+	assert.False(t, d.CanBeMerged(&bimg.Options{Top: 10, AreaWidth: 100, AreaHeight: 100}, &bimg.Options{}))
+}
+
+func TestDpr_ScalesCrop(t *testing.T) {
+	image := detailedImage(400, 300, 400)
+	c := crop{width: 100, height: 100, cropType: Center}
+	d := dpr{ratio: 2}
+
+	cropOptions, _ := c.CreateOptions(buildParameters(nil, image))
+	merged := c.Merge(&bimg.Options{}, cropOptions)
+
+	dprOptions, _ := d.CreateOptions(buildParameters(nil, image))
+	assert.True(t, d.CanBeMerged(merged, dprOptions))
+	merged = d.Merge(merged, dprOptions)
+
+	assert.Equal(t, 200, merged.Width)
+	assert.Equal(t, 200, merged.Height)
+	assert.True(t, merged.Crop)
+}
+
+func TestDpr_ScalesEntropyCrop(t *testing.T) {
+	d := dpr{ratio: 1.5}
+	other := &bimg.Options{Width: 40, Height: 10, Force: true, Left: 30, AreaWidth: 10, AreaHeight: 10}
+
+	merged := d.Merge(other, &bimg.Options{})
+
+	assert.Equal(t, &bimg.Options{Width: 60, Height: 15, Force: true, Left: 45, AreaWidth: 15, AreaHeight: 15}, merged)
+}
+
+func TestDpr_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewDpr, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{2.0},
+		Err:  false,
+	}, {
+		Msg:  "zero ratio",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{2.0, 2.0},
+		Err:  true,
+	}})
+}
+
+func TestDpr_CreateFilter_ClampsRatio(t *testing.T) {
+	f, _ := NewDpr().CreateFilter([]interface{}{10.0})
+	assert.Equal(t, 4.0, f.(*dpr).ratio)
+}
@@ -0,0 +1,175 @@
+package filters
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// DropShadowName is the name of the filter
+const DropShadowName = "dropShadow"
+
+type dropShadow struct {
+	offsetX int
+	offsetY int
+	blur    float64
+	color   bimg.Color
+}
+
+// NewDropShadow creates a new filter of this type
+func NewDropShadow() filters.Spec {
+	return &dropShadow{}
+}
+
+func (f *dropShadow) Name() string {
+	return DropShadowName
+}
+
+// padding is the space needed around the shadow for its blur
+func (f *dropShadow) padding() int {
+	return int(math.Ceil(3 * f.blur))
+}
+
+func (f *dropShadow) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for drop shadow ", f)
+
+	//the canvas is expanded, so the shadow and its blur are not cut
+	pad := f.padding()
+	bounds := img.Bounds()
+	width := bounds.Dx() + 2*pad + int(math.Abs(float64(f.offsetX)))
+	height := bounds.Dy() + 2*pad + int(math.Abs(float64(f.offsetY)))
+
+	//the shadow is moved by the offset, or the image by the opposite of the negative offsets
+	imageAt := image.Pt(pad, pad)
+	shadowAt := image.Pt(pad+f.offsetX, pad+f.offsetY)
+	if f.offsetX < 0 {
+		imageAt.X, shadowAt.X = pad-f.offsetX, pad
+	}
+	if f.offsetY < 0 {
+		imageAt.Y, shadowAt.Y = pad-f.offsetY, pad
+	}
+
+	//the silhouette is the alpha channel of the image
+	silhouette := make([]float64, width*height)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			a := img.Pix[img.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)+3]
+			silhouette[(shadowAt.Y+y)*width+shadowAt.X+x] = float64(a) / 255
+		}
+	}
+	silhouette = gaussianBlur(silhouette, width, height, f.blur)
+
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i, a := range silhouette {
+		result.Pix[i*4] = f.color.R
+		result.Pix[i*4+1] = f.color.G
+		result.Pix[i*4+2] = f.color.B
+		result.Pix[i*4+3] = clampUint8(a * 255)
+	}
+	draw.Draw(result, bounds.Sub(bounds.Min).Add(imageAt), img, bounds.Min, draw.Over)
+
+	return result, nil
+}
+
+// gaussianBlur blurs the values of the plane in two passes, horizontal and vertical
+func gaussianBlur(plane []float64, width int, height int, sigma float64) []float64 {
+	radius := int(math.Ceil(3 * sigma))
+	if radius == 0 {
+		return plane
+	}
+
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	blurred := make([]float64, len(plane))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := 0.0
+			for k, weight := range kernel {
+				if sx := x + k - radius; sx >= 0 && sx < width {
+					v += plane[y*width+sx] * weight
+				}
+			}
+			blurred[y*width+x] = v
+		}
+	}
+
+	result := make([]float64, len(plane))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := 0.0
+			for k, weight := range kernel {
+				if sy := y + k - radius; sy >= 0 && sy < height {
+					v += blurred[sy*width+x] * weight
+				}
+			}
+			result[y*width+x] = v
+		}
+	}
+	return result
+}
+
+func (f *dropShadow) needsAlpha() bool {
+	return true
+}
+
+func (f *dropShadow) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *dropShadow) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *dropShadow) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 4 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	s := &dropShadow{}
+
+	if s.offsetX, err = parse.EskipIntArg(args[0]); err != nil {
+		return nil, parse.AtArg(err, DropShadowName, 1, "offsetX")
+	}
+
+	if s.offsetY, err = parse.EskipIntArg(args[1]); err != nil {
+		return nil, parse.AtArg(err, DropShadowName, 2, "offsetY")
+	}
+
+	if s.blur, err = parse.EskipFloatArg(args[2]); err != nil {
+		return nil, parse.AtArg(err, DropShadowName, 3, "blur")
+	}
+	if s.blur < 0 {
+		log.Errorf("Failed to create the drop shadow filter, the blur %v is negative", s.blur)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c, err := parse.EskipColorArg(args[3])
+	if err != nil {
+		return nil, parse.AtArg(err, DropShadowName, 4, "color")
+	}
+	s.color = bimgColor(c)
+
+	return s, nil
+}
+
+func (f *dropShadow) Request(ctx filters.FilterContext) {}
+
+func (f *dropShadow) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
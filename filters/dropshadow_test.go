@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewDropShadow(t *testing.T) {
+	name := NewDropShadow().Name()
+	assert.Equal(t, "dropShadow", name)
+}
+
+func TestDropShadow_TransformPixels(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	f := &dropShadow{offsetX: 3, offsetY: 2, blur: 1, color: bimg.Color{}}
+
+	result, err := f.TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	//the 4x4 image grows by the offset and by 3 pixels of blur on every side
+	assert.Equal(t, image.Rect(0, 0, 4+3+6, 4+2+6), result.Bounds())
+	assert.Equal(t, c, result.NRGBAAt(3, 3), "the image should be drawn over the shadow")
+	assert.Equal(t, uint8(0), result.NRGBAAt(0, 0).A, "the corner opposite to the shadow should be transparent")
+
+	shadow := result.NRGBAAt(3+3+3, 3+2+3)
+	assert.Equal(t, uint8(0), shadow.R)
+	assert.True(t, shadow.A > 0, "the shadow should be visible next to the image")
+}
+
+func TestDropShadow_TransformPixels_NegativeOffset(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	f := &dropShadow{offsetX: -2, offsetY: -2, blur: 0, color: bimg.Color{R: 255}}
+
+	result, err := f.TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 6, 6), result.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, result.NRGBAAt(0, 0), "the hard shadow should be up and left")
+	assert.Equal(t, c, result.NRGBAAt(5, 5))
+}
+
+func TestDropShadow_NeedsAlpha(t *testing.T) {
+	f := dropShadow{}
+	assert.True(t, f.needsAlpha())
+}
+
+func TestDropShadow_CanBeMerged(t *testing.T) {
+	f := dropShadow{}
+	assert.False(t, f.CanBeMerged(&dropShadow{}))
+}
+
+func TestDropShadow_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewDropShadow, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "four args",
+		Args: []interface{}{5.0, 5.0, 3.0, "#000000"},
+		Err:  false,
+	}, {
+		Msg:  "negative offsets",
+		Args: []interface{}{-5.0, -5.0, 3.0, "#000"},
+		Err:  false,
+	}, {
+		Msg:  "negative blur",
+		Args: []interface{}{5.0, 5.0, -3.0, "#000"},
+		Err:  true,
+	}, {
+		Msg:  "invalid color",
+		Args: []interface{}{5.0, 5.0, 3.0, "black"},
+		Err:  true,
+	}, {
+		Msg:  "more than four args",
+		Args: []interface{}{5.0, 5.0, 3.0, "#000", 1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,137 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// EdgesName is the name of the filter
+const EdgesName = "edges"
+
+var (
+	sobelX = [9]float64{-1, 0, 1, -2, 0, 2, -1, 0, 1}
+	sobelY = [9]float64{-1, -2, -1, 0, 0, 0, 1, 2, 1}
+)
+
+type edges struct {
+	//the edges weaker than the threshold become black and the others white, 0 keeping their strength
+	threshold uint8
+	keepColor bool
+}
+
+// NewEdges creates a new filter of this type
+func NewEdges() filters.Spec {
+	return &edges{}
+}
+
+func (f *edges) Name() string {
+	return EdgesName
+}
+
+func (f *edges) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for edges ", f)
+
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	//the gradients are computed on the luminance, or on every channel to keep the color of the edges
+	planes := 1
+	if f.keepColor {
+		planes = 3
+	}
+	values := make([][]float64, planes)
+	for p := range values {
+		values[p] = make([]float64, width*height)
+	}
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*img.Stride:]
+		for x := 0; x < width; x++ {
+			i := x * 4
+			if f.keepColor {
+				for p := range values {
+					values[p][y*width+x] = float64(row[i+p])
+				}
+			} else {
+				values[0][y*width+x] = float64(luminance(row[i], row[i+1], row[i+2]))
+			}
+		}
+	}
+
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			o := y*result.Stride + x*4
+			for p, plane := range values {
+				var gx, gy float64
+				for k := range sobelX {
+					v := plane[clampInt(y+k/3-1, 0, height-1)*width+clampInt(x+k%3-1, 0, width-1)]
+					gx += v * sobelX[k]
+					gy += v * sobelY[k]
+				}
+				magnitude := clampUint8(math.Hypot(gx, gy))
+				if f.threshold > 0 {
+					if magnitude < f.threshold {
+						magnitude = 0
+					} else {
+						magnitude = 255
+					}
+				}
+				result.Pix[o+p] = magnitude
+			}
+			if !f.keepColor {
+				result.Pix[o+1], result.Pix[o+2] = result.Pix[o], result.Pix[o]
+			}
+			result.Pix[o+3] = 255
+		}
+	}
+
+	return result, nil
+}
+
+// CanBeMerged refuses merging, as the edges of an edge map are not the edges of the image
+func (f *edges) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *edges) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *edges) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) > 2 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	e := &edges{}
+
+	if len(args) >= 1 {
+		threshold, err := parse.EskipIntArg(args[0])
+		if err != nil {
+			return nil, parse.AtArg(err, EdgesName, 1, "threshold")
+		}
+		if threshold < 0 || threshold > 255 {
+			log.Errorf("Failed to create the edges filter, the threshold %d is not between 0 and 255", threshold)
+			return nil, filters.ErrInvalidFilterParameters
+		}
+		e.threshold = uint8(threshold)
+	}
+
+	if len(args) == 2 {
+		keepColor, err := parse.EskipBoolArg(args[1])
+		if err != nil {
+			return nil, parse.AtArg(err, EdgesName, 2, "keepColor")
+		}
+		e.keepColor = keepColor
+	}
+
+	return e, nil
+}
+
+func (f *edges) Request(ctx filters.FilterContext) {}
+
+func (f *edges) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
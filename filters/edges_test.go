@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// boundaryImage is a dark image whose right half is red
+func boundaryImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			c := color.NRGBA{R: 20, G: 20, B: 20, A: 255}
+			if x >= 4 {
+				c = color.NRGBA{R: 250, G: 20, B: 20, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestNewEdges(t *testing.T) {
+	name := NewEdges().Name()
+	assert.Equal(t, "edges", name)
+}
+
+func TestEdges_TransformPixels(t *testing.T) {
+	result, err := (&edges{}).TransformPixels(boundaryImage())
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{A: 255}, result.NRGBAAt(1, 1), "the flat region should be black")
+	assert.Equal(t, color.NRGBA{A: 255}, result.NRGBAAt(6, 1), "the flat region should be black")
+
+	edge := result.NRGBAAt(4, 1)
+	assert.True(t, edge.R > 200, "the boundary should be bright")
+	assert.Equal(t, edge.R, edge.G)
+	assert.Equal(t, edge.R, edge.B)
+}
+
+func TestEdges_TransformPixels_Threshold(t *testing.T) {
+	result, err := (&edges{threshold: 50}).TransformPixels(boundaryImage())
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, result.NRGBAAt(3, 1))
+	assert.Equal(t, color.NRGBA{A: 255}, result.NRGBAAt(0, 1))
+}
+
+func TestEdges_TransformPixels_KeepColor(t *testing.T) {
+	result, err := (&edges{keepColor: true}).TransformPixels(boundaryImage())
+
+	assert.Nil(t, err)
+	edge := result.NRGBAAt(4, 1)
+	assert.Equal(t, uint8(255), edge.R, "the red boundary should be red")
+	assert.Equal(t, uint8(0), edge.G)
+	assert.Equal(t, uint8(0), edge.B)
+}
+
+func TestEdges_CanBeMerged(t *testing.T) {
+	f := edges{}
+	assert.False(t, f.CanBeMerged(&edges{}))
+}
+
+func TestEdges_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewEdges, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "threshold",
+		Args: []interface{}{64.0},
+		Err:  false,
+	}, {
+		Msg:  "threshold and color",
+		Args: []interface{}{0.0, "true"},
+		Err:  false,
+	}, {
+		Msg:  "threshold out of range",
+		Args: []interface{}{300.0},
+		Err:  true,
+	}, {
+		Msg:  "invalid color flag",
+		Args: []interface{}{0.0, "color"},
+		Err:  true,
+	}, {
+		Msg:  "more than two args",
+		Args: []interface{}{0.0, "true", 1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,158 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// EmbedName is the name of the filter
+const EmbedName = "embed"
+
+type embed struct {
+	width             int
+	height            int
+	verticalGravity   bimg.Gravity
+	horizontalGravity bimg.Gravity
+	background        bimg.Color
+}
+
+// embedCanvas places the image on the canvas of the embed filter
+type embedCanvas embed
+
+// NewEmbed creates a new filter of this type
+func NewEmbed() filters.Spec {
+	return &embed{}
+}
+
+func (f *embed) Name() string {
+	return EmbedName
+}
+
+func (f *embed) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for embed ", f)
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	//the image already fits in the canvas, so it is only placed on it
+	if size.Width <= f.width && size.Height <= f.height {
+		return &bimg.Options{}, nil
+	}
+
+	//otherwise it is resized to fit, keeping the ratio
+	ht := int(math.Floor(float64(size.Height*f.width) / float64(size.Width)))
+	if ht <= f.height {
+		return &bimg.Options{
+			Width: f.width}, nil
+	}
+	return &bimg.Options{
+		Height: f.height}, nil
+}
+
+func (f *embed) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return other.Width == 0 && other.Height == 0 && !other.Crop && other.AreaWidth == 0 && other.AreaHeight == 0
+}
+
+func (f *embed) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Width = self.Width
+	other.Height = self.Height
+	return other
+}
+
+func (f *embedCanvas) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for embed ", f)
+
+	bounds := img.Bounds()
+	canvas := image.NewNRGBA(image.Rect(0, 0, f.width, f.height))
+	background := color.NRGBA{R: f.background.R, G: f.background.G, B: f.background.B, A: 255}
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.ZP, draw.Src)
+
+	position := image.Pt(
+		gravityOffset(f.horizontalGravity, bimg.GravityWest, bimg.GravityEast, f.width-bounds.Dx()),
+		gravityOffset(f.verticalGravity, bimg.GravityNorth, bimg.GravitySouth, f.height-bounds.Dy()))
+	draw.Draw(canvas, bounds.Sub(bounds.Min).Add(position), img, bounds.Min, draw.Over)
+
+	return canvas, nil
+}
+
+// gravityOffset returns where the image starts on one axis of the canvas, given the space left
+func gravityOffset(gravity, start, end bimg.Gravity, space int) int {
+	switch gravity {
+	case start:
+		return 0
+	case end:
+		return space
+	default:
+		return space / 2
+	}
+}
+
+func (f *embedCanvas) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *embedCanvas) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *embed) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 4 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	e := &embed{}
+
+	e.width, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	e.height, err = parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if e.width <= 0 || e.height <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	gravity, err := parse.EskipStringArg(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if !gravityType[gravity] {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+	e.verticalGravity = verticalGravity[gravity]
+	e.horizontalGravity = horizontalGravity[gravity]
+
+	c, err := parse.EskipColorArg(args[3])
+	if err != nil {
+		return nil, err
+	}
+	e.background = bimgColor(c)
+
+	return e, nil
+}
+
+func (f *embed) Request(ctx filters.FilterContext) {}
+
+func (f *embed) Response(ctx filters.FilterContext) {
+	//bimg only embeds in the centre and does not extend the canvas of smaller images, so the image is resized
+	//to fit and then placed on the canvas
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+	HandlePixelResponse(ctx, (*embedCanvas)(f))
+}
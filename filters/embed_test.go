@@ -0,0 +1,127 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewEmbed(t *testing.T) {
+	name := NewEmbed().Name()
+	assert.Equal(t, "embed", name)
+}
+
+func TestEmbed_Name(t *testing.T) {
+	c := embed{}
+	assert.Equal(t, "embed", c.Name())
+}
+
+func TestEmbed_CreateOptions_Fits(t *testing.T) {
+	c := embed{width: 2000, height: 2000}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
+
+	assert.Equal(t, &bimg.Options{}, options)
+}
+
+func TestEmbed_CreateOptions_Landscape(t *testing.T) {
+	c := embed{width: 200, height: 200}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
+
+	assert.Equal(t, 200, options.Width)
+	assert.Equal(t, 0, options.Height)
+}
+
+func TestEmbed_CreateOptions_Portrait(t *testing.T) {
+	c := embed{width: 200, height: 200}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.PortraitImage()))
+
+	assert.Equal(t, 0, options.Width)
+	assert.Equal(t, 200, options.Height)
+}
+
+func TestEmbed_CanBeMerged(t *testing.T) {
+	c := embed{}
+	self := &bimg.Options{Width: 200}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Quality: 80}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 300, Height: 300, Crop: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Height: 300}, self))
+}
+
+func TestEmbed_Merge(t *testing.T) {
+	c := embed{}
+	merged := c.Merge(&bimg.Options{Quality: 80}, &bimg.Options{Width: 200})
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Width: 200}, merged)
+}
+
+func TestEmbedCanvas_TransformPixels(t *testing.T) {
+	inside := color.NRGBA{R: 10, G: 20, B: 30, A: 255}
+	red := color.NRGBA{R: 255, A: 255}
+
+	for _, item := range []struct {
+		gravity string
+		min     image.Point
+		outside image.Point
+	}{
+		{NW, image.Pt(0, 0), image.Pt(4, 4)},
+		{NE, image.Pt(6, 0), image.Pt(5, 0)},
+		{CC, image.Pt(3, 2), image.Pt(2, 2)},
+		{SC, image.Pt(3, 4), image.Pt(3, 3)},
+		{SW, image.Pt(0, 4), image.Pt(0, 3)},
+	} {
+		f := embedCanvas{
+			width:             10,
+			height:            8,
+			verticalGravity:   verticalGravity[item.gravity],
+			horizontalGravity: horizontalGravity[item.gravity],
+			background:        bimg.Color{R: 255},
+		}
+
+		result, err := f.TransformPixels(uniformImage(inside))
+
+		assert.Nil(t, err)
+		assert.Equal(t, image.Rect(0, 0, 10, 8), result.Bounds())
+		assert.Equal(t, inside, result.NRGBAAt(item.min.X, item.min.Y), item.gravity)
+		assert.Equal(t, inside, result.NRGBAAt(item.min.X+3, item.min.Y+3), item.gravity)
+		assert.Equal(t, red, result.NRGBAAt(item.outside.X, item.outside.Y), item.gravity)
+	}
+}
+
+func TestEmbedCanvas_CanBeMerged(t *testing.T) {
+	f := embedCanvas{width: 10, height: 10}
+	assert.False(t, f.CanBeMerged(&embedCanvas{width: 10, height: 10}))
+}
+
+func TestEmbed_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewEmbed, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "four args",
+		Args: []interface{}{200.0, 100.0, "CC", "#ffffff"},
+		Err:  false,
+	}, {
+		Msg:  "invalid gravity",
+		Args: []interface{}{200.0, 100.0, "XX", "#ffffff"},
+		Err:  true,
+	}, {
+		Msg:  "invalid color",
+		Args: []interface{}{200.0, 100.0, "CC", "white"},
+		Err:  true,
+	}, {
+		Msg:  "zero width",
+		Args: []interface{}{0.0, 100.0, "CC", "#ffffff"},
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{200.0, 100.0, "CC"},
+		Err:  true,
+	}})
+}
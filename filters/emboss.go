@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// EmbossName is the name of the filter
+const EmbossName = "emboss"
+
+// embossKernel lights the edges from the bottom right. Its weights sum to 0, so the flat regions become the mid-gray
+// of the offset
+var embossKernel = convolve{
+	kernel: [9]float64{-1, -1, 0, -1, 0, 1, 0, 1, 1},
+	scale:  1,
+	offset: 128,
+}
+
+type emboss struct {
+	grayscale bool
+}
+
+// NewEmboss creates a new filter of this type
+func NewEmboss() filters.Spec {
+	return &emboss{}
+}
+
+func (f *emboss) Name() string {
+	return EmbossName
+}
+
+func (f *emboss) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for emboss ", f)
+
+	result, err := embossKernel.TransformPixels(img)
+	if err != nil || !f.grayscale {
+		return result, err
+	}
+
+	for y := 0; y < result.Rect.Dy(); y++ {
+		row := result.Pix[y*result.Stride : y*result.Stride+result.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			gray := luminance(row[i], row[i+1], row[i+2])
+			row[i], row[i+1], row[i+2] = gray, gray, gray
+		}
+	}
+
+	return result, nil
+}
+
+// CanBeMerged refuses merging, as embossing an embossed image highlights its edges again
+func (f *emboss) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *emboss) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *emboss) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) > 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	e := &emboss{}
+
+	if len(args) == 1 {
+		if e.grayscale, err = parse.EskipBoolArg(args[0]); err != nil {
+			return nil, parse.AtArg(err, EmbossName, 1, "grayscale")
+		}
+	}
+
+	return e, nil
+}
+
+func (f *emboss) Request(ctx filters.FilterContext) {}
+
+func (f *emboss) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
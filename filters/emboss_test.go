@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// splitImage is a black image whose bottom right quarter is white
+func splitImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c := color.NRGBA{A: 255}
+			if x >= 4 && y >= 4 {
+				c = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestNewEmboss(t *testing.T) {
+	name := NewEmboss().Name()
+	assert.Equal(t, "emboss", name)
+}
+
+func TestEmboss_TransformPixels(t *testing.T) {
+	result, err := (&emboss{}).TransformPixels(splitImage())
+
+	assert.Nil(t, err)
+	neutral := color.NRGBA{R: 128, G: 128, B: 128, A: 255}
+	assert.Equal(t, neutral, result.NRGBAAt(1, 1), "the flat black region should become mid-gray")
+	assert.Equal(t, neutral, result.NRGBAAt(6, 6), "the flat white region should become mid-gray")
+	assert.True(t, result.NRGBAAt(3, 3).R > 128, "the edge facing the light should be highlighted")
+}
+
+func TestEmboss_TransformPixels_Grayscale(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	img := uniformImage(c)
+	img.SetNRGBA(3, 3, color.NRGBA{R: 255, G: 0, B: 0, A: 255})
+
+	result, err := (&emboss{grayscale: true}).TransformPixels(img)
+
+	assert.Nil(t, err)
+	p := result.NRGBAAt(2, 2)
+	assert.Equal(t, p.R, p.G)
+	assert.Equal(t, p.G, p.B)
+}
+
+func TestEmboss_CanBeMerged(t *testing.T) {
+	f := emboss{}
+	assert.False(t, f.CanBeMerged(&emboss{}))
+}
+
+func TestEmboss_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewEmboss, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "grayscale",
+		Args: []interface{}{"true"},
+		Err:  false,
+	}, {
+		Msg:  "not a boolean",
+		Args: []interface{}{"gray"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"true", "false"},
+		Err:  true,
+	}})
+}
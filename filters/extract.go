@@ -0,0 +1,105 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ExtractName is the name of the filter
+const ExtractName = "extract"
+
+type extract struct {
+	top    int
+	left   int
+	width  int
+	height int
+}
+
+// NewExtract creates a new filter of this type
+func NewExtract() filters.Spec {
+	return &extract{}
+}
+
+func (f *extract) Name() string {
+	return ExtractName
+}
+
+func (f *extract) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for extract ", f)
+
+	return &bimg.Options{
+		Top:        f.top,
+		Left:       f.left,
+		AreaWidth:  f.width,
+		AreaHeight: f.height}, nil
+}
+
+func (f *extract) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return extractCanBeMerged(other, self)
+}
+
+func extractCanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//bimg does only one of crop, embed, trim and extract, and extracts before the watermarks
+	return !other.Crop && !other.Embed && !other.Trim &&
+		len(other.WatermarkImage.Buf) == 0 && other.Watermark.Text == "" &&
+		((other.Top == 0 && other.Left == 0 && other.AreaWidth == 0 && other.AreaHeight == 0) ||
+			(other.Top == self.Top && other.Left == self.Left && other.AreaWidth == self.AreaWidth && other.AreaHeight == self.AreaHeight))
+}
+
+// extractPending checks if an extraction is waiting to be applied. bimg resizes and crops before extracting, so the
+// filters coming after an extraction in the route need to be applied on its result
+func extractPending(other *bimg.Options) bool {
+	return other.Top != 0 || other.Left != 0 || other.AreaWidth != 0 || other.AreaHeight != 0
+}
+
+func (f *extract) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Top = self.Top
+	other.Left = self.Left
+	other.AreaWidth = self.AreaWidth
+	other.AreaHeight = self.AreaHeight
+	return other
+}
+
+func (f *extract) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 4 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	e := &extract{}
+
+	e.top, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	e.left, err = parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	e.width, err = parse.EskipIntArg(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	e.height, err = parse.EskipIntArg(args[3])
+	if err != nil {
+		return nil, err
+	}
+
+	if e.top < 0 || e.left < 0 || e.width <= 0 || e.height <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return e, nil
+}
+
+func (f *extract) Request(ctx filters.FilterContext) {}
+
+func (f *extract) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
@@ -0,0 +1,120 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewExtract(t *testing.T) {
+	name := NewExtract().Name()
+	assert.Equal(t, "extract", name)
+}
+
+func TestExtract_Name(t *testing.T) {
+	c := extract{}
+	assert.Equal(t, "extract", c.Name())
+}
+
+func TestExtract_CreateOptions(t *testing.T) {
+	c := extract{top: 10, left: 20, width: 30, height: 40}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
+
+	assert.Equal(t, &bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}, options)
+}
+
+func TestExtract_ExtractsRegion(t *testing.T) {
+	//a checkerboard of 10x10 black and white squares
+	pixels := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			if (x/10+y/10)%2 == 0 {
+				pixels.SetNRGBA(x, y, color.NRGBA{A: 255})
+			} else {
+				pixels.SetNRGBA(x, y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+			}
+		}
+	}
+	buf, err := encodePixels(pixels)
+	assert.Nil(t, err)
+
+	//the white square in the second column of the first row
+	c := extract{top: 0, left: 10, width: 10, height: 10}
+	options, _ := c.CreateOptions(buildParameters(nil, bimg.NewImage(buf)))
+	options.Type = bimg.PNG
+	result, err := transformImage(bimg.NewImage(buf), options, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extracted, err := decodePixels(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, image.Rect(0, 0, 10, 10), extracted.Bounds())
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, extracted.NRGBAAt(x, y))
+		}
+	}
+}
+
+func TestExtract_CanBeMerged(t *testing.T) {
+	c := extract{}
+	self := &bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Width: 300}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 300, Height: 300, Crop: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Top: 0, Left: 20, AreaWidth: 30, AreaHeight: 40}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Trim: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte{1}}}, self))
+}
+
+func TestExtract_Merge(t *testing.T) {
+	c := extract{}
+	merged := c.Merge(&bimg.Options{Quality: 80}, &bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40})
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}, merged)
+}
+
+func TestExtract_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewExtract, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "four args",
+		Args: []interface{}{0.0, 10.0, 100.0, 200.0},
+		Err:  false,
+	}, {
+		Msg:  "negative top",
+		Args: []interface{}{-1.0, 10.0, 100.0, 200.0},
+		Err:  true,
+	}, {
+		Msg:  "negative left",
+		Args: []interface{}{0.0, -10.0, 100.0, 200.0},
+		Err:  true,
+	}, {
+		Msg:  "zero width",
+		Args: []interface{}{0.0, 10.0, 0.0, 200.0},
+		Err:  true,
+	}, {
+		Msg:  "zero height",
+		Args: []interface{}{0.0, 10.0, 100.0, 0.0},
+		Err:  true,
+	}, {
+		Msg:  "not an int",
+		Args: []interface{}{0.5, 10.0, 100.0, 200.0},
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{0.0, 10.0, 100.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ExtractChannelName is the name of the filter
+const ExtractChannelName = "extractChannel"
+
+var (
+	channels = map[string]bool{"r": true, "g": true, "b": true, "a": true}
+	//the offsets of the channels in the NRGBA pixels
+	channelOffsets = map[string]int{"r": 0, "g": 1, "b": 2, "a": 3}
+)
+
+type extractChannel struct {
+	channel string
+}
+
+// NewExtractChannel creates a new filter of this type
+func NewExtractChannel() filters.Spec {
+	return &extractChannel{}
+}
+
+func (f *extractChannel) Name() string {
+	return ExtractChannelName
+}
+
+func (f *extractChannel) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for extract channel ", f)
+
+	//the value of the channel becomes the gray of an opaque pixel
+	offset := channelOffsets[f.channel]
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			value := row[i+offset]
+			row[i], row[i+1], row[i+2], row[i+3] = value, value, value, 255
+		}
+	}
+
+	return img, nil
+}
+
+// CanBeMerged refuses merging, as the other channels are lost
+func (f *extractChannel) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *extractChannel) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *extractChannel) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	e := &extractChannel{}
+
+	if e.channel, err = parse.EskipEnumArg(args[0], channels); err != nil {
+		return nil, parse.AtArg(err, ExtractChannelName, 1, "channel")
+	}
+
+	return e, nil
+}
+
+func (f *extractChannel) Request(ctx filters.FilterContext) {}
+
+func (f *extractChannel) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
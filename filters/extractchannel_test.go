@@ -0,0 +1,68 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewExtractChannel(t *testing.T) {
+	name := NewExtractChannel().Name()
+	assert.Equal(t, "extractChannel", name)
+}
+
+func TestExtractChannel_TransformPixels_Red(t *testing.T) {
+	red := color.NRGBA{R: 255, G: 0, B: 0, A: 255}
+
+	result, err := (&extractChannel{channel: "r"}).TransformPixels(uniformImage(red))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(color.NRGBA{R: 255, G: 255, B: 255, A: 255}), result)
+}
+
+func TestExtractChannel_TransformPixels(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 128}
+
+	for channel, expected := range map[string]uint8{"r": 200, "g": 100, "b": 50, "a": 128} {
+		result, err := (&extractChannel{channel: channel}).TransformPixels(uniformImage(c))
+
+		assert.Nil(t, err)
+		assert.Equal(t, color.NRGBA{R: expected, G: expected, B: expected, A: 255}, result.NRGBAAt(1, 1), channel)
+	}
+}
+
+func TestExtractChannel_AfterResize(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	HandleImageResponse(fc, &resize{width: 100, height: 100})
+	HandlePixelResponse(fc, &extractChannel{channel: "g"})
+
+	assert.Equal(t, true, fc.FStateBag[hasMergedFilters])
+	assert.Equal(t, []PixelFilter{&extractChannel{channel: "g"}}, fc.FStateBag[skropPixelFilters])
+}
+
+func TestExtractChannel_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewExtractChannel, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "red",
+		Args: []interface{}{"r"},
+		Err:  false,
+	}, {
+		Msg:  "alpha",
+		Args: []interface{}{"a"},
+		Err:  false,
+	}, {
+		Msg:  "unknown channel",
+		Args: []interface{}{"red"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"r", "g"},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/h2non/bimg"
+	"github.com/zalando/skipper/filters"
+)
+
+var (
+	fallbackMutex sync.RWMutex
+	//there is no fallback image by default, the errors are served
+	fallbackImage []byte
+)
+
+// SetFallbackImage sets the image served instead of the error response when the image cannot be processed, with
+// the status 203. Only the processing failures are replaced, the errors of the client and the overloads are still
+// served. nil disables the fallback
+func SetFallbackImage(buf []byte) error {
+	if len(buf) > 0 && !bimg.IsTypeSupported(bimg.DetermineImageType(buf)) {
+		return errors.New("the type of the fallback image is not supported")
+	}
+
+	fallbackMutex.Lock()
+	defer fallbackMutex.Unlock()
+	fallbackImage = buf
+	return nil
+}
+
+// SetFallbackImageFile sets the fallback image from a file
+func SetFallbackImageFile(path string) error {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return SetFallbackImage(buf)
+}
+
+// fallbackResponse returns the response with the fallback image replacing the error response, or nil when there is
+// no fallback image or when the error is not a processing failure
+func fallbackResponse(rsp *http.Response) *http.Response {
+	if rsp.StatusCode != http.StatusInternalServerError && rsp.StatusCode != http.StatusUnsupportedMediaType {
+		return nil
+	}
+
+	fallbackMutex.RLock()
+	buf := fallbackImage
+	fallbackMutex.RUnlock()
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "image/"+bimg.ImageTypeName(bimg.DetermineImageType(buf)))
+	header.Set("Content-Length", strconv.Itoa(len(buf)))
+
+	return &http.Response{
+		StatusCode:    http.StatusNonAuthoritativeInfo,
+		Header:        header,
+		ContentLength: int64(len(buf)),
+		Body:          ioutil.NopCloser(bytes.NewReader(buf)),
+	}
+}
+
+// serveError serves the error response, or the fallback image instead of it
+func serveError(ctx filters.FilterContext, rsp *http.Response) {
+	if fallback := fallbackResponse(rsp); fallback != nil {
+		ctx.StateBag()[skropFallback] = true
+		rsp = fallback
+	}
+	ctx.Serve(rsp)
+}
+
+// processingStopped tells if the image is not processed anymore, as the backend or a previous filter reported an
+// error, or the fallback image was served
+func processingStopped(ctx filters.FilterContext) bool {
+	return ctx.Response().StatusCode > 300 || ctx.StateBag()[skropFallback] == true
+}
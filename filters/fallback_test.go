@@ -0,0 +1,75 @@
+package filters
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func withFallback(t *testing.T) []byte {
+	buf, err := bimg.Read(imagefiltertest.PNGImageFile)
+	assert.Nil(t, err)
+	assert.Nil(t, SetFallbackImage(buf))
+	return buf
+}
+
+func TestSetFallbackImage_Unsupported(t *testing.T) {
+	assert.NotNil(t, SetFallbackImage([]byte("this is not an image")))
+	assert.Nil(t, SetFallbackImage(nil))
+}
+
+func TestHandleImageResponse_Fallback(t *testing.T) {
+	fallback := withFallback(t)
+	defer SetFallbackImage(nil)
+
+	buf, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(buf[:20]))
+
+	err := HandleImageResponse(fc, &crop{width: 100, height: 100, cropType: Center})
+	assert.NotNil(t, err)
+
+	//the following filters do not process the fallback image
+	err = HandleImageResponse(fc, &resize{width: 10, height: 10})
+	assert.NotNil(t, err)
+	FinalizeResponse(fc)
+
+	assert.Equal(t, http.StatusNonAuthoritativeInfo, fc.Response().StatusCode)
+	assert.Equal(t, "image/png", fc.Response().Header.Get("Content-Type"))
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, fallback, body)
+}
+
+func TestHandleImageResponse_FallbackUnsupportedType(t *testing.T) {
+	fallback := withFallback(t)
+	defer SetFallbackImage(nil)
+
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewBufferString("this is not an image"))
+
+	HandleImageResponse(fc, &crop{width: 100, height: 100, cropType: Center})
+
+	assert.Equal(t, http.StatusNonAuthoritativeInfo, fc.Response().StatusCode)
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, fallback, body)
+}
+
+func TestHandleImageResponse_FallbackNotForClientErrors(t *testing.T) {
+	withFallback(t)
+	defer SetFallbackImage(nil)
+
+	fc := createDefaultContext(t, "url")
+
+	HandleImageResponse(fc, &cropByFocalPoint{targetX: 0.5, targetY: 0.5, aspectRatio: 1.5})
+
+	assert.Equal(t, http.StatusBadRequest, fc.Response().StatusCode)
+}
+
+func TestFallbackResponse_Disabled(t *testing.T) {
+	assert.Nil(t, fallbackResponse(errorResponse()))
+}
@@ -0,0 +1,126 @@
+package filters
+
+import (
+	"math"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// FitName is the name of the filter
+const FitName = "fit"
+
+// Fit modes
+const (
+	Cover   = "cover"
+	Contain = "contain"
+	Fill    = "fill"
+)
+
+var fitModes = map[string]bool{
+	Cover:   true,
+	Contain: true,
+	Fill:    true,
+}
+
+type fit struct {
+	width  int
+	height int
+	mode   string
+}
+
+// NewFit creates a new filter of this type
+func NewFit() filters.Spec {
+	return &fit{}
+}
+
+func (f *fit) Name() string {
+	return FitName
+}
+
+func (f *fit) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for fit ", f)
+
+	switch f.mode {
+	case Cover:
+		return &bimg.Options{
+			Width:   f.width,
+			Height:  f.height,
+			Gravity: bimg.GravityCentre,
+			Crop:    true}, nil
+	case Fill:
+		return &bimg.Options{
+			Width:  f.width,
+			Height: f.height,
+			Force:  true}, nil
+	}
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	//the image is scaled by the edge needing the bigger reduction, so that both edges are inside the box
+	factor := math.Min(float64(f.width)/float64(size.Width), float64(f.height)/float64(size.Height))
+
+	return &bimg.Options{
+		Width:  scaleDimension(size.Width, factor),
+		Height: scaleDimension(size.Height, factor),
+		Force:  true}, nil
+}
+
+func (f *fit) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//every mode decides on its own how the image is cropped
+	return other.AreaWidth == 0 && other.AreaHeight == 0 && !other.Crop && !other.Embed && !other.Trim &&
+		((other.Width == 0 && other.Height == 0) ||
+			(other.Width == self.Width && other.Height == self.Height && other.Force == self.Force))
+}
+
+func (f *fit) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Width = self.Width
+	other.Height = self.Height
+	other.Gravity = self.Gravity
+	other.Crop = self.Crop
+	other.Force = self.Force
+	return other
+}
+
+func (f *fit) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 3 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &fit{}
+
+	c.width, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c.height, err = parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if c.width <= 0 || c.height <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	if mode, ok := args[2].(string); ok && fitModes[mode] {
+		c.mode = mode
+	} else {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return c, nil
+}
+
+func (f *fit) Request(ctx filters.FilterContext) {}
+
+func (f *fit) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
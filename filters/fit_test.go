@@ -0,0 +1,111 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewFit(t *testing.T) {
+	name := NewFit().Name()
+	assert.Equal(t, "fit", name)
+}
+
+func TestFit_Name(t *testing.T) {
+	f := fit{}
+	assert.Equal(t, "fit", f.Name())
+}
+
+func TestFit_CreateOptions_Cover(t *testing.T) {
+	f := fit{width: 100, height: 100, mode: Cover}
+	options, _ := f.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, 100, options.Width)
+	assert.Equal(t, 100, options.Height)
+	assert.True(t, options.Crop)
+	assert.Equal(t, bimg.GravityCentre, options.Gravity)
+}
+
+func TestFit_CreateOptions_Contain(t *testing.T) {
+	f := fit{width: 100, height: 100, mode: Contain}
+	options, _ := f.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, 100, options.Width)
+	assert.Equal(t, 75, options.Height)
+	assert.False(t, options.Crop)
+}
+
+func TestFit_CreateOptions_ContainPortrait(t *testing.T) {
+	f := fit{width: 100, height: 100, mode: Contain}
+	options, _ := f.CreateOptions(buildParameters(nil, detailedImage(300, 400, 300)))
+
+	assert.Equal(t, 75, options.Width)
+	assert.Equal(t, 100, options.Height)
+}
+
+func TestFit_CreateOptions_Fill(t *testing.T) {
+	f := fit{width: 100, height: 100, mode: Fill}
+	options, _ := f.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, 100, options.Width)
+	assert.Equal(t, 100, options.Height)
+	assert.True(t, options.Force)
+	assert.False(t, options.Crop)
+}
+
+func TestFit_CanBeMerged(t *testing.T) {
+	f := fit{}
+	self := &bimg.Options{Width: 100, Height: 100, Force: true}
+
+	assert.True(t, f.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, f.CanBeMerged(&bimg.Options{Width: 100, Height: 100, Force: true}, self))
+	assert.False(t, f.CanBeMerged(&bimg.Options{Width: 100, Height: 100, Crop: true}, self))
+	assert.False(t, f.CanBeMerged(&bimg.Options{Width: 200, Height: 100, Force: true}, self))
+}
+
+func TestFit_Merge(t *testing.T) {
+	f := fit{}
+	self := &bimg.Options{Width: 100, Height: 100, Gravity: bimg.GravityCentre, Crop: true}
+
+	merged := f.Merge(&bimg.Options{Quality: 80}, self)
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Width: 100, Height: 100, Gravity: bimg.GravityCentre, Crop: true}, merged)
+}
+
+func TestFit_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewFit, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "two args",
+		Args: []interface{}{100.0, 100.0},
+		Err:  true,
+	}, {
+		Msg:  "cover",
+		Args: []interface{}{100.0, 100.0, Cover},
+		Err:  false,
+	}, {
+		Msg:  "contain",
+		Args: []interface{}{100.0, 100.0, Contain},
+		Err:  false,
+	}, {
+		Msg:  "fill",
+		Args: []interface{}{100.0, 100.0, Fill},
+		Err:  false,
+	}, {
+		Msg:  "invalid mode",
+		Args: []interface{}{100.0, 100.0, "stretch"},
+		Err:  true,
+	}, {
+		Msg:  "zero width",
+		Args: []interface{}{0.0, 100.0, Cover},
+		Err:  true,
+	}, {
+		Msg:  "more than three args",
+		Args: []interface{}{100.0, 100.0, Cover, Cover},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,52 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// FlipName is the name of the filter
+const FlipName = "flip"
+
+type flip struct{}
+
+// NewFlip creates a new filter of this type
+func NewFlip() filters.Spec {
+	return &flip{}
+}
+
+func (f *flip) Name() string {
+	return FlipName
+}
+
+func (f *flip) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for flip ", f)
+
+	return &bimg.Options{
+		Flip: true}, nil
+}
+
+func (f *flip) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return orientationCanBeMerged(other)
+}
+
+func (f *flip) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	//two flips cancel each other
+	other.Flip = other.Flip != self.Flip
+	return other
+}
+
+func (f *flip) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &flip{}, nil
+}
+
+func (f *flip) Request(ctx filters.FilterContext) {}
+
+func (f *flip) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
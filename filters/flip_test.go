@@ -0,0 +1,74 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewFlip(t *testing.T) {
+	name := NewFlip().Name()
+	assert.Equal(t, "flip", name)
+}
+
+func TestFlip_Name(t *testing.T) {
+	c := flip{}
+	assert.Equal(t, "flip", c.Name())
+}
+
+func TestFlip_CreateOptions(t *testing.T) {
+	f := flip{}
+	image := imagefiltertest.LandscapeImage()
+	options, _ := f.CreateOptions(buildParameters(nil, image))
+
+	assert.True(t, options.Flip)
+}
+
+func TestFlip_CanBeMerged_True(t *testing.T) {
+	s := flip{}
+	opt := &bimg.Options{Rotate: bimg.D90, Quality: 80}
+	self := &bimg.Options{Flip: true}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestFlip_CanBeMerged_AfterCrop(t *testing.T) {
+	s := flip{}
+	opt := &bimg.Options{Width: 100, Height: 200, Crop: true}
+	self := &bimg.Options{Flip: true}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestFlip_CanBeMerged_AfterOverlay(t *testing.T) {
+	s := flip{}
+	opt := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte{1, 2, 3}}}
+	self := &bimg.Options{Flip: true}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestFlip_Merge(t *testing.T) {
+	s := flip{}
+	self := &bimg.Options{Flip: true}
+
+	opt := s.Merge(&bimg.Options{}, self)
+	assert.True(t, opt.Flip)
+
+	opt = s.Merge(opt, self)
+	assert.False(t, opt.Flip, "two flips should cancel each other")
+}
+
+func TestFlip_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewFlip, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,52 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// FlopName is the name of the filter
+const FlopName = "flop"
+
+type flop struct{}
+
+// NewFlop creates a new filter of this type
+func NewFlop() filters.Spec {
+	return &flop{}
+}
+
+func (f *flop) Name() string {
+	return FlopName
+}
+
+func (f *flop) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for flop ", f)
+
+	return &bimg.Options{
+		Flop: true}, nil
+}
+
+func (f *flop) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return orientationCanBeMerged(other)
+}
+
+func (f *flop) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	//two flops cancel each other
+	other.Flop = other.Flop != self.Flop
+	return other
+}
+
+func (f *flop) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &flop{}, nil
+}
+
+func (f *flop) Request(ctx filters.FilterContext) {}
+
+func (f *flop) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
@@ -0,0 +1,74 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewFlop(t *testing.T) {
+	name := NewFlop().Name()
+	assert.Equal(t, "flop", name)
+}
+
+func TestFlop_Name(t *testing.T) {
+	c := flop{}
+	assert.Equal(t, "flop", c.Name())
+}
+
+func TestFlop_CreateOptions(t *testing.T) {
+	f := flop{}
+	image := imagefiltertest.LandscapeImage()
+	options, _ := f.CreateOptions(buildParameters(nil, image))
+
+	assert.True(t, options.Flop)
+}
+
+func TestFlop_CanBeMerged_True(t *testing.T) {
+	s := flop{}
+	opt := &bimg.Options{Rotate: bimg.D90, Quality: 80}
+	self := &bimg.Options{Flop: true}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestFlop_CanBeMerged_AfterCrop(t *testing.T) {
+	s := flop{}
+	opt := &bimg.Options{Width: 100, Height: 200, Crop: true}
+	self := &bimg.Options{Flop: true}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestFlop_CanBeMerged_AfterOverlay(t *testing.T) {
+	s := flop{}
+	opt := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte{1, 2, 3}}}
+	self := &bimg.Options{Flop: true}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestFlop_Merge(t *testing.T) {
+	s := flop{}
+	self := &bimg.Options{Flop: true}
+
+	opt := s.Merge(&bimg.Options{}, self)
+	assert.True(t, opt.Flop)
+
+	opt = s.Merge(opt, self)
+	assert.False(t, opt.Flop, "two flops should cancel each other")
+}
+
+func TestFlop_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewFlop, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// GammaName is the name of the filter
+const GammaName = "gamma"
+
+type gamma struct {
+	value float64
+}
+
+// NewGamma creates a new filter of this type
+func NewGamma() filters.Spec {
+	return &gamma{}
+}
+
+func (f *gamma) Name() string {
+	return GammaName
+}
+
+func (f *gamma) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for gamma ", f)
+
+	if f.value == 1 {
+		return img, nil
+	}
+
+	//values greater than 1 brighten the midtones, values smaller than 1 darken them
+	var table [256]uint8
+	for i := range table {
+		table[i] = clampUint8(255 * math.Pow(float64(i)/255, 1/f.value))
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+func (f *gamma) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*gamma)
+	return ok
+}
+
+func (f *gamma) Merge(other PixelFilter) PixelFilter {
+	return &gamma{value: other.(*gamma).value * f.value}
+}
+
+func (f *gamma) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	g := &gamma{}
+
+	g.value, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if g.value <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return g, nil
+}
+
+func (f *gamma) Request(ctx filters.FilterContext) {}
+
+func (f *gamma) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,86 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewGamma(t *testing.T) {
+	name := NewGamma().Name()
+	assert.Equal(t, "gamma", name)
+}
+
+func TestGamma_Name(t *testing.T) {
+	c := gamma{}
+	assert.Equal(t, "gamma", c.Name())
+}
+
+func TestGamma_TransformPixels(t *testing.T) {
+	f := gamma{value: 2}
+	img := uniformImage(color.NRGBA{R: 0, G: 64, B: 255, A: 255})
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 0, G: 128, B: 255, A: 255}, result.NRGBAAt(0, 0))
+}
+
+func TestGamma_CanBeMerged_True(t *testing.T) {
+	f := gamma{value: 1.5}
+	assert.True(t, f.CanBeMerged(&gamma{value: 2.2}))
+}
+
+func TestGamma_CanBeMerged_False(t *testing.T) {
+	f := gamma{value: 1.5}
+	assert.False(t, f.CanBeMerged(&contrast{factor: 1.5}))
+}
+
+func TestGamma_Merge(t *testing.T) {
+	f := gamma{value: 1.5}
+
+	merged := f.Merge(&gamma{value: 2})
+
+	assert.Equal(t, 3.0, merged.(*gamma).value)
+}
+
+func TestGamma_Merge_SameAsSequential(t *testing.T) {
+	first := gamma{value: 2}
+	second := gamma{value: 0.8}
+
+	intermediate, _ := first.TransformPixels(uniformImage(color.NRGBA{R: 200, G: 120, B: 40, A: 255}))
+	sequential, _ := second.TransformPixels(intermediate)
+	merged, _ := second.Merge(&first).TransformPixels(uniformImage(color.NRGBA{R: 200, G: 120, B: 40, A: 255}))
+
+	assert.InDelta(t, sequential.NRGBAAt(0, 0).G, merged.NRGBAAt(0, 0).G, 1)
+}
+
+func TestGamma_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewGamma, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{2.2},
+		Err:  false,
+	}, {
+		Msg:  "zero",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "negative",
+		Args: []interface{}{-1.0},
+		Err:  true,
+	}, {
+		Msg:  "type error",
+		Args: []interface{}{"2.2"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{1.2, 1.3},
+		Err:  true,
+	}})
+}
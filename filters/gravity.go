@@ -0,0 +1,62 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+)
+
+const (
+	// NE North East
+	NE = "NE"
+	// NC North Center
+	NC = "NC"
+	// NW North West
+	NW = "NW"
+	// CE Centre East
+	CE = "CE"
+	// CC Centre Center
+	CC = "CC"
+	// CW Centre West
+	CW = "CW"
+	// SE South East
+	SE = "SE"
+	// SC South Center
+	SC = "SC"
+	// SW South West
+	SW = "SW"
+)
+
+var (
+	gravityType = map[string]bool{
+		NE: true,
+		NC: true,
+		NW: true,
+		CE: true,
+		CC: true,
+		CW: true,
+		SE: true,
+		SC: true,
+		SW: true,
+	}
+	verticalGravity = map[string]bimg.Gravity{
+		NE: bimg.GravityNorth,
+		NC: bimg.GravityNorth,
+		NW: bimg.GravityNorth,
+		CE: bimg.GravityCentre,
+		CC: bimg.GravityCentre,
+		CW: bimg.GravityCentre,
+		SE: bimg.GravitySouth,
+		SC: bimg.GravitySouth,
+		SW: bimg.GravitySouth,
+	}
+	horizontalGravity = map[string]bimg.Gravity{
+		NE: bimg.GravityEast,
+		NC: bimg.GravityCentre,
+		NW: bimg.GravityWest,
+		CE: bimg.GravityEast,
+		CC: bimg.GravityCentre,
+		CW: bimg.GravityWest,
+		SE: bimg.GravityEast,
+		SC: bimg.GravityCentre,
+		SW: bimg.GravityWest,
+	}
+)
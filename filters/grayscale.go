@@ -0,0 +1,62 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// GrayscaleName is the name of the filter
+const GrayscaleName = "grayscale"
+
+type grayscale struct{}
+
+// NewGrayscale creates a new filter of this type
+func NewGrayscale() filters.Spec {
+	return &grayscale{}
+}
+
+func (f *grayscale) Name() string {
+	return GrayscaleName
+}
+
+func (f *grayscale) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for grayscale ", f)
+
+	interpretation, err := imageContext.Image.Interpretation()
+	if err != nil {
+		return nil, err
+	}
+
+	//the image is already single channel, so it keeps its interpretation
+	if interpretation == bimg.InterpretationBW || interpretation == bimg.InterpretationGREY16 {
+		return &bimg.Options{
+			Interpretation: interpretation}, nil
+	}
+
+	return &bimg.Options{
+		Interpretation: bimg.InterpretationBW}, nil
+}
+
+func (f *grayscale) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return other.Interpretation == 0 || other.Interpretation == self.Interpretation
+}
+
+func (f *grayscale) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Interpretation = self.Interpretation
+	return other
+}
+
+func (f *grayscale) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &grayscale{}, nil
+}
+
+func (f *grayscale) Request(ctx filters.FilterContext) {}
+
+func (f *grayscale) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewGrayscale(t *testing.T) {
+	name := NewGrayscale().Name()
+	assert.Equal(t, "grayscale", name)
+}
+
+func TestGrayscale_Name(t *testing.T) {
+	c := grayscale{}
+	assert.Equal(t, "grayscale", c.Name())
+}
+
+func TestGrayscale_CreateOptions(t *testing.T) {
+	f := grayscale{}
+	image := imagefiltertest.LandscapeImage()
+	options, err := f.CreateOptions(buildParameters(nil, image))
+
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.InterpretationBW, options.Interpretation)
+}
+
+func TestGrayscale_CreateOptions_SingleChannel(t *testing.T) {
+	f := grayscale{}
+	buf, err := imagefiltertest.LandscapeImage().Colourspace(bimg.InterpretationBW)
+	assert.Nil(t, err)
+
+	options, err := f.CreateOptions(buildParameters(nil, bimg.NewImage(buf)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.InterpretationBW, options.Interpretation)
+}
+
+func TestGrayscale_CanBeMerged_True(t *testing.T) {
+	s := grayscale{}
+	opt := &bimg.Options{Width: 100, Height: 200, Crop: true}
+	self := &bimg.Options{Interpretation: bimg.InterpretationBW}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestGrayscale_CanBeMerged_Repeated(t *testing.T) {
+	s := grayscale{}
+	opt := &bimg.Options{Interpretation: bimg.InterpretationBW}
+	self := &bimg.Options{Interpretation: bimg.InterpretationBW}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestGrayscale_CanBeMerged_False(t *testing.T) {
+	s := grayscale{}
+	opt := &bimg.Options{Interpretation: bimg.InterpretationCMYK}
+	self := &bimg.Options{Interpretation: bimg.InterpretationBW}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestGrayscale_Merge(t *testing.T) {
+	s := grayscale{}
+	self := &bimg.Options{Interpretation: bimg.InterpretationBW}
+
+	opt := s.Merge(&bimg.Options{StripMetadata: true}, self)
+
+	assert.Equal(t, bimg.InterpretationBW, opt.Interpretation)
+	assert.True(t, opt.StripMetadata)
+}
+
+func TestGrayscale_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewGrayscale, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{"bw"},
+		Err:  true,
+	}})
+}
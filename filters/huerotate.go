@@ -0,0 +1,140 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// HueRotateName is the name of the filter
+	HueRotateName = "hueRotate"
+)
+
+type hueRotate struct {
+	degrees int
+}
+
+// NewHueRotate creates a new filter of this type
+func NewHueRotate() filters.Spec {
+	return &hueRotate{}
+}
+
+func (f *hueRotate) Name() string {
+	return HueRotateName
+}
+
+func (f *hueRotate) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for hue rotate ", f)
+
+	//a full rotation leaves the image unchanged
+	if f.degrees%360 == 0 {
+		return img, nil
+	}
+
+	//the hue of the colors is rotated, keeping their saturation and value
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			h, s, v := rgbToHSV(row[i], row[i+1], row[i+2])
+			row[i], row[i+1], row[i+2] = hsvToRGB(math.Mod(h+float64(f.degrees), 360), s, v)
+		}
+	}
+
+	return img, nil
+}
+
+func (f *hueRotate) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*hueRotate)
+	return ok
+}
+
+func (f *hueRotate) Merge(other PixelFilter) PixelFilter {
+	return &hueRotate{degrees: (other.(*hueRotate).degrees + f.degrees) % 360}
+}
+
+func (f *hueRotate) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	h := &hueRotate{}
+
+	h.degrees, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if h.degrees < 0 {
+		log.Errorf("Failed to create the hue rotate filter, the rotation of %d degrees is negative", h.degrees)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+	//the rotations of more than a full turn are wrapped
+	h.degrees %= 360
+
+	return h, nil
+}
+
+func (f *hueRotate) Request(ctx filters.FilterContext) {}
+
+func (f *hueRotate) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
+
+// rgbToHSV returns the hue in degrees, and the saturation and the value between 0 and 1
+func rgbToHSV(r, g, b uint8) (float64, float64, float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	max := math.Max(rf, math.Max(gf, bf))
+	min := math.Min(rf, math.Min(gf, bf))
+	delta := max - min
+
+	var h float64
+	switch {
+	case delta == 0:
+		h = 0
+	case max == rf:
+		h = 60 * math.Mod((gf-bf)/delta, 6)
+	case max == gf:
+		h = 60 * ((bf-rf)/delta + 2)
+	default:
+		h = 60 * ((rf-gf)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+
+	s := 0.0
+	if max > 0 {
+		s = delta / max
+	}
+
+	return h, s, max
+}
+
+func hsvToRGB(h, s, v float64) (uint8, uint8, uint8) {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return clampUint8((r + m) * 255), clampUint8((g + m) * 255), clampUint8((b + m) * 255)
+}
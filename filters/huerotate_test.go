@@ -0,0 +1,112 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewHueRotate(t *testing.T) {
+	name := NewHueRotate().Name()
+	assert.Equal(t, "hueRotate", name)
+}
+
+func TestHueRotate_TransformPixels(t *testing.T) {
+	red := color.NRGBA{R: 255, G: 0, B: 0, A: 128}
+
+	result, err := (&hueRotate{degrees: 120}).TransformPixels(uniformImage(red))
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 0, G: 255, B: 0, A: 128}, result.NRGBAAt(1, 1))
+}
+
+func TestHueRotate_TransformPixels_FullRotation(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	result, err := (&hueRotate{degrees: 360}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result)
+}
+
+func TestHueRotate_TransformPixels_RoundTrip(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	result, _ := (&hueRotate{degrees: 90}).TransformPixels(uniformImage(c))
+	result, _ = (&hueRotate{degrees: 270}).TransformPixels(result)
+
+	assert.Equal(t, uniformImage(c), result)
+}
+
+func TestHueRotate_TransformPixels_Gray(t *testing.T) {
+	gray := color.NRGBA{R: 90, G: 90, B: 90, A: 255}
+
+	result, err := (&hueRotate{degrees: 45}).TransformPixels(uniformImage(gray))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(gray), result)
+}
+
+func TestHueRotate_CanBeMerged(t *testing.T) {
+	f := hueRotate{degrees: 90}
+	assert.True(t, f.CanBeMerged(&hueRotate{degrees: 45}))
+	assert.False(t, f.CanBeMerged(&saturation{factor: 0.5}))
+}
+
+func TestHueRotate_Merge(t *testing.T) {
+	f := hueRotate{degrees: 300}
+
+	merged := f.Merge(&hueRotate{degrees: 90})
+
+	assert.Equal(t, &hueRotate{degrees: 30}, merged)
+	assert.Equal(t, 300, f.degrees)
+}
+
+func TestHueRotate_SingleDecodeWithOtherColorFilters(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	HandlePixelResponse(fc, &brightness{factor: 1.2})
+	HandlePixelResponse(fc, &hueRotate{degrees: 200})
+	HandlePixelResponse(fc, &hueRotate{degrees: 200})
+	HandlePixelResponse(fc, &saturation{factor: 1.5})
+
+	assert.Equal(t, []PixelFilter{&brightness{factor: 1.2}, &hueRotate{degrees: 40}, &saturation{factor: 1.5}},
+		fc.FStateBag[skropPixelFilters])
+}
+
+func TestHueRotate_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewHueRotate, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{90.0},
+		Err:  false,
+	}, {
+		Msg:  "more than a full rotation",
+		Args: []interface{}{450.0},
+		Err:  false,
+	}, {
+		Msg:  "negative rotation",
+		Args: []interface{}{-90.0},
+		Err:  true,
+	}, {
+		Msg:  "not a number",
+		Args: []interface{}{"90"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{90.0, 1.0},
+		Err:  true,
+	}})
+}
+
+func TestHueRotate_CreateFilter_Wraps(t *testing.T) {
+	f, err := NewHueRotate().CreateFilter([]interface{}{450.0})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &hueRotate{degrees: 90}, f)
+}
@@ -2,16 +2,21 @@ package filters
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/h2non/bimg"
 	log "github.com/sirupsen/logrus"
 	"github.com/zalando-stups/skrop/messages"
+	"github.com/zalando-stups/skrop/parse"
 	"github.com/zalando/skipper/filters"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -25,6 +30,10 @@ const (
 	West = "west"
 	// Center Gravity
 	Center = "center"
+	// Smart Gravity, cropping the most interesting part of the image
+	Smart = "smart"
+	// Focus Gravity, cropping around the focal point given with the crop
+	Focus = "focus"
 	// Quality used by default if not specified
 	Quality          = 100
 	doNotEnlarge     = "DO_NOT_ENLARGE"
@@ -32,12 +41,40 @@ const (
 	hasMergedFilters = "hasMergedFilters"
 	skropOptions     = "skOptions"
 	skropInit        = "skInit"
+	skropAlpha       = "skAlpha"
+	//the metadata is stripped from the encoded image, to keep its ICC profile
+	skropStripKeepingProfile = "skStripKeepingProfile"
+	skropPreserveProfile     = "skPreserveProfile"
+	//bimg cannot encode the PNG images without compression
+	skropNoCompression     = "skNoCompression"
+	skropAnimated          = "skAnimated"
+	skropNotImage          = "skNotImage"
+	skropChromaSubsampling = "skChromaSubsampling"
+	//the image is smaller than the minimum dimensions, so the following filters are skipped
+	skropSkipped = "skSkipped"
+	//the last filter merged in the options, and the decode/encode passes of the image
+	skropLastMerged = "skLastMerged"
+	skropPasses     = "skPasses"
+	skropImageSize  = "skImageSize"
+	skropDeadline   = "skDeadline"
+	//the fallback image is served instead of the error, so the following filters are skipped
+	skropFallback = "skFallback"
+	//the filters applied and the sizes of the image, logged when the response is finalized
+	skropRequestLog = "skRequestLog"
+	//the bigger bodies are read without trusting their length
+	maxPreallocatedBody = 256 << 20
 )
 
 var (
+	// ErrSmartCropNotSupported is returned when creating a smart crop filter with a libvips older than 8.5
+	ErrSmartCropNotSupported = errors.New("smart crop is not supported, it needs libvips 8.5 or newer")
+
 	cropTypeToGravity map[string]bimg.Gravity
 	cropTypes         map[string]bool
 	stripMetadata     bool
+	strictCropBounds  bool
+	//the pixels are rotated by the EXIF orientation by default
+	preserveOrientation bool
 )
 
 func init() {
@@ -46,18 +83,73 @@ func init() {
 		South:  true,
 		East:   true,
 		West:   true,
-		Center: true}
+		Center: true,
+		Smart:  true,
+		Focus:  true}
 	cropTypeToGravity = map[string]bimg.Gravity{
 		North:  bimg.GravityNorth,
 		South:  bimg.GravitySouth,
 		East:   bimg.GravityEast,
 		West:   bimg.GravityWest,
-		Center: bimg.GravityCentre}
+		Center: bimg.GravityCentre,
+		Smart:  bimg.GravitySmart}
 
 	val, exists := os.LookupEnv("STRIP_METADATA")
 	if exists && strings.ToUpper(val) == "TRUE" {
 		stripMetadata = true
 	}
+
+	val, exists = os.LookupEnv("STRICT_CROP_BOUNDS")
+	if exists && strings.ToUpper(val) == "TRUE" {
+		strictCropBounds = true
+	}
+
+	val, exists = os.LookupEnv("EXIF_ORIENTATION")
+	if exists && strings.ToUpper(val) == OrientationPreserve {
+		preserveOrientation = true
+	}
+
+	val, exists = os.LookupEnv("MAX_CONCURRENCY")
+	if n, err := strconv.Atoi(val); exists && err == nil {
+		SetMaxConcurrency(n)
+	}
+
+	val, exists = os.LookupEnv("CONCURRENCY_TIMEOUT")
+	if timeout, err := time.ParseDuration(val); exists && err == nil {
+		SetConcurrencyTimeout(timeout)
+	}
+
+	val, exists = os.LookupEnv("MAX_INPUT_PIXELS")
+	if n, err := strconv.ParseInt(val, 10, 64); exists && err == nil {
+		SetMaxInputPixels(n)
+	}
+
+	val, exists = os.LookupEnv("MAX_INPUT_BYTES")
+	if n, err := strconv.ParseInt(val, 10, 64); exists && err == nil {
+		SetMaxInputBytes(n)
+	}
+
+	val, exists = os.LookupEnv("PROCESSING_TIMEOUT")
+	if timeout, err := time.ParseDuration(val); exists && err == nil {
+		SetProcessingTimeout(timeout)
+	}
+
+	val, exists = os.LookupEnv("MAX_OUTPUT_PIXELS")
+	if n, err := strconv.ParseInt(val, 10, 64); exists && err == nil {
+		SetMaxOutputPixels(n)
+	}
+
+	val, exists = os.LookupEnv("FALLBACK_IMAGE")
+	if exists && val != "" {
+		if err := SetFallbackImageFile(val); err != nil {
+			log.Error("failed to read the fallback image ", err.Error())
+		}
+	}
+}
+
+// smartCropSupported tells if libvips is recent enough to crop the most interesting part of the image
+func smartCropSupported() bool {
+	return bimg.VipsMajorVersion > 8 || (bimg.VipsMajorVersion == 8 && bimg.VipsMinorVersion >= 5)
 }
 
 // ImageFilter defines what a filter should implement
@@ -75,10 +167,86 @@ type ImageFilterContext struct {
 
 func (c *ImageFilterContext) PathParam(key string) string { return (*c.filterContext).PathParam(key) }
 
+// cachedImageSize is the size of the image of the state bag
+type cachedImageSize struct {
+	image *bimg.Image
+	size  bimg.ImageSize
+}
+
+// ImageSize returns the size of the image. It is read once for every image of the response, as all the filters
+// merged together create their options on the same image. bimg rotates the image before resizing and cropping it,
+// so the size is the one of the image rotated by its EXIF orientation and by the options merged so far
+func (c *ImageFilterContext) ImageSize() (bimg.ImageSize, error) {
+	if c.filterContext == nil || *c.filterContext == nil || (*c.filterContext).StateBag() == nil {
+		size, err := readImageSize(c.Image)
+		return orientedSize(c.Image.Image(), size), err
+	}
+
+	bag := (*c.filterContext).StateBag()
+	size, err := c.cachedSize(bag)
+	if err != nil {
+		return size, err
+	}
+
+	if options, ok := bag[skropOptions].(*bimg.Options); ok && (options.Rotate == bimg.D90 || options.Rotate == bimg.D270) {
+		size.Width, size.Height = size.Height, size.Width
+	}
+	return size, nil
+}
+
+// cachedSize returns the size of the image, as displayed with its EXIF orientation
+func (c *ImageFilterContext) cachedSize(bag map[string]interface{}) (bimg.ImageSize, error) {
+	if cached, ok := bag[skropImageSize].(*cachedImageSize); ok && cached.image == c.Image {
+		return cached.size, nil
+	}
+
+	size, err := readImageSize(c.Image)
+	if err != nil {
+		return size, err
+	}
+
+	size = orientedSize(c.Image.Image(), size)
+	bag[skropImageSize] = &cachedImageSize{image: c.Image, size: size}
+	return size, nil
+}
+
+// readImageSize reads the size of the image with libvips
+var readImageSize = func(image *bimg.Image) (size bimg.ImageSize, err error) {
+	defer recoverCorrupt(&err)
+	return image.Size()
+}
+
 func errorResponse() *http.Response {
+	return statusResponse(http.StatusInternalServerError, messages.Error500)
+}
+
+// errorResponseFor returns the response for the error which occurred while processing the image. The body is a
+// short message, the details of the error are only logged
+func errorResponseFor(err error) *http.Response {
+	switch {
+	case errors.Is(err, filters.ErrInvalidFilterParameters):
+		return statusResponse(http.StatusBadRequest, messages.Error400)
+	case err == ErrTooBusy:
+		return statusResponse(http.StatusServiceUnavailable, messages.Error503)
+	case err == ErrImageTooLarge:
+		return statusResponse(http.StatusRequestEntityTooLarge, messages.Error413)
+	case errors.Is(err, ErrCorruptImage):
+		return statusResponse(http.StatusUnsupportedMediaType, messages.ErrorCorruptImage)
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return statusResponse(http.StatusGatewayTimeout, messages.Error504)
+	default:
+		return errorResponse()
+	}
+}
+
+func statusResponse(status int, message string) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+
 	return &http.Response{
-		StatusCode: http.StatusInternalServerError,
-		Body:       ioutil.NopCloser(bytes.NewBufferString(messages.Error500)),
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(message)),
 	}
 }
 
@@ -97,76 +265,171 @@ func buildParameters(ctx filters.FilterContext, image *bimg.Image) *ImageFilterC
 
 // HandleImageResponse should be called by the Response of every filter. It transforms the image
 func HandleImageResponse(ctx filters.FilterContext, f ImageFilter) error {
+	start := time.Now()
+	statusSkipped := processingStopped(ctx)
+
+	err := handleImageResponse(ctx, f)
+
+	name := filterName(f)
+	duration := time.Since(start)
+	metrics.ObserveDuration(name, duration)
+	if err != nil {
+		metrics.IncErrors(name, errorKind(err, statusSkipped))
+	}
+	logFilter(ctx, name, duration, err)
+	return err
+}
+
+func handleImageResponse(ctx filters.FilterContext, f ImageFilter) error {
 
 	log.Debug("Handle Image Response")
 
 	//in case the response had an error from the backend or from a previous filter
-	if ctx.Response().StatusCode > 300 {
+	if processingStopped(ctx) {
 		return fmt.Errorf("processing skipped, as the backend/filter reported %d status code", ctx.Response().StatusCode)
 	}
 
-	//executed while processing the first filter
-	if _, ok := ctx.StateBag()[skropInit]; !ok {
-		initResponse(ctx)
-		ctx.StateBag()[skropInit] = true
-		ctx.StateBag()[hasMergedFilters] = false
+	initOnce(ctx)
+
+	//the error response is already served when the image cannot be read
+	if processingStopped(ctx) {
+		return fmt.Errorf("processing failed, the image could not be read: %d status code", ctx.Response().StatusCode)
+	}
+
+	if ctx.StateBag()[skropAnimated] == true {
+		return ErrAnimatedImage
+	}
+
+	if ctx.StateBag()[skropNotImage] == true {
+		return ErrNotAnImage
+	}
+
+	if ctx.StateBag()[skropSkipped] == true {
+		log.Debug("Filter ", f, " skipped, the image is smaller than the minimum dimensions")
+		return nil
+	}
+
+	if err := checkCanceled(ctx); err != nil {
+		log.Error("Failed to process image ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return err
 	}
 
 	image, ok := ctx.StateBag()[skropImage].(*bimg.Image)
 	if !ok {
 		//call the init func
 		log.Error("context state bag does not contains the key ", skropImage)
-		ctx.Serve(errorResponse())
+		serveError(ctx, errorResponse())
 		return errors.New("processing failed, image not exists in the state bag")
 	}
 
-	optionsFromRequest, err := f.CreateOptions(buildParameters(ctx, image))
+	//the pixel filters queued so far need to be applied before the image can be transformed again
+	if err := applyPixelFilters(ctx); err != nil {
+		log.Error("Failed to process image ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return err
+	}
+	image = ctx.StateBag()[skropImage].(*bimg.Image)
+
+	optionsFromRequest, err := createOptions(ctx, f, image)
 	if err != nil {
-		log.Error("Failed to create options ", err.Error())
-		ctx.Serve(errorResponse())
 		return err
 	}
 
 	optionsFromStateBag, ok := ctx.StateBag()[skropOptions].(*bimg.Options)
 	if !ok {
 		log.Error("context state bag does not contains the key ", skropImage)
-		ctx.Serve(errorResponse())
+		serveError(ctx, errorResponse())
 		return errors.New("processing failed, initialization of options not successful")
 	}
 
-	if f.CanBeMerged(optionsFromStateBag, optionsFromRequest) {
-		ctx.StateBag()[skropOptions] = f.Merge(optionsFromStateBag, optionsFromRequest)
+	if merged, ok := mergeFilter(f, optionsFromStateBag, optionsFromRequest); ok {
+		ctx.StateBag()[skropOptions] = merged
 		ctx.StateBag()[hasMergedFilters] = true
+		ctx.StateBag()[skropLastMerged] = f
 		log.Debug("Filter ", f, " merged in ", ctx.StateBag()[skropOptions])
 		return nil
 	}
 
+	//the options merged so far need to be applied first, so the filter is applied on their result
+	if ctx.StateBag()[hasMergedFilters] == true {
+		log.Debugf("Filter %s cannot be merged with %s, the image is transformed in a separate pass",
+			filterName(f), filterName(ctx.StateBag()[skropLastMerged]))
+		log.Debugf("Transform the image based on the options merged so far: %+v", optionsFromStateBag)
+		countPass(ctx)
+		keepProfile(ctx, optionsFromStateBag)
+		buf, err := transformImage(image, optionsFromStateBag, hasAlpha(ctx))
+		if err != nil {
+			log.Error("Failed to process image ", err.Error())
+			serveError(ctx, errorResponseFor(err))
+			return err
+		}
+		metrics.AddBytes(filterName(f), len(image.Image()), len(buf))
+		image = bimg.NewImage(buf)
+		//the merged options are applied, so the size of the new image is not changed by their rotation anymore
+		ctx.StateBag()[skropOptions] = &bimg.Options{}
+
+		if err := checkCanceled(ctx); err != nil {
+			log.Error("Failed to process image ", err.Error())
+			serveError(ctx, errorResponseFor(err))
+			return err
+		}
+
+		optionsFromRequest, err = createOptions(ctx, f, image)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Debugf("Transform the image based on the options from request: %+v", optionsFromRequest)
-	buf, err := transformImage(image, optionsFromRequest)
+	countPass(ctx)
+	keepProfile(ctx, optionsFromRequest)
+	buf, err := transformImage(image, optionsFromRequest, hasAlpha(ctx))
 	if err != nil {
 		log.Error("Failed to process image ", err.Error())
-		ctx.Serve(errorResponse())
+		serveError(ctx, errorResponseFor(err))
 		return err
 	}
+	metrics.AddBytes(filterName(f), len(image.Image()), len(buf))
 
 	newImage := bimg.NewImage(buf)
 	if err != nil {
 		log.Error("Failed to create new options ", err.Error())
-		ctx.Serve(errorResponse())
+		serveError(ctx, errorResponse())
 		return err
 	}
 
 	ctx.StateBag()[skropImage] = newImage
 	ctx.StateBag()[skropOptions] = &bimg.Options{}
+	ctx.StateBag()[hasMergedFilters] = false
+	delete(ctx.StateBag(), skropLastMerged)
 	return nil
 }
 
+// createOptions creates the options of the filter for the image, serving an error response if it fails
+func createOptions(ctx filters.FilterContext, f ImageFilter, image *bimg.Image) (*bimg.Options, error) {
+	options, err := f.CreateOptions(buildParameters(ctx, image))
+	if err != nil {
+		log.Error("Failed to create options ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return nil, err
+	}
+
+	if hasAlpha(ctx) && options.Type == bimg.JPEG {
+		log.Error("Failed to create options, the transparency of the image cannot be kept as JPEG")
+		serveError(ctx, errorResponse())
+		return nil, errors.New("processing failed, the image has transparency and cannot be converted to JPEG")
+	}
+
+	return options, nil
+}
+
 // FinalizeResponse is called at the end of the transformations on an image to empty the queue of
 // operations to perform
 func FinalizeResponse(ctx filters.FilterContext) {
 	log.Debug("Finalize Response")
 	//in case the response had an error from he backend or from a previous filter
-	if ctx.Response().StatusCode > 300 {
+	if processingStopped(ctx) {
 		return
 	}
 
@@ -174,6 +437,18 @@ func FinalizeResponse(ctx filters.FilterContext) {
 		return
 	}
 
+	if err := checkCanceled(ctx); err != nil {
+		log.Error("failed to process image ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return
+	}
+
+	if err := applyPixelFilters(ctx); err != nil {
+		log.Error("failed to process image ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return
+	}
+
 	image := ctx.StateBag()[skropImage].(*bimg.Image)
 	opts := ctx.StateBag()[skropOptions].(*bimg.Options)
 
@@ -182,53 +457,182 @@ func FinalizeResponse(ctx filters.FilterContext) {
 	var err error
 
 	if ctx.StateBag()[hasMergedFilters] == true {
-		buf, err = transformImage(image, opts)
+		countPass(ctx)
+		keepProfile(ctx, opts)
+		checkLossless(image, opts)
+		checkCompression(ctx, image, opts)
+		checkChromaSubsampling(ctx, image, opts)
+		buf, err = transformImage(image, opts, hasAlpha(ctx))
 		ctx.StateBag()[hasMergedFilters] = false
+	} else {
+		//no bimg pass rotates the image by its EXIF orientation, so it is baked on its own
+		var rotated bool
+		if buf, rotated, err = bakeOrientation(buf); rotated {
+			countPass(ctx)
+		}
+	}
+
+	if err == nil && ctx.StateBag()[skropStripKeepingProfile] == true && !opts.StripMetadata {
+		buf, err = stripMetadataKeepingProfile(buf)
+	}
+
+	if err == nil && ctx.StateBag()[skropNoCompression] == true && bimg.DetermineImageType(buf) == bimg.PNG {
+		buf, err = encodeWithoutCompression(buf)
+	}
+
+	if err == nil {
+		buf, err = applyDominantColor(ctx, buf)
 	}
 
 	if err != nil {
 		log.Error("failed to process image ", err.Error())
-		ctx.Serve(&http.Response{
-			StatusCode: http.StatusInternalServerError,
-			Body:       ioutil.NopCloser(bytes.NewBufferString(messages.Error500)),
-		})
+		serveError(ctx, errorResponseFor(err))
 		return
 	}
 
+	//the input image is not needed anymore, so only the output is kept in memory
+	ctx.StateBag()[skropImage] = bimg.NewImage(buf)
+
 	rsp := ctx.Response()
 
 	defer rsp.Body.Close()
 
 	rsp.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	rsp.ContentLength = int64(len(buf))
+	rsp.Header.Set("Content-Length", strconv.Itoa(len(buf)))
+
+	logProcessing(ctx, buf)
 }
 
-func transformImage(image *bimg.Image, opts *bimg.Options) ([]byte, error) {
-	defOpt := applyDefaults(opts)
+func transformImage(image *bimg.Image, opts *bimg.Options, keepAlpha bool) ([]byte, error) {
+	defOpt := applyDefaults(opts, keepAlpha)
+
+	//bimg ignores the EXIF orientation of the image when the options rotate or flip it
+	pending := orientationPending(image.Image())
+	if pending && (defOpt.Rotate != 0 || defOpt.Flip || defOpt.Flop) {
+		buf, _, err := bakeOrientation(image.Image())
+		if err != nil {
+			return nil, err
+		}
+		image, pending = bimg.NewImage(buf), false
+	}
+
+	if err := checkOutputPixels(image, defOpt); err != nil {
+		return nil, err
+	}
+
+	if !pending && isIdentity(image, defOpt, keepAlpha) {
+		log.Debugf("The options do not change the image, it is kept as it is: %+v", opts)
+		return image.Image(), nil
+	}
 
 	log.Debugf("successfully applied the following options on the image: %+v\n", opts)
 
-	transformedImageBytes, err := image.Process(*defOpt)
+	transformedImageBytes, err := processImage(image, *defOpt)
 
 	if err != nil {
 		return nil, err
 	}
 
+	//bimg rotates the image by its orientation, but it keeps the orientation tag as it is
+	if pending {
+		setJPEGOrientation(transformedImageBytes, orientationNormal)
+	}
+
 	return transformedImageBytes, nil
 }
 
-func applyDefaults(o *bimg.Options) *bimg.Options {
+func applyDefaults(o *bimg.Options, keepAlpha bool) *bimg.Options {
 	if (stripMetadata) {
 		o.StripMetadata = true
 	}
+	if preserveOrientation {
+		o.NoAutoRotate = true
+	}
 	if o.Quality == 0 {
 		o.Quality = Quality
 	}
-	if o.Background == bimg.ColorBlack {
+	//bimg does not flatten the image on a black background, so the transparency is kept
+	if o.Background == bimg.ColorBlack && !keepAlpha {
 		o.Background = bimg.Color{R: 255, G: 255, B: 255}
 	}
 	return o
 }
 
+// bimgColor converts the parsed color to the color of bimg, ignoring its opacity
+func bimgColor(c parse.Color) bimg.Color {
+	return bimg.Color{R: c.R, G: c.G, B: c.B}
+}
+
+// Passes returns the number of decode/encode passes of the image so far, every filter which cannot be merged
+// with the previous ones adding a pass
+func Passes(ctx filters.FilterContext) int {
+	passes, _ := ctx.StateBag()[skropPasses].(int)
+	return passes
+}
+
+func countPass(ctx filters.FilterContext) {
+	ctx.StateBag()[skropPasses] = Passes(ctx) + 1
+}
+
+// filterName returns the name of the filter, for the logs
+func filterName(f interface{}) string {
+	if f == nil {
+		return "the options merged so far"
+	}
+	if spec, ok := f.(filters.Spec); ok {
+		return spec.Name()
+	}
+	return fmt.Sprintf("%T", f)
+}
+
+// hasAlpha tells if a filter made the image transparent, so the transparency needs to be kept
+func hasAlpha(ctx filters.FilterContext) bool {
+	return ctx.StateBag()[skropAlpha] == true
+}
+
+// initOnce reads the image from the response, while processing the first filter
+func initOnce(ctx filters.FilterContext) {
+	if _, ok := ctx.StateBag()[skropInit]; !ok {
+		setDeadline(ctx)
+		startRequestLog(ctx)
+		initResponse(ctx)
+		ctx.StateBag()[skropInit] = true
+		ctx.StateBag()[hasMergedFilters] = false
+	}
+}
+
+// readBody reads the whole body of the response. bimg needs the image in a single buffer, so when the length is
+// known the buffer is allocated once, instead of growing it while reading the big images. The bodies bigger than
+// the limit of the input images are not read entirely
+func readBody(rsp *http.Response) ([]byte, error) {
+	_, maxBytes := inputLimits()
+	if maxBytes > 0 && rsp.ContentLength > maxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	body := io.Reader(rsp.Body)
+	if maxBytes > 0 {
+		body = io.LimitReader(rsp.Body, maxBytes+1)
+	}
+
+	var buf []byte
+	var err error
+	if rsp.ContentLength <= 0 || rsp.ContentLength > maxPreallocatedBody {
+		buf, err = ioutil.ReadAll(body)
+	} else {
+		//the buffer grows if less than bytes.MinRead bytes are left when reading the end of the body
+		b := bytes.NewBuffer(make([]byte, 0, rsp.ContentLength+bytes.MinRead))
+		_, err = b.ReadFrom(body)
+		buf = b.Bytes()
+	}
+
+	if err == nil && maxBytes > 0 && int64(len(buf)) > maxBytes {
+		return nil, ErrImageTooLarge
+	}
+	return buf, err
+}
+
 func initResponse(ctx filters.FilterContext) {
 	rsp := ctx.Response()
 
@@ -236,29 +640,61 @@ func initResponse(ctx filters.FilterContext) {
 
 	rsp.Header.Del("Content-Length")
 
-	buf, err := ioutil.ReadAll(rsp.Body)
+	buf, err := readBody(rsp)
 	imageBytesLength := len(buf)
 
 	log.Debug("Image bytes length: ", imageBytesLength)
+	getRequestLog(ctx).inputBytes = imageBytesLength
 
 	if err != nil {
 		log.Error("failed to process image ", err.Error())
-		ctx.Serve(&http.Response{
-			StatusCode: http.StatusInternalServerError,
-			Body:       ioutil.NopCloser(bytes.NewBufferString(messages.Error500)),
-		})
+		serveError(ctx, errorResponseFor(err))
 		return
 	}
 
 	if imageBytesLength == 0 {
 		log.Error("original image is empty")
-		ctx.Serve(&http.Response{
-			StatusCode: http.StatusInternalServerError,
-			Body:       ioutil.NopCloser(bytes.NewBufferString(messages.Error404)),
-		})
+		serveError(ctx, statusResponse(http.StatusInternalServerError, messages.Error404))
 		return
 	}
 
-	ctx.StateBag()[skropImage] = bimg.NewImage(buf)
+	if !isImageResponse(rsp, buf) {
+		log.Warn("The response is not an image, it is passed through without being transformed: ",
+			rsp.Header.Get("Content-Type"))
+		ctx.StateBag()[skropNotImage] = true
+		ctx.StateBag()[skropImage] = bimg.NewImage(buf)
+		ctx.StateBag()[skropOptions] = &bimg.Options{}
+		return
+	}
+
+	if imageType := bimg.DetermineImageType(buf); !bimg.IsTypeSupported(imageType) {
+		log.Error("the type of the original image is not supported")
+		serveError(ctx, statusResponse(http.StatusUnsupportedMediaType, messages.Error415))
+		return
+	}
+
+	size, err := checkDecodable(buf)
+	if err != nil {
+		log.Error("the original image cannot be decoded ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return
+	}
+
+	if err := checkInputPixels(size); err != nil {
+		log.Error("the original image has too many pixels")
+		serveError(ctx, errorResponseFor(err))
+		return
+	}
+
+	if isAnimated(buf) {
+		log.Warn("The image is animated, it is passed through without being transformed")
+		ctx.StateBag()[skropAnimated] = true
+	}
+
+	image := bimg.NewImage(buf)
+	ctx.StateBag()[skropImage] = image
 	ctx.StateBag()[skropOptions] = &bimg.Options{}
+
+	//the EXIF orientation is applied by the first bimg pass, or baked when the response is finalized
+	ctx.StateBag()[skropImageSize] = &cachedImageSize{image: image, size: orientedSize(buf, size)}
 }
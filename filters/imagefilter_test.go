@@ -2,14 +2,23 @@ package filters
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"runtime"
+	"strconv"
 	"testing"
 
 	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+	"github.com/zalando-stups/skrop/messages"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
 	"github.com/zalando/skipper/filters/filtertest"
 )
 
@@ -129,7 +138,7 @@ func createDefaultContext(t *testing.T, url string) *filtertest.Context {
 	return createContext(t, "GET", url, imagefiltertest.PNGImageFile, bag)
 }
 
-func createContext(t *testing.T, method string, url string, image string, stateBag map[string]interface{}) *filtertest.Context {
+func createContext(t testing.TB, method string, url string, image string, stateBag map[string]interface{}) *filtertest.Context {
 	buffer, _ := bimg.Read(image)
 
 	imageReader := ioutil.NopCloser(bytes.NewReader(buffer))
@@ -163,3 +172,241 @@ func (f *FakeImageFilter) Merge(other *bimg.Options, self *bimg.Options) *bimg.O
 	other.Background = self.Background
 	return other
 }
+
+func TestHandleImageResponse_AlphaToJPEG(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropAlpha] = true
+	imageFilter := FakeImageFilter(bimg.Options{Type: bimg.JPEG})
+
+	err := HandleImageResponse(fc, &imageFilter)
+
+	assert.NotNil(t, err, "the transparency cannot be kept as JPEG")
+	assert.Equal(t, http.StatusInternalServerError, fc.FResponse.StatusCode)
+}
+
+func TestApplyDefaults_KeepAlpha(t *testing.T) {
+	assert.Equal(t, bimg.Color{R: 255, G: 255, B: 255}, applyDefaults(&bimg.Options{}, false).Background)
+	assert.Equal(t, bimg.ColorBlack, applyDefaults(&bimg.Options{}, true).Background)
+}
+
+func TestHandleImageResponse_AppliesMergedOptionsFirst(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropOptions] = &bimg.Options{Width: 200, Height: 200, Crop: true}
+	imageFilter := FakeImageFilter(bimg.Options{Width: 100})
+
+	err := HandleImageResponse(fc, &imageFilter)
+
+	assert.Nil(t, err, "there should not be any error")
+	assert.Equal(t, false, fc.FStateBag[hasMergedFilters])
+	size, _ := fc.FStateBag[skropImage].(*bimg.Image).Size()
+	assert.Equal(t, bimg.ImageSize{Width: 100, Height: 100}, size, "the square crop should be applied before the resize")
+}
+
+func TestHandleImageResponse_LogsMergeConflict(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+	level := log.GetLevel()
+	log.SetLevel(log.DebugLevel)
+	defer log.SetLevel(level)
+
+	fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+
+	HandleImageResponse(fc, &crop{width: 100, height: 100, cropType: Center})
+	HandleImageResponse(fc, &extract{top: 10, left: 10, width: 50, height: 50})
+
+	var messages []string
+	for _, entry := range hook.AllEntries() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Contains(t, messages, "Filter extract cannot be merged with crop, the image is transformed in a separate pass")
+}
+
+func TestPasses(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+	assert.Equal(t, 0, Passes(fc))
+
+	countPass(fc)
+	countPass(fc)
+	assert.Equal(t, 2, Passes(fc))
+}
+
+// countSizeReads counts the sizes read with libvips until the returned func is called
+func countSizeReads() (*int, func()) {
+	reads := 0
+	read := readImageSize
+	readImageSize = func(image *bimg.Image) (bimg.ImageSize, error) {
+		reads++
+		return read(image)
+	}
+	return &reads, func() { readImageSize = read }
+}
+
+// handleMergedChain handles the responses of a quality, an overlay and a crop, all merged together
+func handleMergedChain(tb testing.TB) *filtertest.Context {
+	o, err := NewOverlayImage().CreateFilter([]interface{}{"../images/star.png", 1.0, "NW"})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	fc := createContext(tb, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+	for _, f := range []ImageFilter{&quality{percentage: 80}, o.(ImageFilter), &crop{width: 100, height: 100, cropType: Center}} {
+		if err := HandleImageResponse(fc, f); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return fc
+}
+
+func TestImageSize_ReadOnce(t *testing.T) {
+	reads, restore := countSizeReads()
+	defer restore()
+
+	fc := handleMergedChain(t)
+
+	assert.Equal(t, true, fc.FStateBag[hasMergedFilters])
+	assert.Equal(t, 1, *reads, "the size should be read once for the merged filters")
+}
+
+func TestImageSize_NewImage(t *testing.T) {
+	reads, restore := countSizeReads()
+	defer restore()
+
+	fc := createDefaultContext(t, "url")
+	image := fc.FStateBag[skropImage].(*bimg.Image)
+
+	size, err := buildParameters(fc, image).ImageSize()
+	assert.Nil(t, err)
+	assert.Equal(t, 762, size.Width)
+	buildParameters(fc, image).ImageSize()
+	assert.Equal(t, 1, *reads)
+
+	buildParameters(fc, bimg.NewImage(image.Image())).ImageSize()
+	assert.Equal(t, 2, *reads, "the size of another image should be read again")
+}
+
+func TestImageSize_PendingRotation(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+	image := detailedImage(400, 200, 400)
+
+	fc.FStateBag[skropOptions] = &bimg.Options{Rotate: bimg.D90}
+	size, err := buildParameters(fc, image).ImageSize()
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.ImageSize{Width: 200, Height: 400}, size)
+
+	fc.FStateBag[skropOptions] = &bimg.Options{Rotate: bimg.D180}
+	size, err = buildParameters(fc, image).ImageSize()
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.ImageSize{Width: 400, Height: 200}, size)
+}
+
+func BenchmarkHandleImageResponse_SizeReads(b *testing.B) {
+	reads, restore := countSizeReads()
+	defer restore()
+
+	for i := 0; i < b.N; i++ {
+		handleMergedChain(b)
+	}
+
+	b.ReportMetric(float64(*reads)/float64(b.N), "sizes/op")
+}
+
+// bigResponse returns a response with a body of the size of a 20MP image, with its length
+func bigResponse(body []byte) *http.Response {
+	return &http.Response{
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Header:        make(http.Header),
+	}
+}
+
+func TestReadBody_AllocatesOnce(t *testing.T) {
+	body := make([]byte, 20<<20)
+	rand.Read(body)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	buf, err := readBody(bigResponse(body))
+	runtime.ReadMemStats(&after)
+
+	assert.Nil(t, err)
+	assert.Equal(t, body, buf)
+	assert.True(t, after.TotalAlloc-before.TotalAlloc < uint64(len(body))*11/10,
+		"the body should be read in a single allocation")
+}
+
+func TestReadBody_UnknownLength(t *testing.T) {
+	rsp := bigResponse([]byte("image"))
+	rsp.ContentLength = -1
+
+	buf, err := readBody(rsp)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("image"), buf)
+}
+
+func TestFinalizeResponse_ContentLength(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+	fc.FStateBag[hasMergedFilters] = false
+
+	FinalizeResponse(fc)
+
+	result, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, int64(len(result)), fc.Response().ContentLength)
+	assert.Equal(t, strconv.Itoa(len(result)), fc.Response().Header.Get("Content-Length"))
+	assert.Equal(t, result, fc.FStateBag[skropImage].(*bimg.Image).Image())
+}
+
+func BenchmarkInitResponse_20MP(b *testing.B) {
+	body := make([]byte, 20<<20)
+	rand.Read(body)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		fc := createContext(b, "GET", "url", "", map[string]interface{}{})
+		fc.FResponse = bigResponse(body)
+		initResponse(fc)
+	}
+}
+
+func TestHandleImageResponse_UnsupportedType(t *testing.T) {
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewBufferString("this is not an image"))
+
+	err := HandleImageResponse(fc, &crop{width: 100, height: 100, cropType: Center})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, fc.Response().StatusCode)
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, messages.Error415, string(body))
+	assert.Equal(t, "text/plain; charset=utf-8", fc.Response().Header.Get("Content-Type"))
+}
+
+func TestHandleImageResponse_CorruptImage(t *testing.T) {
+	buf, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(buf[:20]))
+
+	err := HandleImageResponse(fc, &crop{width: 100, height: 100, cropType: Center})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, fc.Response().StatusCode)
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, messages.ErrorCorruptImage, string(body))
+}
+
+func TestHandleImageResponse_InvalidParameters(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+
+	err := HandleImageResponse(fc, &cropByFocalPoint{targetX: 0.5, targetY: 0.5, aspectRatio: 1.5})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, fc.Response().StatusCode)
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, messages.Error400, string(body), "the details of the error should not be in the body")
+}
+
+func TestErrorResponseFor(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, errorResponseFor(parse.AtArg(filters.ErrInvalidFilterParameters, "crop", 1, "width")).StatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, errorResponseFor(ErrTooBusy).StatusCode)
+	assert.Equal(t, http.StatusInternalServerError, errorResponseFor(errors.New("failed")).StatusCode)
+}
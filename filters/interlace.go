@@ -0,0 +1,52 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// InterlaceName is the name of the filter
+const InterlaceName = "interlace"
+
+// interlace encodes the image as a progressive JPEG or an interlaced PNG, the other types are not affected
+type interlace struct{}
+
+// NewInterlace creates a new filter of this type
+func NewInterlace() filters.Spec {
+	return &interlace{}
+}
+
+func (f *interlace) Name() string {
+	return InterlaceName
+}
+
+func (f *interlace) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for interlace ", f)
+
+	return &bimg.Options{Interlace: true}, nil
+}
+
+func (f *interlace) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//it only concerns the encoding of the image
+	return true
+}
+
+func (f *interlace) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Interlace = other.Interlace || self.Interlace
+	return other
+}
+
+func (f *interlace) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &interlace{}, nil
+}
+
+func (f *interlace) Request(ctx filters.FilterContext) {}
+
+func (f *interlace) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// isProgressiveJPEG tells if the start of frame segment of the JPEG is the progressive one
+func isProgressiveJPEG(buf []byte) bool {
+	for i := 2; i+4 <= len(buf) && buf[i] == 0xFF; i += 2 + int(binary.BigEndian.Uint16(buf[i+2:])) {
+		switch marker := buf[i+1]; {
+		case marker == 0xC2:
+			return true
+		case marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC, marker == 0xDA:
+			return false
+		}
+	}
+	return false
+}
+
+func TestNewInterlace(t *testing.T) {
+	name := NewInterlace().Name()
+	assert.Equal(t, "interlace", name)
+}
+
+func TestInterlace_CreateOptions(t *testing.T) {
+	options, _ := (&interlace{}).CreateOptions(nil)
+	assert.True(t, options.Interlace)
+}
+
+func TestInterlace_CanBeMerged(t *testing.T) {
+	s := interlace{}
+	self := &bimg.Options{Interlace: true}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 100, Crop: true, Quality: 80}, self))
+}
+
+func TestInterlace_Merge(t *testing.T) {
+	s := interlace{}
+
+	opt := s.Merge(&bimg.Options{Width: 100}, &bimg.Options{Interlace: true})
+
+	assert.True(t, opt.Interlace)
+	assert.Equal(t, 100, opt.Width)
+}
+
+func TestInterlace_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewInterlace, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{true},
+		Err:  true,
+	}})
+}
+
+func TestInterlace_Response(t *testing.T) {
+	original, _ := bimg.Read(imagefiltertest.PortraitImageFile)
+	assert.False(t, isProgressiveJPEG(original), "the original image should be baseline")
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(original)
+	fc.FStateBag[hasMergedFilters] = false
+
+	(&interlace{}).Response(fc)
+	FinalizeResponse(fc)
+
+	result := readResultImage(fc.Response().Body, t)
+
+	assert.Equal(t, "jpeg", result.Type())
+	assert.True(t, isProgressiveJPEG(result.Image()), "the JPEG should be progressive")
+}
@@ -0,0 +1,72 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// InterpolatorName is the name of the filter
+const InterpolatorName = "interpolator"
+
+// interpolators are the ones supported by bimg. Lanczos3 is only used by libvips to shrink the image
+var interpolators = map[string]bimg.Interpolator{
+	"bicubic":  bimg.Bicubic,
+	"bilinear": bimg.Bilinear,
+	"nohalo":   bimg.Nohalo,
+	"nearest":  bimg.Nearest,
+}
+
+type interpolator struct {
+	interpolator bimg.Interpolator
+}
+
+// NewInterpolator creates a new filter of this type
+func NewInterpolator() filters.Spec {
+	return &interpolator{}
+}
+
+func (f *interpolator) Name() string {
+	return InterpolatorName
+}
+
+func (f *interpolator) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for interpolator ", f)
+
+	return &bimg.Options{
+		Interpolator: f.interpolator}, nil
+}
+
+func (f *interpolator) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//bicubic is the default, so it can be overridden
+	return other.Interpolator == bimg.Bicubic || other.Interpolator == self.Interpolator
+}
+
+func (f *interpolator) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Interpolator = self.Interpolator
+	return other
+}
+
+func (f *interpolator) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	i, ok := interpolators[name]
+	if !ok {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &interpolator{interpolator: i}, nil
+}
+
+func (f *interpolator) Request(ctx filters.FilterContext) {}
+
+func (f *interpolator) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
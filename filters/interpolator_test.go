@@ -0,0 +1,113 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewInterpolator(t *testing.T) {
+	name := NewInterpolator().Name()
+	assert.Equal(t, "interpolator", name)
+}
+
+func TestInterpolator_Name(t *testing.T) {
+	i := interpolator{}
+	assert.Equal(t, "interpolator", i.Name())
+}
+
+func TestInterpolator_CreateOptions(t *testing.T) {
+	i := interpolator{interpolator: bimg.Nearest}
+	options, _ := i.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
+
+	assert.Equal(t, &bimg.Options{Interpolator: bimg.Nearest}, options)
+}
+
+func TestInterpolator_CanBeMerged(t *testing.T) {
+	i := interpolator{}
+	self := &bimg.Options{Interpolator: bimg.Nearest}
+
+	assert.True(t, i.CanBeMerged(&bimg.Options{Width: 100, Height: 100, Force: true}, self))
+	assert.True(t, i.CanBeMerged(&bimg.Options{Interpolator: bimg.Nearest}, self))
+	assert.False(t, i.CanBeMerged(&bimg.Options{Interpolator: bimg.Bilinear}, self))
+}
+
+func TestInterpolator_Merge(t *testing.T) {
+	i := interpolator{}
+	self := &bimg.Options{Interpolator: bimg.Nearest}
+
+	merged := i.Merge(&bimg.Options{Width: 100, Height: 100, Force: true}, self)
+
+	assert.Equal(t, &bimg.Options{Width: 100, Height: 100, Force: true, Interpolator: bimg.Nearest}, merged)
+}
+
+func TestInterpolator_NearestKeepsHardEdges(t *testing.T) {
+	pixels := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			v := uint8(0)
+			if x >= 2 {
+				v = 255
+			}
+			pixels.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	buf, _ := encodePixels(pixels)
+
+	r := resize{width: 32, height: 32}
+	i := interpolator{interpolator: bimg.Nearest}
+	img := bimg.NewImage(buf)
+
+	options, _ := r.CreateOptions(buildParameters(nil, img))
+	self, _ := i.CreateOptions(buildParameters(nil, img))
+	options = i.Merge(options, self)
+	options.Type = bimg.PNG
+
+	result, err := transformImage(img, options, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upscaled, err := decodePixels(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 32, upscaled.Rect.Dx())
+	for x := 0; x < upscaled.Rect.Dx(); x++ {
+		v := upscaled.NRGBAAt(x, 16).R
+		assert.True(t, v == 0 || v == 255, "pixel %d is blurred: %d", x, v)
+	}
+}
+
+func TestInterpolator_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewInterpolator, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "nearest",
+		Args: []interface{}{"nearest"},
+		Err:  false,
+	}, {
+		Msg:  "bicubic",
+		Args: []interface{}{"bicubic"},
+		Err:  false,
+	}, {
+		Msg:  "not supported by bimg",
+		Args: []interface{}{"lanczos3"},
+		Err:  true,
+	}, {
+		Msg:  "not a string",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"nearest", "bilinear"},
+		Err:  true,
+	}})
+}
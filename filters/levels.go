@@ -0,0 +1,96 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// LevelsName is the name of the filter
+const LevelsName = "levels"
+
+type levels struct {
+	black uint8
+	white uint8
+	gamma float64
+}
+
+// NewLevels creates a new filter of this type
+func NewLevels() filters.Spec {
+	return &levels{}
+}
+
+func (f *levels) Name() string {
+	return LevelsName
+}
+
+func (f *levels) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for levels ", f)
+
+	if f.black == 0 && f.white == 255 && f.gamma == 1 {
+		return img, nil
+	}
+
+	//the values up to the black point become black, the ones from the white point white, and the ones between them
+	//are stretched linearly before the gamma is applied on the midtones, like the gamma filter
+	var table [256]uint8
+	for i := range table {
+		value := math.Min(math.Max(float64(i)-float64(f.black), 0)/float64(f.white-f.black), 1)
+		table[i] = clampUint8(255 * math.Pow(value, 1/f.gamma))
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+// CanBeMerged refuses merging, as the clipping of a levels filter changes the range the next one stretches
+func (f *levels) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *levels) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *levels) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 3 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	l := &levels{}
+
+	black, err := parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	white, err := parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if black < 0 || white > 255 || black >= white {
+		log.Errorf("Failed to create the levels filter, the black point %d and the white point %d are not in order "+
+			"between 0 and 255", black, white)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+	l.black, l.white = uint8(black), uint8(white)
+
+	l.gamma, err = parse.EskipFloatArg(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if l.gamma <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return l, nil
+}
+
+func (f *levels) Request(ctx filters.FilterContext) {}
+
+func (f *levels) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,100 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// grayGradient is a row of the 256 gray values
+func grayGradient() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 256, 1))
+	for x := 0; x < 256; x++ {
+		img.SetNRGBA(x, 0, color.NRGBA{R: uint8(x), G: uint8(x), B: uint8(x), A: 255})
+	}
+	return img
+}
+
+func TestNewLevels(t *testing.T) {
+	name := NewLevels().Name()
+	assert.Equal(t, "levels", name)
+}
+
+func TestLevels_TransformPixels(t *testing.T) {
+	result, err := (&levels{black: 50, white: 200, gamma: 1}).TransformPixels(grayGradient())
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(0), result.NRGBAAt(0, 0).R)
+	assert.Equal(t, uint8(0), result.NRGBAAt(50, 0).R, "the black point should become black")
+	assert.Equal(t, uint8(128), result.NRGBAAt(125, 0).R, "the midpoint should become the middle gray")
+	assert.Equal(t, uint8(255), result.NRGBAAt(200, 0).R, "the white point should become white")
+	assert.Equal(t, uint8(255), result.NRGBAAt(255, 0).R)
+	assert.Equal(t, uint8(255), result.NRGBAAt(125, 0).A)
+}
+
+func TestLevels_TransformPixels_Gamma(t *testing.T) {
+	result, err := (&levels{black: 0, white: 255, gamma: 2}).TransformPixels(grayGradient())
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(0), result.NRGBAAt(0, 0).R)
+	assert.True(t, result.NRGBAAt(64, 0).R > 64, "the gamma should brighten the midtones")
+	assert.Equal(t, uint8(255), result.NRGBAAt(255, 0).R)
+}
+
+func TestLevels_TransformPixels_NoOp(t *testing.T) {
+	result, err := (&levels{black: 0, white: 255, gamma: 1}).TransformPixels(grayGradient())
+
+	assert.Nil(t, err)
+	assert.Equal(t, grayGradient(), result)
+}
+
+func TestLevels_CanBeMerged(t *testing.T) {
+	f := levels{black: 10, white: 240, gamma: 1}
+	assert.False(t, f.CanBeMerged(&levels{black: 10, white: 240, gamma: 1}))
+	assert.False(t, f.CanBeMerged(&gamma{value: 2}))
+}
+
+func TestLevels_AfterResize(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	HandleImageResponse(fc, &resize{width: 100, height: 100})
+	HandlePixelResponse(fc, &levels{black: 10, white: 240, gamma: 1})
+
+	assert.Equal(t, true, fc.FStateBag[hasMergedFilters])
+	assert.Equal(t, []PixelFilter{&levels{black: 10, white: 240, gamma: 1}}, fc.FStateBag[skropPixelFilters])
+}
+
+func TestLevels_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewLevels, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{10.0, 240.0, 1.2},
+		Err:  false,
+	}, {
+		Msg:  "black point not lower than the white point",
+		Args: []interface{}{200.0, 200.0, 1.0},
+		Err:  true,
+	}, {
+		Msg:  "white point out of range",
+		Args: []interface{}{10.0, 300.0, 1.0},
+		Err:  true,
+	}, {
+		Msg:  "negative black point",
+		Args: []interface{}{-10.0, 200.0, 1.0},
+		Err:  true,
+	}, {
+		Msg:  "zero gamma",
+		Args: []interface{}{10.0, 240.0, 0.0},
+		Err:  true,
+	}, {
+		Msg:  "more than three args",
+		Args: []interface{}{10.0, 240.0, 1.0, 1.0},
+		Err:  true,
+	}})
+}
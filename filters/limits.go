@@ -0,0 +1,103 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/h2non/bimg"
+	"github.com/zalando/skipper/filters"
+)
+
+var (
+	// ErrImageTooLarge is returned when the original image is bigger than the limits of the input images
+	ErrImageTooLarge = errors.New("processing failed, the image is too large")
+	// ErrOutputTooLarge is returned when the filters would create an image bigger than the limit of the output
+	// images. The parameters of the filters are not valid for the image, so it is an ErrInvalidFilterParameters
+	ErrOutputTooLarge = fmt.Errorf("%w: the output image would be too large", filters.ErrInvalidFilterParameters)
+)
+
+var (
+	limitsMutex sync.RWMutex
+	//there are no limits by default
+	maxInputPixels  int64
+	maxInputBytes   int64
+	maxOutputPixels int64
+)
+
+// SetMaxInputPixels limits the number of pixels of the original images, as decoding a small file of huge dimensions
+// can exhaust the memory. The bigger images are rejected with the status 413, 0 disabling the limit
+func SetMaxInputPixels(n int64) {
+	limitsMutex.Lock()
+	defer limitsMutex.Unlock()
+	maxInputPixels = n
+}
+
+// SetMaxInputBytes limits the size in bytes of the original images. The bigger images are rejected with the
+// status 413, without being read entirely, 0 disabling the limit
+func SetMaxInputBytes(n int64) {
+	limitsMutex.Lock()
+	defer limitsMutex.Unlock()
+	maxInputBytes = n
+}
+
+func inputLimits() (pixels int64, bytes int64) {
+	limitsMutex.RLock()
+	defer limitsMutex.RUnlock()
+	return maxInputPixels, maxInputBytes
+}
+
+// checkInputPixels tells if the dimensions of the image, read from its header, are within the limit
+func checkInputPixels(size bimg.ImageSize) error {
+	maxPixels, _ := inputLimits()
+	if maxPixels > 0 && int64(size.Width)*int64(size.Height) > maxPixels {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// SetMaxOutputPixels limits the number of pixels of the images created by the filters, like a resize to huge
+// dimensions. The transformations creating bigger images fail with ErrOutputTooLarge, 0 disabling the limit
+func SetMaxOutputPixels(n int64) {
+	limitsMutex.Lock()
+	defer limitsMutex.Unlock()
+	maxOutputPixels = n
+}
+
+// checkOutputPixels tells if the image created by the options is within the limit. When only one dimension is set,
+// the other one is computed keeping the ratio of the image
+func checkOutputPixels(image *bimg.Image, o *bimg.Options) error {
+	limitsMutex.RLock()
+	maxPixels := maxOutputPixels
+	limitsMutex.RUnlock()
+
+	if maxPixels <= 0 || (o.Width == 0 && o.Height == 0 && o.Zoom == 0) {
+		return nil
+	}
+
+	size, err := readImageSize(image)
+	if err != nil {
+		//the image fails when it is processed
+		return nil
+	}
+	size = orientedSize(image.Image(), size)
+
+	width, height := int64(o.Width), int64(o.Height)
+	switch {
+	case width == 0 && height == 0:
+		width, height = int64(size.Width), int64(size.Height)
+	case width == 0:
+		width = height * int64(size.Width) / int64(size.Height)
+	case height == 0:
+		height = width * int64(size.Height) / int64(size.Width)
+	}
+
+	if o.Zoom > 0 {
+		width, height = width*int64(o.Zoom), height*int64(o.Zoom)
+	}
+
+	if width*height > maxPixels {
+		return fmt.Errorf("%w: %dx%d is more than %d pixels", ErrOutputTooLarge, width, height, maxPixels)
+	}
+	return nil
+}
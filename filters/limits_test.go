@@ -0,0 +1,122 @@
+package filters
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+	"github.com/zalando/skipper/filters"
+)
+
+func withInputLimits(t *testing.T, pixels int64, bytes int64) {
+	t.Cleanup(func() {
+		SetMaxInputPixels(0)
+		SetMaxInputBytes(0)
+		SetMaxOutputPixels(0)
+	})
+	SetMaxInputPixels(pixels)
+	SetMaxInputBytes(bytes)
+}
+
+func TestInitResponse_MaxInputPixels(t *testing.T) {
+	//the image is 762x1100
+	for _, test := range []struct {
+		name      string
+		maxPixels int64
+		tooLarge  bool
+	}{
+		{"no limit", 0, false},
+		{"just under the limit", 762 * 1100, false},
+		{"just over the limit", 762*1100 - 1, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			withInputLimits(t, test.maxPixels, 0)
+			fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+
+			initResponse(fc)
+
+			assertTooLarge(t, test.tooLarge, fc.Response(), fc.FStateBag)
+		})
+	}
+}
+
+func TestInitResponse_MaxInputBytes(t *testing.T) {
+	buf, _ := bimg.Read(imagefiltertest.PNGImageFile)
+	size := int64(len(buf))
+
+	for _, test := range []struct {
+		name          string
+		maxBytes      int64
+		contentLength int64
+		tooLarge      bool
+	}{
+		{"just under the limit", size, size, false},
+		{"just over the limit", size - 1, size, true},
+		{"just under the limit without length", size, -1, false},
+		{"just over the limit without length", size - 1, -1, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			withInputLimits(t, 0, test.maxBytes)
+			fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+			fc.FResponse.ContentLength = test.contentLength
+
+			initResponse(fc)
+
+			assertTooLarge(t, test.tooLarge, fc.Response(), fc.FStateBag)
+		})
+	}
+}
+
+func assertTooLarge(t *testing.T, tooLarge bool, rsp *http.Response, bag map[string]interface{}) {
+	if tooLarge {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rsp.StatusCode)
+		assert.Nil(t, bag[skropImage])
+	} else {
+		assert.Equal(t, 0, rsp.StatusCode, "the response should not be served")
+		assert.NotNil(t, bag[skropImage])
+	}
+}
+
+func TestCheckOutputPixels(t *testing.T) {
+	//the image is 762x1100
+	image := imagefiltertest.PNGImage()
+	withInputLimits(t, 0, 0)
+	SetMaxOutputPixels(1000 * 1000)
+
+	for _, test := range []struct {
+		name     string
+		options  bimg.Options
+		tooLarge bool
+	}{
+		{"no resize", bimg.Options{Quality: 80}, false},
+		{"within the limit", bimg.Options{Width: 1000, Height: 1000}, false},
+		{"over the limit", bimg.Options{Width: 1000, Height: 1001}, true},
+		{"width keeping the ratio within the limit", bimg.Options{Width: 800}, false},
+		{"width keeping the ratio over the limit", bimg.Options{Width: 900}, true},
+		{"zoom over the limit", bimg.Options{Zoom: 2}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkOutputPixels(image, &test.options)
+			assert.Equal(t, test.tooLarge, err != nil)
+			if test.tooLarge {
+				assert.True(t, errors.Is(err, ErrOutputTooLarge))
+				assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+			}
+		})
+	}
+}
+
+func TestFinalizeResponse_MaxOutputPixels(t *testing.T) {
+	withInputLimits(t, 0, 0)
+	SetMaxOutputPixels(1000 * 1000)
+	fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+
+	err := HandleImageResponse(fc, &resize{width: 2000, height: 2000})
+	assert.Nil(t, err)
+	FinalizeResponse(fc)
+
+	assert.Equal(t, http.StatusBadRequest, fc.Response().StatusCode)
+}
@@ -26,7 +26,7 @@ func (f *longerEdgeResize) Name() string {
 func (f *longerEdgeResize) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
 	log.Debug("Create options for longer edge resize ", f)
 
-	imageSize, err := imageContext.Image.Size()
+	imageSize, err := imageContext.ImageSize()
 
 	if err != nil {
 		return nil, err
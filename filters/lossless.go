@@ -0,0 +1,77 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// LosslessName is the name of the filter
+const LosslessName = "lossless"
+
+// lossless encodes the image as a lossless WebP. It is ignored for the other output types
+type lossless struct {
+	enabled bool
+}
+
+// NewLossless creates a new filter of this type
+func NewLossless() filters.Spec {
+	return &lossless{}
+}
+
+func (f *lossless) Name() string {
+	return LosslessName
+}
+
+func (f *lossless) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for lossless ", f)
+
+	return &bimg.Options{Lossless: f.enabled}, nil
+}
+
+func (f *lossless) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//it only concerns the encoding of the image, so the last one wins
+	return true
+}
+
+func (f *lossless) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Lossless = self.Lossless
+	return other
+}
+
+func (f *lossless) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	enabled, err := parse.EskipBoolArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &lossless{enabled: enabled}, nil
+}
+
+func (f *lossless) Request(ctx filters.FilterContext) {}
+
+func (f *lossless) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
+
+// checkLossless ignores the lossless encoding when the image is not encoded as WebP
+func checkLossless(image *bimg.Image, opts *bimg.Options) {
+	if !opts.Lossless {
+		return
+	}
+
+	imageType := opts.Type
+	if imageType == bimg.UNKNOWN {
+		imageType = bimg.DetermineImageType(image.Image())
+	}
+
+	if imageType != bimg.WEBP {
+		log.Warnf("The lossless encoding is ignored, as the image is encoded as %s instead of WebP", bimg.ImageTypeName(imageType))
+		opts.Lossless = false
+	}
+}
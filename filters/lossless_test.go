@@ -0,0 +1,101 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewLossless(t *testing.T) {
+	name := NewLossless().Name()
+	assert.Equal(t, "lossless", name)
+}
+
+func TestLossless_CreateOptions(t *testing.T) {
+	options, _ := (&lossless{enabled: true}).CreateOptions(nil)
+	assert.True(t, options.Lossless)
+}
+
+func TestLossless_CanBeMerged(t *testing.T) {
+	s := lossless{}
+	self := &bimg.Options{Lossless: true}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 100, Type: bimg.WEBP, Quality: 80}, self))
+}
+
+func TestLossless_Merge(t *testing.T) {
+	s := lossless{}
+
+	opt := s.Merge(&bimg.Options{Width: 100, Type: bimg.WEBP}, &bimg.Options{Lossless: true})
+	assert.True(t, opt.Lossless)
+	assert.Equal(t, 100, opt.Width)
+
+	opt = s.Merge(&bimg.Options{Lossless: true}, &bimg.Options{Lossless: false})
+	assert.False(t, opt.Lossless)
+}
+
+func TestLossless_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewLossless, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "enabled",
+		Args: []interface{}{"true"},
+		Err:  false,
+	}, {
+		Msg:  "disabled",
+		Args: []interface{}{false},
+		Err:  false,
+	}, {
+		Msg:  "not a boolean",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{true, true},
+		Err:  true,
+	}})
+}
+
+func TestCheckLossless(t *testing.T) {
+	image := imagefiltertest.LandscapeImage()
+
+	opts := &bimg.Options{Lossless: true}
+	checkLossless(image, opts)
+	assert.False(t, opts.Lossless, "the JPEG image cannot be lossless")
+
+	opts = &bimg.Options{Lossless: true, Type: bimg.PNG}
+	checkLossless(image, opts)
+	assert.False(t, opts.Lossless, "the PNG image cannot be lossless")
+
+	opts = &bimg.Options{Lossless: true, Type: bimg.WEBP}
+	checkLossless(image, opts)
+	assert.True(t, opts.Lossless)
+}
+
+func TestLossless_Response_RoundTrip(t *testing.T) {
+	original := uniformImage(color.NRGBA{R: 30, G: 144, B: 255, A: 255})
+	buf, _ := encodePixels(original)
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(buf)
+	fc.FStateBag[hasMergedFilters] = false
+
+	(&lossless{enabled: true}).Response(fc)
+	(&convert{imageType: bimg.WEBP}).Response(fc)
+	FinalizeResponse(fc)
+
+	result := readResultImage(fc.Response().Body, t)
+	assert.Equal(t, "webp", result.Type())
+
+	pixels, err := decodePixels(result.Image())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, original.Pix, pixels.Pix, "the lossless WebP should keep the pixels")
+}
@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// MaskName is the name of the filter
+const MaskName = "mask"
+
+type mask struct {
+	file string
+	//the luminance of the mask, 0 making the pixels transparent and 255 keeping them as they are
+	alpha *image.Gray
+}
+
+// NewMask creates a new filter of this type
+func NewMask() filters.Spec {
+	return &mask{}
+}
+
+func (f *mask) Name() string {
+	return MaskName
+}
+
+func (f *mask) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for mask ", f.file)
+
+	//the mask is stretched on the image, which already has its final dimensions, by picking its nearest pixel
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	maskWidth, maskHeight := f.alpha.Rect.Dx(), f.alpha.Rect.Dy()
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		maskRow := f.alpha.Pix[(y*maskHeight/height)*f.alpha.Stride:]
+		for x := 0; x < width; x++ {
+			i := x*4 + 3
+			row[i] = uint8(uint16(row[i]) * uint16(maskRow[x*maskWidth/width]) / 255)
+		}
+	}
+
+	return img, nil
+}
+
+func (f *mask) needsAlpha() bool {
+	return true
+}
+
+func (f *mask) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *mask) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *mask) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	m := &mask{}
+
+	m.file, err = parse.EskipStringArg(args[0])
+	if err != nil {
+		return nil, parse.AtArg(err, MaskName, 1, "maskFile")
+	}
+
+	//the mask is read once, when the route is created
+	buf, err := readImage(m.file)
+	if err != nil {
+		log.Errorf("Failed to read the mask %s: %v", m.file, err)
+		return nil, err
+	}
+
+	pixels, err := decodePixels(buf)
+	if err != nil {
+		log.Errorf("Failed to decode the mask %s: %v", m.file, err)
+		return nil, err
+	}
+
+	m.alpha = image.NewGray(image.Rect(0, 0, pixels.Rect.Dx(), pixels.Rect.Dy()))
+	for y := 0; y < pixels.Rect.Dy(); y++ {
+		row := pixels.Pix[y*pixels.Stride:]
+		for x := 0; x < pixels.Rect.Dx(); x++ {
+			i := x * 4
+			m.alpha.Pix[y*m.alpha.Stride+x] = luminance(row[i], row[i+1], row[i+2])
+		}
+	}
+
+	return m, nil
+}
+
+func (f *mask) Request(ctx filters.FilterContext) {}
+
+func (f *mask) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
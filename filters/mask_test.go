@@ -0,0 +1,96 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// halfMaskFile writes a mask whose left half is black and whose right half is white
+func halfMaskFile(t *testing.T) string {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		img.SetNRGBA(0, y, color.NRGBA{A: 255})
+		img.SetNRGBA(1, y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+	buf, err := encodePixels(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ioutil.TempFile("", "mask-*.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestNewMask(t *testing.T) {
+	name := NewMask().Name()
+	assert.Equal(t, "mask", name)
+}
+
+func TestMask_TransformPixels(t *testing.T) {
+	file := halfMaskFile(t)
+	defer os.Remove(file)
+
+	f, err := NewMask().CreateFilter([]interface{}{file})
+	assert.Nil(t, err)
+
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 200}
+	result, err := f.(*mask).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	for y := 0; y < 4; y++ {
+		assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 0}, result.NRGBAAt(0, y))
+		assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 0}, result.NRGBAAt(1, y))
+		assert.Equal(t, c, result.NRGBAAt(2, y))
+		assert.Equal(t, c, result.NRGBAAt(3, y))
+	}
+}
+
+func TestMask_NeedsAlpha(t *testing.T) {
+	f := mask{}
+	assert.True(t, f.needsAlpha())
+}
+
+func TestMask_CanBeMerged(t *testing.T) {
+	f := mask{}
+	assert.False(t, f.CanBeMerged(&mask{}))
+}
+
+func TestMask_CreateFilter(t *testing.T) {
+	file := halfMaskFile(t)
+	defer os.Remove(file)
+
+	imagefiltertest.TestCreate(t, NewMask, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "mask file",
+		Args: []interface{}{file},
+		Err:  false,
+	}, {
+		Msg:  "missing file",
+		Args: []interface{}{"../images/nonExisting.png"},
+		Err:  true,
+	}, {
+		Msg:  "not a string",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{file, 1.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,76 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+)
+
+// FilterOptions is an image filter with the options it created for the image
+type FilterOptions struct {
+	Filter  ImageFilter
+	Options *bimg.Options
+}
+
+// MergeFilters plans the transformations of the image for the filters, given in the order of the route, and
+// returns the options of every transformation, in the order they are applied. It follows the rules applied by
+// HandleImageResponse:
+//   - the responses are processed in the reverse order of the route, so the last filter of the route is the
+//     first one merged
+//   - a filter is merged in the options merged so far when its CanBeMerged allows it, and its Merge combines them
+//   - otherwise the options merged so far are applied in a transformation, and the options of the filter in
+//     another one, so the filter is applied on their result
+//   - the filters processed after it are merged again starting from empty options
+//
+// HandleImageResponse creates the options of a filter which cannot be merged again, on the transformed image.
+// The options given here are used instead, so the filters whose options depend on the size of the image are
+// planned with the size they were created for
+func MergeFilters(route []FilterOptions) []*bimg.Options {
+	var transformations []*bimg.Options
+	merged, hasMerged := &bimg.Options{}, false
+
+	for i := len(route) - 1; i >= 0; i-- {
+		if options, ok := mergeFilter(route[i].Filter, merged, route[i].Options); ok {
+			merged, hasMerged = options, true
+			continue
+		}
+
+		if hasMerged {
+			transformations = append(transformations, merged)
+		}
+		transformations = append(transformations, route[i].Options)
+		merged, hasMerged = &bimg.Options{}, false
+	}
+
+	if hasMerged {
+		transformations = append(transformations, merged)
+	}
+
+	return transformations
+}
+
+// mergeFilter merges the options of the filter in the options merged so far, telling if they could be merged
+func mergeFilter(f ImageFilter, merged *bimg.Options, options *bimg.Options) (*bimg.Options, bool) {
+	if !f.CanBeMerged(merged, options) {
+		return merged, false
+	}
+	return f.Merge(merged, options), true
+}
+
+// MergePixelFilters returns the pixel filters, given in the order of the route, in the order they transform the
+// pixels. As for HandlePixelResponse, a filter is merged in the previous one when its CanBeMerged allows it.
+// All the pixel filters following each other are applied in a single decode/encode pass, merged or not
+func MergePixelFilters(route []PixelFilter) []PixelFilter {
+	var queue []PixelFilter
+	for i := len(route) - 1; i >= 0; i-- {
+		queue = queuePixelFilter(queue, route[i])
+	}
+	return queue
+}
+
+// queuePixelFilter adds the pixel filter to the queue, merging it in the last queued filter when possible
+func queuePixelFilter(queue []PixelFilter, f PixelFilter) []PixelFilter {
+	if n := len(queue); n > 0 && f.CanBeMerged(queue[n-1]) {
+		queue[n-1] = f.Merge(queue[n-1])
+		return queue
+	}
+	return append(queue, f)
+}
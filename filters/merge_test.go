@@ -0,0 +1,92 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFilters(t *testing.T) {
+	watermark := bimg.WatermarkImage{Left: 10, Top: 10, Buf: []byte("a"), Opacity: 1}
+	otherWatermark := bimg.WatermarkImage{Left: 20, Top: 20, Buf: []byte("b"), Opacity: 1}
+
+	cropOptions := func() *bimg.Options {
+		return &bimg.Options{Width: 100, Height: 80, Gravity: bimg.GravityNorth, Crop: true}
+	}
+	resizeOptions := func(width int) *bimg.Options { return &bimg.Options{Width: width, Height: width} }
+	overlayOptions := func(w bimg.WatermarkImage) *bimg.Options { return &bimg.Options{WatermarkImage: w} }
+
+	for _, test := range []struct {
+		name     string
+		route    []FilterOptions
+		expected []*bimg.Options
+	}{{
+		"no filters",
+		nil,
+		nil,
+	}, {
+		"crop then overlay stays in order in a single transformation",
+		[]FilterOptions{{&crop{}, cropOptions()}, {&overlay{}, overlayOptions(watermark)}},
+		[]*bimg.Options{{Width: 100, Height: 80, Gravity: bimg.GravityNorth, Crop: true, WatermarkImage: watermark}},
+	}, {
+		"overlay then crop is applied after the crop",
+		[]FilterOptions{{&overlay{}, overlayOptions(watermark)}, {&crop{}, cropOptions()}},
+		[]*bimg.Options{cropOptions(), overlayOptions(watermark)},
+	}, {
+		"two overlays are applied in separate transformations",
+		[]FilterOptions{{&overlay{}, overlayOptions(watermark)}, {&overlay{}, overlayOptions(otherWatermark)}},
+		[]*bimg.Options{overlayOptions(otherWatermark), overlayOptions(watermark)},
+	}, {
+		"two resizes of the same size are merged",
+		[]FilterOptions{{&resize{}, resizeOptions(50)}, {&resize{}, resizeOptions(50)}},
+		[]*bimg.Options{resizeOptions(50)},
+	}, {
+		"two resizes of different sizes are not merged",
+		[]FilterOptions{{&resize{}, resizeOptions(100)}, {&resize{}, resizeOptions(50)}},
+		[]*bimg.Options{resizeOptions(50), resizeOptions(100)},
+	}, {
+		"the output options are merged with the transformation",
+		[]FilterOptions{{&resize{}, resizeOptions(50)}, {&quality{}, &bimg.Options{Quality: 80}}},
+		[]*bimg.Options{{Width: 50, Height: 50, Quality: 80}},
+	}, {
+		"the filters after a filter which cannot be merged start from empty options",
+		[]FilterOptions{
+			{&quality{}, &bimg.Options{Quality: 80}},
+			{&crop{}, cropOptions()},
+			{&resize{}, resizeOptions(50)}},
+		[]*bimg.Options{resizeOptions(50), cropOptions(), {Quality: 80}},
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, MergeFilters(test.route))
+		})
+	}
+}
+
+func TestMergePixelFilters(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		route    []PixelFilter
+		expected []PixelFilter
+	}{{
+		"no filters",
+		nil,
+		nil,
+	}, {
+		"two compatible color filters collapse into one",
+		[]PixelFilter{&brightness{factor: 2}, &brightness{factor: 1.5}},
+		[]PixelFilter{&brightness{factor: 3}},
+	}, {
+		"different color filters are applied in the reverse order of the route",
+		[]PixelFilter{&brightness{factor: 2}, &contrast{factor: 1.5}},
+		[]PixelFilter{&contrast{factor: 1.5}, &brightness{factor: 2}},
+	}, {
+		"only the consecutive filters are merged",
+		[]PixelFilter{&brightness{factor: 2}, &contrast{factor: 1.5}, &brightness{factor: 3}},
+		[]PixelFilter{&brightness{factor: 3}, &contrast{factor: 1.5}, &brightness{factor: 2}},
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, MergePixelFilters(test.route))
+		})
+	}
+}
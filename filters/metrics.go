@@ -0,0 +1,62 @@
+package filters
+
+import (
+	"time"
+)
+
+const (
+	// ErrorKindStatus counts the responses skipped because of the status code of the backend or of a filter
+	ErrorKindStatus = "status"
+	// ErrorKindAnimated counts the animated images passed through untouched
+	ErrorKindAnimated = "animated"
+	// ErrorKindNotImage counts the responses of the backend passed through untouched, as they are not images
+	ErrorKindNotImage = "notImage"
+	// ErrorKindBusy counts the images not processed because too many are processed concurrently
+	ErrorKindBusy = "busy"
+	// ErrorKindProcessing counts the other failures while processing the image
+	ErrorKindProcessing = "processing"
+)
+
+// Metrics receives the measures of the processing of the images, labeled by the name of the filter, so they can be
+// exposed by any metrics library, like Prometheus
+type Metrics interface {
+	// ObserveDuration is called once for every response handled by the filter
+	ObserveDuration(filter string, duration time.Duration)
+	// IncErrors is called when the filter fails, with one of the ErrorKind values
+	IncErrors(filter string, kind string)
+	// AddBytes is called when the filter transforms the image, with the size of the image before and after
+	AddBytes(filter string, in int, out int)
+}
+
+type noMetrics struct{}
+
+func (noMetrics) ObserveDuration(string, time.Duration) {}
+func (noMetrics) IncErrors(string, string)              {}
+func (noMetrics) AddBytes(string, int, int)             {}
+
+var metrics Metrics = noMetrics{}
+
+// SetMetrics registers the metrics receiving the measures of the filters, nil disabling them. It needs to be
+// called before the routes are served
+func SetMetrics(m Metrics) {
+	if m == nil {
+		m = noMetrics{}
+	}
+	metrics = m
+}
+
+// errorKind classifies the error returned by the filter
+func errorKind(err error, statusSkipped bool) string {
+	switch {
+	case statusSkipped:
+		return ErrorKindStatus
+	case err == ErrAnimatedImage:
+		return ErrorKindAnimated
+	case err == ErrNotAnImage:
+		return ErrorKindNotImage
+	case err == ErrTooBusy:
+		return ErrorKindBusy
+	default:
+		return ErrorKindProcessing
+	}
+}
@@ -0,0 +1,96 @@
+package filters
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMetrics struct {
+	durations map[string]int
+	errors    map[string]int
+	bytesIn   int
+}
+
+func newFakeMetrics(t *testing.T) *fakeMetrics {
+	m := &fakeMetrics{durations: map[string]int{}, errors: map[string]int{}}
+	SetMetrics(m)
+	t.Cleanup(func() { SetMetrics(nil) })
+	return m
+}
+
+func (m *fakeMetrics) ObserveDuration(filter string, duration time.Duration) {
+	m.durations[filter]++
+}
+
+func (m *fakeMetrics) IncErrors(filter string, kind string) {
+	m.errors[filter+"/"+kind]++
+}
+
+func (m *fakeMetrics) AddBytes(filter string, in int, out int) {
+	m.bytesIn += in
+}
+
+func TestHandleImageResponse_Metrics(t *testing.T) {
+	m := newFakeMetrics(t)
+	fc := createDefaultContext(t, "url")
+
+	err := HandleImageResponse(fc, &quality{percentage: 80})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{QualityName: 1}, m.durations, "the duration should be observed once per request")
+	assert.Empty(t, m.errors)
+	assert.Equal(t, 0, m.bytesIn, "the merged filters do not transform the image")
+}
+
+func TestHandleImageResponse_MetricsErrors(t *testing.T) {
+	m := newFakeMetrics(t)
+	fc := createDefaultContext(t, "url")
+	fc.FResponse.StatusCode = 304
+
+	HandleImageResponse(fc, &quality{percentage: 80})
+
+	assert.Equal(t, 1, m.durations[QualityName])
+	assert.Equal(t, map[string]int{QualityName + "/" + ErrorKindStatus: 1}, m.errors)
+}
+
+func TestHandlePixelResponse_Metrics(t *testing.T) {
+	m := newFakeMetrics(t)
+	fc := createDefaultContext(t, "url")
+
+	err := HandlePixelResponse(fc, &threshold{level: 128})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{ThresholdName: 1}, m.durations)
+	assert.Empty(t, m.errors)
+	assert.Equal(t, 0, m.bytesIn, "the queued filters do not transform the image")
+
+	assert.Nil(t, applyPixelFilters(fc))
+	assert.NotZero(t, m.bytesIn, "the bytes should be added when the pixels are transformed")
+}
+
+func TestHandlePixelResponse_MetricsErrors(t *testing.T) {
+	m := newFakeMetrics(t)
+	fc := createDefaultContext(t, "url")
+	fc.FResponse.StatusCode = 304
+
+	HandlePixelResponse(fc, &threshold{level: 128})
+
+	assert.Equal(t, 1, m.durations[ThresholdName])
+	assert.Equal(t, map[string]int{ThresholdName + "/" + ErrorKindStatus: 1}, m.errors)
+}
+
+func TestErrorKind(t *testing.T) {
+	assert.Equal(t, ErrorKindStatus, errorKind(errors.New("skipped"), true))
+	assert.Equal(t, ErrorKindAnimated, errorKind(ErrAnimatedImage, false))
+	assert.Equal(t, ErrorKindNotImage, errorKind(ErrNotAnImage, false))
+	assert.Equal(t, ErrorKindBusy, errorKind(ErrTooBusy, false))
+	assert.Equal(t, ErrorKindProcessing, errorKind(errors.New("failed"), false))
+}
+
+func TestSetMetrics_Nil(t *testing.T) {
+	SetMetrics(nil)
+	assert.Equal(t, noMetrics{}, metrics)
+}
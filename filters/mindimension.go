@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// MinDimensionName is the name of the filter
+const MinDimensionName = "minDimension"
+
+type minDimension struct {
+	width  int
+	height int
+}
+
+// NewMinDimension creates a new filter of this type
+func NewMinDimension() filters.Spec {
+	return &minDimension{}
+}
+
+func (f *minDimension) Name() string {
+	return MinDimensionName
+}
+
+func (f *minDimension) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 2 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &minDimension{}
+
+	if c.width, err = parse.EskipIntArg(args[0]); err != nil {
+		return nil, parse.AtArg(err, MinDimensionName, 1, "width")
+	}
+
+	if c.height, err = parse.EskipIntArg(args[1]); err != nil {
+		return nil, parse.AtArg(err, MinDimensionName, 2, "height")
+	}
+
+	if c.width < 0 || c.height < 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return c, nil
+}
+
+func (f *minDimension) Request(ctx filters.FilterContext) {}
+
+// Response skips the filters processed after this one, which are the ones preceding it in the route, when the
+// image is smaller than the minimum dimensions. A dimension of 0 is not checked
+func (f *minDimension) Response(ctx filters.FilterContext) {
+	log.Debug("Response for min dimension ", f)
+
+	if processingStopped(ctx) {
+		return
+	}
+
+	initOnce(ctx)
+
+	//the error response was already served when the image could not be read, and the responses which are not images
+	//are passed through
+	image, ok := ctx.StateBag()[skropImage].(*bimg.Image)
+	if !ok || ctx.StateBag()[skropNotImage] == true {
+		return
+	}
+
+	size, err := buildParameters(ctx, image).ImageSize()
+	if err != nil {
+		log.Error("Failed to read the size of the image ", err.Error())
+		serveError(ctx, errorResponse())
+		return
+	}
+
+	if size.Width < f.width || size.Height < f.height {
+		log.Debugf("The image of %dx%d is smaller than %dx%d, the following filters are skipped",
+			size.Width, size.Height, f.width, f.height)
+		ctx.StateBag()[skropSkipped] = true
+	}
+}
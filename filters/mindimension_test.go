@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewMinDimension(t *testing.T) {
+	assert.Equal(t, "minDimension", NewMinDimension().Name())
+}
+
+func TestMinDimension_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewMinDimension, []imagefiltertest.CreateTestItem{{
+		"no args",
+		nil,
+		true,
+	}, {
+		"one arg",
+		[]interface{}{800.0},
+		true,
+	}, {
+		"two args",
+		[]interface{}{800.0, 600.0},
+		false,
+	}, {
+		"only the width",
+		[]interface{}{800.0, 0.0},
+		false,
+	}, {
+		"negative dimension",
+		[]interface{}{-1.0, 600.0},
+		true,
+	}, {
+		"invalid dimension",
+		[]interface{}{800.0, "600"},
+		true,
+	}, {
+		"too many args",
+		[]interface{}{800.0, 600.0, 1.0},
+		true,
+	}})
+}
+
+func TestMinDimension_Response_SmallImage(t *testing.T) {
+	fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+	original := readResultImage(createDefaultContext(t, "url").Response().Body, t).Image()
+
+	f := &minDimension{width: 1000, height: 1000}
+	f.Response(fc)
+	assert.Equal(t, true, fc.FStateBag[skropSkipped])
+
+	err := HandleImageResponse(fc, &resize{width: 100, height: 100})
+	assert.Nil(t, err)
+	err = HandlePixelResponse(fc, &negate{active: true})
+	assert.Nil(t, err)
+
+	FinalizeResponse(fc)
+
+	assert.Equal(t, original, readResultImage(fc.Response().Body, t).Image())
+}
+
+func TestMinDimension_Response_LargeImage(t *testing.T) {
+	fc := createContext(t, "GET", "url", imagefiltertest.PNGImageFile, map[string]interface{}{})
+
+	f := &minDimension{width: 500, height: 0}
+	f.Response(fc)
+	assert.Nil(t, fc.FStateBag[skropSkipped])
+
+	err := HandleImageResponse(fc, &resize{width: 100, height: 100})
+	assert.Nil(t, err)
+
+	FinalizeResponse(fc)
+
+	size, _ := readResultImage(fc.Response().Body, t).Size()
+	assert.Equal(t, 100, size.Width)
+}
@@ -0,0 +1,65 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// NegateName is the name of the filter
+const NegateName = "negate"
+
+type negate struct {
+	//false when two negations cancelled each other
+	active bool
+}
+
+// NewNegate creates a new filter of this type
+func NewNegate() filters.Spec {
+	return &negate{}
+}
+
+func (f *negate) Name() string {
+	return NegateName
+}
+
+func (f *negate) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for negate ", f)
+
+	if !f.active {
+		return img, nil
+	}
+
+	var table [256]uint8
+	for i := range table {
+		table[i] = uint8(255 - i)
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+func (f *negate) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*negate)
+	return ok
+}
+
+func (f *negate) Merge(other PixelFilter) PixelFilter {
+	//two negations cancel each other
+	return &negate{active: other.(*negate).active != f.active}
+}
+
+func (f *negate) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &negate{active: true}, nil
+}
+
+func (f *negate) Request(ctx filters.FilterContext) {}
+
+func (f *negate) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewNegate(t *testing.T) {
+	name := NewNegate().Name()
+	assert.Equal(t, "negate", name)
+}
+
+func TestNegate_Name(t *testing.T) {
+	c := negate{}
+	assert.Equal(t, "negate", c.Name())
+}
+
+func TestNegate_TransformPixels(t *testing.T) {
+	f := negate{active: true}
+	img := uniformImage(color.NRGBA{R: 0, G: 100, B: 255, A: 128})
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 255, G: 155, B: 0, A: 128}, result.NRGBAAt(0, 0))
+}
+
+func TestNegate_TransformPixels_Twice(t *testing.T) {
+	f := negate{active: true}
+	img, err := decodePixels(imagefiltertest.PNGImage().Image())
+	assert.Nil(t, err)
+	original := append([]uint8(nil), img.Pix...)
+
+	result, _ := f.TransformPixels(img)
+	result, _ = f.TransformPixels(result)
+
+	assert.Equal(t, original, result.Pix)
+}
+
+func TestNegate_CanBeMerged_True(t *testing.T) {
+	f := negate{active: true}
+	assert.True(t, f.CanBeMerged(&negate{active: true}))
+}
+
+func TestNegate_CanBeMerged_False(t *testing.T) {
+	f := negate{active: true}
+	assert.False(t, f.CanBeMerged(&gamma{value: 2}))
+}
+
+func TestNegate_Merge(t *testing.T) {
+	f := negate{active: true}
+
+	merged := f.Merge(&negate{active: true})
+	assert.False(t, merged.(*negate).active, "two negations should cancel each other")
+
+	merged = f.Merge(merged)
+	assert.True(t, merged.(*negate).active)
+}
+
+func TestNegate_Merge_Identity(t *testing.T) {
+	f := negate{active: true}
+	img, err := decodePixels(imagefiltertest.PNGImage().Image())
+	assert.Nil(t, err)
+	original := append([]uint8(nil), img.Pix...)
+
+	result, _ := f.Merge(&negate{active: true}).TransformPixels(img)
+
+	assert.Equal(t, original, result.Pix)
+}
+
+func TestNegate_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewNegate, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}})
+}
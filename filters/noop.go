@@ -0,0 +1,50 @@
+package filters
+
+import (
+	"reflect"
+
+	"github.com/h2non/bimg"
+)
+
+// isIdentity tells if the options, with their defaults applied, would encode the image again without changing it,
+// like a crop of the size of the image or the maximum quality without any conversion, so the image can be kept
+// as it is
+func isIdentity(image *bimg.Image, o *bimg.Options, keepAlpha bool) bool {
+	identity := *o
+	imageType := bimg.DetermineImageType(image.Image())
+
+	if identity.Type == imageType {
+		identity.Type = bimg.UNKNOWN
+	}
+
+	//the quality cannot be increased by encoding the image again
+	if identity.Quality == Quality {
+		identity.Quality = 0
+	}
+
+	//a pending orientation is checked before, otherwise the orientation is already applied on the pixels, or preserved
+	identity.NoAutoRotate = false
+
+	if identity.Width > 0 || identity.Height > 0 {
+		size, err := image.Size()
+		if err != nil || identity.Width != size.Width || identity.Height != size.Height {
+			return false
+		}
+		identity.Width, identity.Height = 0, 0
+		identity.Crop, identity.Embed, identity.Force, identity.Enlarge = false, false, false, false
+		identity.Gravity = bimg.GravityCentre
+	}
+
+	//the background applied by default only flattens the transparent images
+	if !keepAlpha && identity.Background == (bimg.Color{R: 255, G: 255, B: 255}) {
+		if supportsAlpha(imageType) {
+			metadata, err := image.Metadata()
+			if err != nil || metadata.Alpha {
+				return false
+			}
+		}
+		identity.Background = bimg.Color{}
+	}
+
+	return reflect.DeepEqual(identity, bimg.Options{})
+}
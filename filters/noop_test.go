@@ -0,0 +1,53 @@
+package filters
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestIsIdentity(t *testing.T) {
+	image := imagefiltertest.LandscapeImage()
+	size, _ := image.Size()
+
+	for _, test := range []struct {
+		name     string
+		options  bimg.Options
+		expected bool
+	}{
+		{"no options", bimg.Options{}, true},
+		{"crop of the size of the image", bimg.Options{Width: size.Width, Height: size.Height, Crop: true, Gravity: bimg.GravityCentre}, true},
+		{"maximum quality", bimg.Options{Quality: 100}, true},
+		{"conversion to the same type", bimg.Options{Type: bimg.JPEG}, true},
+		{"smaller crop", bimg.Options{Width: 100, Height: 100, Crop: true}, false},
+		{"crop of the width only", bimg.Options{Width: size.Width, Crop: true}, false},
+		{"lower quality", bimg.Options{Quality: 80}, false},
+		{"conversion", bimg.Options{Type: bimg.PNG}, false},
+		{"stripped metadata", bimg.Options{StripMetadata: true}, false},
+		{"interlaced", bimg.Options{Interlace: true}, false},
+		{"overlay", bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte("overlay")}}, false},
+		{"blur", bimg.Options{GaussianBlur: bimg.GaussianBlur{Sigma: 2}}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isIdentity(image, applyDefaults(&test.options, false), false))
+		})
+	}
+}
+
+func TestHandleImageResponse_IdentityCrop(t *testing.T) {
+	fc := createContext(t, "GET", "url", imagefiltertest.LandscapeImageFile, map[string]interface{}{})
+	original := imagefiltertest.LandscapeImage().Image()
+	size, _ := imagefiltertest.LandscapeImage().Size()
+
+	err := HandleImageResponse(fc, &crop{width: size.Width, height: size.Height, cropType: Center})
+	assert.Nil(t, err)
+	FinalizeResponse(fc)
+
+	result, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, len(original), len(result))
+	assert.Equal(t, sha256.Sum256(original), sha256.Sum256(result), "the image should be kept as it is")
+}
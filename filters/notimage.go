@@ -0,0 +1,28 @@
+package filters
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrNotAnImage is returned by the filters when the backend did not respond with an image, like an HTML error page.
+// The body is passed through untouched, as it cannot be processed
+var ErrNotAnImage = errors.New("processing skipped, the response is not an image")
+
+// isImageResponse tells if the response of the backend is an image. The content type of the response is trusted
+// when it is known, otherwise the body is sniffed for the HTML pages. The images whose type is not supported are
+// still rejected when they are processed
+func isImageResponse(rsp *http.Response, buf []byte) bool {
+	contentType := rsp.Header.Get("Content-Type")
+	if contentType == "" {
+		return !strings.HasPrefix(http.DetectContentType(buf), "text/html")
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	return strings.HasPrefix(mediaType, "image/") || mediaType == "application/octet-stream"
+}
@@ -0,0 +1,71 @@
+package filters
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+const htmlPage = "<!DOCTYPE html><html><body><h1>502 Bad Gateway</h1></body></html>"
+
+func TestIsImageResponse(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		contentType string
+		body        string
+		expected    bool
+	}{
+		{"image content type", "image/jpeg", "\xff\xd8\xff", true},
+		{"binary content type", "application/octet-stream", "\xff\xd8\xff", true},
+		{"html content type", "text/html; charset=utf-8", htmlPage, false},
+		{"json content type", "application/json", "{}", false},
+		{"unknown content type with html body", "", htmlPage, false},
+		{"unknown content type with image body", "", "\xff\xd8\xff", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rsp := &http.Response{Header: make(http.Header)}
+			if test.contentType != "" {
+				rsp.Header.Set("Content-Type", test.contentType)
+			}
+			assert.Equal(t, test.expected, isImageResponse(rsp, []byte(test.body)))
+		})
+	}
+}
+
+func TestHandleImageResponse_NotAnImage(t *testing.T) {
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.StatusCode = http.StatusOK
+	fc.FResponse.Header.Set("Content-Type", "text/html")
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewBufferString(htmlPage))
+
+	err := HandleImageResponse(fc, &resize{width: 100, height: 100})
+	assert.Equal(t, ErrNotAnImage, err)
+	err = HandlePixelResponse(fc, &negate{active: true})
+	assert.Equal(t, ErrNotAnImage, err)
+
+	FinalizeResponse(fc)
+
+	assert.Equal(t, http.StatusOK, fc.Response().StatusCode)
+	assert.Equal(t, "text/html", fc.Response().Header.Get("Content-Type"))
+	body, _ := ioutil.ReadAll(fc.Response().Body)
+	assert.Equal(t, htmlPage, string(body))
+	assert.Equal(t, 0, Passes(fc))
+}
+
+func TestHandleImageResponse_Image(t *testing.T) {
+	fc := createContext(t, "GET", "url", imagefiltertest.LandscapeImageFile, map[string]interface{}{})
+	fc.FResponse.Header.Set("Content-Type", "image/jpeg")
+
+	err := HandleImageResponse(fc, &resize{width: 100, height: 100})
+	assert.Nil(t, err)
+
+	FinalizeResponse(fc)
+
+	assert.Equal(t, 1, Passes(fc))
+	size, _ := readResultImage(fc.Response().Body, t).Size()
+	assert.Equal(t, 100, size.Width)
+}
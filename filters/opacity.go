@@ -0,0 +1,86 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// OpacityName is the name of the filter
+const OpacityName = "opacity"
+
+type opacity struct {
+	factor float64
+}
+
+// NewOpacity creates a new filter of this type
+func NewOpacity() filters.Spec {
+	return &opacity{}
+}
+
+func (f *opacity) Name() string {
+	return OpacityName
+}
+
+func (f *opacity) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for opacity ", f)
+
+	if f.factor == 1 {
+		return img, nil
+	}
+
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 3; i < len(row); i += 4 {
+			row[i] = clampUint8(float64(row[i]) * f.factor)
+		}
+	}
+
+	return img, nil
+}
+
+// needsAlpha tells if the image becomes transparent, a factor of 1 leaving it as it is
+func (f *opacity) needsAlpha() bool {
+	return f.factor < 1
+}
+
+func (f *opacity) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*opacity)
+	return ok
+}
+
+func (f *opacity) Merge(other PixelFilter) PixelFilter {
+	return &opacity{factor: other.(*opacity).factor * f.factor}
+}
+
+func (f *opacity) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	o := &opacity{}
+
+	o.factor, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	//the factor is clamped like the opacity of overlayImage
+	if o.factor < 0 {
+		o.factor = 0
+	} else if o.factor > 1.0 {
+		o.factor = 1
+	}
+
+	return o, nil
+}
+
+func (f *opacity) Request(ctx filters.FilterContext) {}
+
+func (f *opacity) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewOpacity(t *testing.T) {
+	name := NewOpacity().Name()
+	assert.Equal(t, "opacity", name)
+}
+
+func TestOpacity_TransformPixels(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 200}
+
+	result, err := (&opacity{factor: 0.5}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 200, G: 100, B: 50, A: 100}, result.NRGBAAt(1, 1))
+}
+
+func TestOpacity_TransformPixels_NoOp(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 200}
+
+	result, err := (&opacity{factor: 1}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result)
+	assert.False(t, (&opacity{factor: 1}).needsAlpha())
+}
+
+func TestOpacity_TransformPixels_Transparent(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	result, err := (&opacity{factor: 0}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			assert.Equal(t, uint8(0), result.NRGBAAt(x, y).A)
+		}
+	}
+	assert.True(t, (&opacity{factor: 0}).needsAlpha())
+}
+
+func TestOpacity_Merge(t *testing.T) {
+	f := opacity{factor: 0.5}
+
+	assert.True(t, f.CanBeMerged(&opacity{factor: 0.5}))
+	assert.False(t, f.CanBeMerged(&brightness{factor: 0.5}))
+	assert.Equal(t, &opacity{factor: 0.25}, f.Merge(&opacity{factor: 0.5}))
+}
+
+func TestOpacity_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewOpacity, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{0.5},
+		Err:  false,
+	}, {
+		Msg:  "not a number",
+		Args: []interface{}{"half"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{0.5, 1.0},
+		Err:  true,
+	}})
+}
+
+func TestOpacity_CreateFilter_Clamped(t *testing.T) {
+	f, err := NewOpacity().CreateFilter([]interface{}{1.5})
+	assert.Nil(t, err)
+	assert.Equal(t, &opacity{factor: 1}, f)
+
+	f, err = NewOpacity().CreateFilter([]interface{}{-0.5})
+	assert.Nil(t, err)
+	assert.Equal(t, &opacity{factor: 0}, f)
+}
@@ -0,0 +1,135 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// OrientationBake rotates the pixels of the images by their EXIF orientation, which is then reset
+	OrientationBake = "BAKE"
+	// OrientationPreserve keeps the pixels as they are stored, together with their EXIF orientation
+	OrientationPreserve = "PRESERVE"
+	orientationTag      = 0x0112
+	// the orientation of the images stored as they are displayed
+	orientationNormal = 1
+	// the first of the orientations swapping the width and the height of the image
+	orientationTransposed = 5
+	orientationMax        = 8
+)
+
+// bakeOrientation rotates the pixels of the JPEG image by its EXIF orientation and resets the orientation,
+// so that the clients ignoring the EXIF tags display the image like the others. It tells if the image was rotated
+func bakeOrientation(buf []byte) ([]byte, bool, error) {
+	if !orientationPending(buf) {
+		return buf, false, nil
+	}
+
+	log.Debug("Rotate the image by its EXIF orientation")
+
+	//bimg rotates the image by its orientation, but it keeps the orientation tag as it is
+	rotated, err := processImage(bimg.NewImage(buf), bimg.Options{Quality: Quality})
+	if err != nil {
+		return nil, false, err
+	}
+
+	setJPEGOrientation(rotated, orientationNormal)
+	return rotated, true, nil
+}
+
+// orientationPending tells if the pixels of the image still need to be rotated by its EXIF orientation. bimg
+// rotates them whenever it processes the image, so they are only baked on their own when no bimg pass does it
+func orientationPending(buf []byte) bool {
+	if preserveOrientation || bimg.DetermineImageType(buf) != bimg.JPEG {
+		return false
+	}
+	orientation := jpegOrientation(buf)
+	return orientation > orientationNormal && orientation <= orientationMax
+}
+
+// orientedSize returns the size of the image as it is displayed, the orientations from 5 on turning it by 90 degrees
+func orientedSize(buf []byte, size bimg.ImageSize) bimg.ImageSize {
+	if orientationPending(buf) && jpegOrientation(buf) >= orientationTransposed {
+		size.Width, size.Height = size.Height, size.Width
+	}
+	return size
+}
+
+// jpegOrientation returns the EXIF orientation of the JPEG image, 0 if it is missing
+func jpegOrientation(buf []byte) int {
+	offset, order := orientationOffset(buf)
+	if offset < 0 {
+		return 0
+	}
+	return int(order.Uint16(buf[offset:]))
+}
+
+// setJPEGOrientation changes the EXIF orientation of the JPEG image in place, if the image has one
+func setJPEGOrientation(buf []byte, orientation int) {
+	if offset, order := orientationOffset(buf); offset >= 0 {
+		order.PutUint16(buf[offset:], uint16(orientation))
+	}
+}
+
+// orientationOffset finds the value of the orientation in the first IFD of the EXIF segment, -1 if it is missing
+func orientationOffset(buf []byte) (int, binary.ByteOrder) {
+	if len(buf) < 2 || buf[0] != 0xFF || buf[1] != 0xD8 {
+		return -1, nil
+	}
+
+	for i := 2; i+4 <= len(buf) && buf[i] == 0xFF && buf[i+1] != 0xDA; {
+		end := i + 2 + int(binary.BigEndian.Uint16(buf[i+2:]))
+		if end > len(buf) {
+			return -1, nil
+		}
+
+		if data := buf[i+4 : end]; buf[i+1] == 0xE1 && bytes.HasPrefix(data, []byte("Exif\x00\x00")) {
+			if offset, order := tiffOrientationOffset(data[6:]); offset >= 0 {
+				return i + 4 + 6 + offset, order
+			}
+			return -1, nil
+		}
+		i = end
+	}
+
+	return -1, nil
+}
+
+// tiffOrientationOffset finds the value of the orientation in the first IFD of the TIFF data
+func tiffOrientationOffset(tiff []byte) (int, binary.ByteOrder) {
+	if len(tiff) < 8 {
+		return -1, nil
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "MM":
+		order = binary.BigEndian
+	case "II":
+		order = binary.LittleEndian
+	default:
+		return -1, nil
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return -1, nil
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		entry := ifd + 2 + 12*e
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			//the short value is stored at the start of the value field
+			return entry + 8, order
+		}
+	}
+
+	return -1, nil
+}
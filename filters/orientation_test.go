@@ -0,0 +1,179 @@
+package filters
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io/ioutil"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+)
+
+// exifOrientation returns an EXIF segment with the orientation, little endian and with another tag before it
+func exifOrientation(orientation byte) []byte {
+	return jpegSegment(0xE1, []byte{
+		'E', 'x', 'i', 'f', 0, 0,
+		'I', 'I', 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00,
+		0x02, 0x00,
+		//image width, long, count 1, value 40
+		0x00, 0x01, 0x04, 0x00, 0x01, 0x00, 0x00, 0x00, 0x28, 0x00, 0x00, 0x00,
+		//orientation, short, count 1
+		0x12, 0x01, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00, orientation, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	})
+}
+
+// rotatedFixture is stored as 40x20, red on the left and blue on the right, with the EXIF orientation 6,
+// so it is displayed as 20x40, red on the top and blue on the bottom
+func rotatedFixture() []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				img.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				img.SetNRGBA(x, y, color.NRGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100})
+	return withSegments(buf.Bytes(), exifOrientation(6))
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	assert.Equal(t, 6, jpegOrientation(rotatedFixture()))
+	assert.Equal(t, 6, jpegOrientation(exifRotatedImage()))
+
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4)), nil)
+	assert.Equal(t, 0, jpegOrientation(buf.Bytes()))
+
+	png, _ := encodePixels(uniformImage(color.NRGBA{A: 255}))
+	assert.Equal(t, 0, jpegOrientation(png))
+}
+
+func TestSetJPEGOrientation(t *testing.T) {
+	for _, buf := range [][]byte{rotatedFixture(), exifRotatedImage()} {
+		setJPEGOrientation(buf, 1)
+		assert.Equal(t, 1, jpegOrientation(buf))
+
+		_, err := jpeg.Decode(bytes.NewReader(buf))
+		assert.Nil(t, err)
+	}
+}
+
+func TestBakeOrientation_NotRotated(t *testing.T) {
+	original, _ := bimg.Read("../images/lisbon-tram.jpg")
+
+	buf, rotated, err := bakeOrientation(original)
+	assert.Nil(t, err)
+	assert.False(t, rotated)
+	assert.Equal(t, original, buf, "the image without orientation is kept as it is")
+}
+
+func TestOrientedSize(t *testing.T) {
+	size := bimg.ImageSize{Width: 40, Height: 20}
+	assert.Equal(t, bimg.ImageSize{Width: 20, Height: 40}, orientedSize(rotatedFixture(), size))
+
+	upsideDown := rotatedFixture()
+	setJPEGOrientation(upsideDown, 3)
+	assert.Equal(t, size, orientedSize(upsideDown, size))
+
+	preserveOrientation = true
+	defer func() { preserveOrientation = false }()
+	assert.Equal(t, size, orientedSize(rotatedFixture(), size))
+}
+
+func TestInitResponse_DefersOrientation(t *testing.T) {
+	original := rotatedFixture()
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(original))
+
+	initResponse(fc)
+
+	//the following bimg pass rotates the image, so it is not rotated on its own
+	image := fc.FStateBag[skropImage].(*bimg.Image)
+	assert.Equal(t, original, image.Image())
+
+	size, err := buildParameters(fc, image).ImageSize()
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.ImageSize{Width: 20, Height: 40}, size)
+}
+
+func TestFinalizeResponse_BakesOrientation(t *testing.T) {
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(rotatedFixture()))
+
+	initOnce(fc)
+	FinalizeResponse(fc)
+
+	buf, err := ioutil.ReadAll(fc.Response().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, Passes(fc), "the image should be rotated in a single pass")
+	assert.Equal(t, 1, jpegOrientation(buf), "the orientation should be neutralized")
+
+	pixels, err := jpeg.Decode(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 20, pixels.Bounds().Dx())
+	assert.Equal(t, 40, pixels.Bounds().Dy())
+
+	r, _, b, _ := pixels.At(10, 5).RGBA()
+	assert.True(t, r > b, "the top should be red")
+	r, _, b, _ = pixels.At(10, 35).RGBA()
+	assert.True(t, b > r, "the bottom should be blue")
+}
+
+func TestHandleImageResponse_OrientationInTheSamePass(t *testing.T) {
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(rotatedFixture()))
+
+	//the crop is computed on the image as it is displayed, and bimg rotates it in the same pass
+	(&crop{width: 20, height: 10, cropType: North}).Response(fc)
+	FinalizeResponse(fc)
+
+	buf, err := ioutil.ReadAll(fc.Response().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, Passes(fc))
+	assert.Equal(t, 1, jpegOrientation(buf), "the orientation should be neutralized")
+
+	pixels, err := jpeg.Decode(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, image.Rect(0, 0, 20, 10), pixels.Bounds())
+	r, _, b, _ := pixels.At(10, 5).RGBA()
+	assert.True(t, r > b, "the top should be red")
+}
+
+func TestApplyDefaults_PreserveOrientation(t *testing.T) {
+	assert.False(t, applyDefaults(&bimg.Options{}, false).NoAutoRotate)
+
+	preserveOrientation = true
+	defer func() { preserveOrientation = false }()
+
+	assert.True(t, applyDefaults(&bimg.Options{}, false).NoAutoRotate)
+}
+
+func TestInitResponse_PreserveOrientation(t *testing.T) {
+	preserveOrientation = true
+	defer func() { preserveOrientation = false }()
+
+	original := rotatedFixture()
+	fc := createContext(t, "GET", "url", "", map[string]interface{}{})
+	fc.FResponse.Body = ioutil.NopCloser(bytes.NewReader(original))
+
+	initResponse(fc)
+
+	assert.Equal(t, original, fc.FStateBag[skropImage].(*bimg.Image).Image())
+}
@@ -1,72 +1,54 @@
 package filters
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/h2non/bimg"
 	"github.com/zalando-stups/skrop/parse"
 	"github.com/zalando/skipper/filters"
-	"github.com/h2non/bimg"
+	"image"
+	"image/draw"
+	"io/fs"
 	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
 	// OverlayImageName is the name of the filter
 	OverlayImageName = "overlayImage"
-	// NE North East
-	NE = "NE"
-	// NC North Center
-	NC = "NC"
-	// NW North West
-	NW = "NW"
-	// CE Centre East
-	CE = "CE"
-	// CC Centre Center
-	CC = "CC"
-	// CW Centre West
-	CW = "CW"
-	// SE South East
-	SE = "SE"
-	// SC South Center
-	SC = "SC"
-	// SW South West
-	SW = "SW"
+	// Tile repeats the overlay image over the whole image
+	Tile            = "TILE"
+	fetchTimeout    = 10 * time.Second
+	maxOverlayScale = 100
+	dataURIPrefix   = "data:"
 )
 
 var (
-	gravityType = map[string]bool{
-		NE: true,
-		NC: true,
-		NW: true,
-		CE: true,
-		CC: true,
-		CW: true,
-		SE: true,
-		SC: true,
-		SW: true,
-	}
-	verticalGravity = map[string]bimg.Gravity{
-		NE: bimg.GravityNorth,
-		NC: bimg.GravityNorth,
-		NW: bimg.GravityNorth,
-		CE: bimg.GravityCentre,
-		CC: bimg.GravityCentre,
-		CW: bimg.GravityCentre,
-		SE: bimg.GravitySouth,
-		SC: bimg.GravitySouth,
-		SW: bimg.GravitySouth,
-	}
-	horizontalGravity = map[string]bimg.Gravity{
-		NE: bimg.GravityEast,
-		NC: bimg.GravityCentre,
-		NW: bimg.GravityWest,
-		CE: bimg.GravityEast,
-		CC: bimg.GravityCentre,
-		CW: bimg.GravityWest,
-		SE: bimg.GravityEast,
-		SC: bimg.GravityCentre,
-		SW: bimg.GravityWest,
-	}
+	fetchClient = &http.Client{Timeout: fetchTimeout}
+	//the images fetched over HTTP, by URL
+	fetchedImages sync.Map
+	//the images being fetched, by URL
+	fetchCalls sync.Map
+	//the overlay images, by file or URL
+	overlayImages sync.Map
 )
 
+// cachedOverlay is an overlay image read before, with the modification time and the size of its file
+type cachedOverlay struct {
+	buf      []byte
+	size     bimg.ImageSize
+	modTime  time.Time
+	fileSize int64
+}
+
 type overlay struct {
 	file              string
 	opacity           float64
@@ -76,6 +58,19 @@ type overlay struct {
 	leftMargin        int
 	topMargin         int
 	bottomMargin      int
+	tile              bool
+	scalePercent      float64
+	angle             int
+	//the margins in percent of the size of the image, replacing the ones in pixels when set
+	topPercent    float64
+	rightPercent  float64
+	bottomPercent float64
+	leftPercent   float64
+	blend         string
+	//the file system of the overlay images, the one of the OS if not set
+	fsys fs.FS
+	//the overlay images read from the file system, the ones of the OS if not set
+	images *sync.Map
 }
 
 // NewOverlayImage creates a new filter of this type
@@ -83,66 +78,116 @@ func NewOverlayImage() filters.Spec {
 	return &overlay{}
 }
 
+// NewOverlayImageFromFS creates a new filter of this type, reading the overlay images from the file system,
+// like the one of the files embedded in the binary
+func NewOverlayImageFromFS(fsys fs.FS) filters.Spec {
+	return &overlay{fsys: fsys, images: &sync.Map{}}
+}
+
 func (f *overlay) Name() string {
 	return OverlayImageName
 }
 
 func (f *overlay) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
-	origSize, err := imageContext.Image.Size()
+	origSize, err := imageContext.ImageSize()
 	if err != nil {
 		return nil, err
 	}
 
-	overArr, err := readImage(f.file)
+	watermark, err := f.watermark(origSize)
 	if err != nil {
 		return nil, err
 	}
 
-	overImage := bimg.NewImage(overArr)
-	overSize, err := overImage.Size()
+	return &bimg.Options{WatermarkImage: *watermark}, nil
+}
+
+// watermark returns the overlay image and where it is placed on the image of the given size
+func (f *overlay) watermark(origSize bimg.ImageSize) (*bimg.WatermarkImage, error) {
+	over, err := f.loadOverlay()
 	if err != nil {
 		return nil, err
 	}
+	overArr, overSize := over.buf, over.size
+
+	if f.scalePercent > 0 {
+		overArr, overSize, err = scaleOverlay(overArr, overSize, origSize.Width, f.scalePercent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if f.angle != 0 {
+		overArr, overSize, err = rotateOverlay(overArr, f.angle)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//bimg places the watermark only once, so the tiles are drawn on a watermark as big as the image
+	if f.tile {
+		tiled, err := tileOverlay(overArr, origSize)
+		if err != nil {
+			return nil, err
+		}
+		return &bimg.WatermarkImage{Buf: tiled,
+			Opacity: float32(f.opacity),
+		}, nil
+	}
+
+	topMargin := marginPixels(f.topMargin, f.topPercent, origSize.Height)
+	rightMargin := marginPixels(f.rightMargin, f.rightPercent, origSize.Width)
+	bottomMargin := marginPixels(f.bottomMargin, f.bottomPercent, origSize.Height)
+	leftMargin := marginPixels(f.leftMargin, f.leftPercent, origSize.Width)
 
 	var x, y int
 	switch f.verticalGravity {
 	case bimg.GravityNorth:
-		y = f.topMargin
+		y = topMargin
 	case bimg.GravityCentre:
-		y = f.topMargin + int(float64(origSize.Height-f.topMargin-f.bottomMargin)/2) - int(
-			float64(overSize.Height)/2)
+		//the margins shift the centered overlay, by the difference between them
+		y = origSize.Height/2 - overSize.Height/2 + topMargin - bottomMargin
 	case bimg.GravitySouth:
-		y = origSize.Height - f.bottomMargin - overSize.Height
+		y = origSize.Height - bottomMargin - overSize.Height
 	}
 
 	switch f.horizontalGravity {
 	case bimg.GravityWest:
-		x = f.leftMargin
+		x = leftMargin
 	case bimg.GravityCentre:
-		x = f.leftMargin + int(float64(origSize.Width-f.leftMargin-f.rightMargin)/2) - int(float64(overSize.Width)/2)
+		x = origSize.Width/2 - overSize.Width/2 + leftMargin - rightMargin
 	case bimg.GravityEast:
-		x = origSize.Width - f.rightMargin - overSize.Width
+		x = origSize.Width - rightMargin - overSize.Width
 	}
 
-	return &bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: overArr,
+	return &bimg.WatermarkImage{Buf: overArr,
 		Opacity: float32(f.opacity),
 		Left:    x,
 		Top:     y,
-	}}, nil
+	}, nil
 }
 
 func (f *overlay) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
 	zero := bimg.WatermarkImage{}
 
-	//it can be merged if the background was not set (in options or in self) or if they are set to the same value
-	return other.Width == 0 && other.Height == 0 && (equals(other.WatermarkImage, zero) || equals(other.WatermarkImage, self.WatermarkImage))
+	//it can be merged if the background was not set (in options or in self) or if they are set to the same value.
+	//bimg composites a single watermark, so another overlay is applied in a separate pass
+	return other.Width == 0 && other.Height == 0 && !geometryPending(other) &&
+		(equals(other.WatermarkImage, zero) || equals(other.WatermarkImage, self.WatermarkImage))
+}
+
+// geometryPending checks if a rotation, flip, flop, zoom, extract or trim is waiting to be applied. bimg applies
+// them before placing the overlay, which is positioned based on the size of the image before them
+func geometryPending(other *bimg.Options) bool {
+	return other.Rotate != 0 || other.Flip || other.Flop || other.Zoom != 0 ||
+		other.AreaWidth != 0 || other.AreaHeight != 0 || other.Trim
 }
 
 func equals(one bimg.WatermarkImage, two bimg.WatermarkImage) bool {
 	return one.Opacity == two.Opacity &&
 		one.Top == two.Top &&
 		one.Left == two.Left &&
-		len(one.Buf) == len(two.Buf)
+		bytes.Equal(one.Buf, two.Buf)
 }
 
 func (f *overlay) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
@@ -150,7 +195,197 @@ func (f *overlay) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
 	return other
 }
 
+// marginPixels returns the margin in pixels, computing it from the percentage of the size of the image if set
+func marginPixels(pixels int, percent float64, size int) int {
+	if percent > 0 {
+		return int(math.Floor(float64(size)*percent/100 + 0.5))
+	}
+	return pixels
+}
+
+// isMargin tells if the string looks like a margin, in pixels or as a percentage. Only the percentages are valid
+// margins, but the numbers are not taken for a blend mode either
+func isMargin(arg string) bool {
+	_, _, err := parse.EskipPercentageArg(arg)
+	return err == nil
+}
+
+// parseMargin parses the margin either in pixels or as a percentage of the size of the image, like "5%"
+func parseMargin(arg interface{}) (int, float64, error) {
+	value, percentage, err := parse.EskipPercentageArg(arg)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !percentage {
+		pixels, err := parse.EskipIntArg(arg)
+		return pixels, 0, err
+	}
+
+	if value < 0 || value >= 100 {
+		return 0, 0, filters.ErrInvalidFilterParameters
+	}
+
+	return 0, value, nil
+}
+
+// scaleOverlay resizes the overlay image to have the percentage of the width of the image, keeping its ratio
+func scaleOverlay(buf []byte, size bimg.ImageSize, width int, percent float64) ([]byte, bimg.ImageSize, error) {
+	scaled := scaledOverlaySize(size, width, percent)
+
+	buf, err := processImage(bimg.NewImage(buf), bimg.Options{Width: scaled.Width, Height: scaled.Height, Force: true})
+	if err != nil {
+		return nil, bimg.ImageSize{}, err
+	}
+
+	return buf, scaled, nil
+}
+
+func scaledOverlaySize(size bimg.ImageSize, width int, percent float64) bimg.ImageSize {
+	scaledWidth := scaleDimension(width, percent/100)
+	return bimg.ImageSize{
+		Width:  scaledWidth,
+		Height: scaleDimension(size.Height, float64(scaledWidth)/float64(size.Width)),
+	}
+}
+
+// rotateOverlay rotates the overlay image clockwise. bimg only rotates by multiples of 90 degrees, so the pixels
+// are rotated directly, on a transparent image big enough to contain the rotated overlay
+func rotateOverlay(buf []byte, angle int) ([]byte, bimg.ImageSize, error) {
+	over, err := decodePixels(buf)
+	if err != nil {
+		return nil, bimg.ImageSize{}, err
+	}
+
+	rotated := rotatePixels(over, angle)
+
+	buf, err = encodePixels(rotated)
+	if err != nil {
+		return nil, bimg.ImageSize{}, err
+	}
+
+	return buf, bimg.ImageSize{Width: rotated.Rect.Dx(), Height: rotated.Rect.Dy()}, nil
+}
+
+// rotatePixels rotates the image clockwise around its center, taking for every pixel the closest one of the image
+func rotatePixels(img *image.NRGBA, angle int) *image.NRGBA {
+	radians := float64(angle) * math.Pi / 180
+	sin, cos := math.Sin(radians), math.Cos(radians)
+	width, height := float64(img.Rect.Dx()), float64(img.Rect.Dy())
+
+	//the bounding box of the rotated image, ignoring the rounding errors of the multiples of 90 degrees
+	boxWidth := int(math.Ceil(math.Abs(width*cos) + math.Abs(height*sin) - 1e-9))
+	boxHeight := int(math.Ceil(math.Abs(width*sin) + math.Abs(height*cos) - 1e-9))
+
+	rotated := image.NewNRGBA(image.Rect(0, 0, boxWidth, boxHeight))
+	for y := 0; y < boxHeight; y++ {
+		for x := 0; x < boxWidth; x++ {
+			dx := float64(x) + 0.5 - float64(boxWidth)/2
+			dy := float64(y) + 0.5 - float64(boxHeight)/2
+			sx := int(math.Floor(dx*cos + dy*sin + width/2))
+			sy := int(math.Floor(-dx*sin + dy*cos + height/2))
+			if sx >= 0 && sy >= 0 && sx < img.Rect.Dx() && sy < img.Rect.Dy() {
+				rotated.SetNRGBA(x, y, img.NRGBAAt(img.Rect.Min.X+sx, img.Rect.Min.Y+sy))
+			}
+		}
+	}
+
+	return rotated
+}
+
+// tileOverlay repeats the overlay image, starting from the top left corner, over a transparent image of the size
+func tileOverlay(buf []byte, size bimg.ImageSize) ([]byte, error) {
+	over, err := decodePixels(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	tiled := image.NewNRGBA(image.Rect(0, 0, size.Width, size.Height))
+	for y := 0; y < size.Height; y += over.Rect.Dy() {
+		for x := 0; x < size.Width; x += over.Rect.Dx() {
+			draw.Draw(tiled, over.Rect.Add(image.Pt(x, y)), over, image.ZP, draw.Src)
+		}
+	}
+
+	return encodePixels(tiled)
+}
+
+// loadOverlay returns the overlay image, reading it again only if its file was modified
+func (f *overlay) loadOverlay() (*cachedOverlay, error) {
+	var modTime time.Time
+	var fileSize int64
+
+	images := f.images
+	if images == nil {
+		images = &overlayImages
+	}
+
+	//the images fetched over HTTP and the inline ones never change
+	if !isURL(f.file) && !isDataURI(f.file) {
+		info, err := f.stat()
+		if err != nil {
+			return nil, err
+		}
+		modTime, fileSize = info.ModTime(), info.Size()
+	}
+
+	if cached, ok := images.Load(f.file); ok {
+		over := cached.(*cachedOverlay)
+		if over.modTime.Equal(modTime) && over.fileSize == fileSize {
+			return over, nil
+		}
+	}
+
+	buf, err := f.readImage()
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := bimg.NewImage(buf).Size()
+	if err != nil {
+		return nil, err
+	}
+
+	over := &cachedOverlay{buf: buf, size: size, modTime: modTime, fileSize: fileSize}
+	images.Store(f.file, over)
+	return over, nil
+}
+
+func (f *overlay) stat() (fs.FileInfo, error) {
+	if f.fsys == nil {
+		return os.Stat(f.file)
+	}
+	return fs.Stat(f.fsys, f.file)
+}
+
+// readImage reads the overlay image from the file system of the filter
+func (f *overlay) readImage() ([]byte, error) {
+	if f.fsys == nil || isURL(f.file) || isDataURI(f.file) {
+		return readImage(f.file)
+	}
+	return fs.ReadFile(f.fsys, f.file)
+}
+
+func isURL(file string) bool {
+	u, err := url.Parse(file)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+func isDataURI(file string) bool {
+	return strings.HasPrefix(file, dataURIPrefix)
+}
+
+// readImage reads the image from the file or, if it is an HTTP(S) URL, fetches it.
+// The image can also be inline, as a base64 data URI
 func readImage(file string) ([]byte, error) {
+	if isURL(file) {
+		return fetchImage(file)
+	}
+
+	if isDataURI(file) {
+		return decodeDataURI(file)
+	}
+
 	img, err := os.Open(file)
 	if err != nil {
 		return nil, err
@@ -165,17 +400,115 @@ func readImage(file string) ([]byte, error) {
 	return buf, nil
 }
 
+// decodeDataURI decodes the image of a base64 data URI, like data:image/png;base64,iVBORw0KGgo...
+func decodeDataURI(uri string) ([]byte, error) {
+	parts := strings.SplitN(strings.TrimPrefix(uri, dataURIPrefix), ",", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("malformed data URI, the data is missing")
+	}
+
+	meta := strings.Split(parts[0], ";")
+	if !strings.HasPrefix(meta[0], "image/") {
+		return nil, fmt.Errorf("malformed data URI, the media type %q is not an image", meta[0])
+	}
+
+	if meta[len(meta)-1] != "base64" {
+		return nil, errors.New("malformed data URI, only base64 encoded images are supported")
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed data URI, invalid base64 data: %v", err)
+	}
+
+	return buf, nil
+}
+
+// fetchCall is a download in progress, shared by the filters fetching the same image at the same time
+type fetchCall struct {
+	done chan struct{}
+	buf  []byte
+	err  error
+}
+
+// fetchImage downloads the image, which is kept in memory so it is fetched only once
+func fetchImage(url string) ([]byte, error) {
+	if buf, ok := fetchedImages.Load(url); ok {
+		return buf.([]byte), nil
+	}
+
+	call := &fetchCall{done: make(chan struct{})}
+	if running, loaded := fetchCalls.LoadOrStore(url, call); loaded {
+		call = running.(*fetchCall)
+		<-call.done
+		return call.buf, call.err
+	}
+
+	call.buf, call.err = downloadImage(url)
+	if call.err == nil {
+		fetchedImages.Store(url, call.buf)
+	}
+	fetchCalls.Delete(url)
+	close(call.done)
+
+	return call.buf, call.err
+}
+
+// downloadImage downloads the image, which is limited like the original images
+func downloadImage(url string) ([]byte, error) {
+	rsp, err := fetchClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the image %s, the server responded with %d", url, rsp.StatusCode)
+	}
+
+	if contentType := rsp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("failed to fetch the image %s, the content type %q is not an image", url, contentType)
+	}
+
+	buf, err := readBody(rsp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the image %s: %w", url, err)
+	}
+
+	return buf, nil
+}
+
 func (f *overlay) CreateFilter(args []interface{}) (filters.Filter, error) {
 	//imageOverlay(<filename>, <opacity>, <gravity>, <right_margin>, <left_margin>, <top_margin>, <bottom_margin>)
 	//imageOverlay("filename", 1.0, NE, 0, 0, 0, 0)
 	//imageOverlay("filename", 1.0, NE)
+	//imageOverlay("filename", 1.0, TILE)
+	//the scale, in percent of the width of the image, and the angle are optional after the gravity
+	//imageOverlay("filename", 1.0, NE, 10)
+	//imageOverlay("filename", 1.0, NE, 10, 45)
+	//the angle, or the scale and the angle, are optional after the margins
+	//imageOverlay("filename", 1.0, NE, 0, 0, 0, 0, 45)
+	//imageOverlay("filename", 1.0, NE, 0, 0, 0, 0, 10, 45)
+	//the blend mode is optional at the end
+	//imageOverlay("filename", 1.0, NE, "multiply")
 	var err error
 
-	if len(args) != 3 && len(args) != 7 {
-		return nil, filters.ErrInvalidFilterParameters
+	o := &overlay{fsys: f.fsys, images: f.images, blend: BlendOver}
+
+	if n := len(args); n > 3 {
+		//the margins can be strings too, like "5%", so only the strings which are not numbers are blend modes
+		if mode, ok := args[n-1].(string); ok && (blendModes[mode] || !isMargin(mode)) {
+			if !blendModes[mode] {
+				return nil, filters.ErrInvalidFilterParameters
+			}
+			o.blend = mode
+			args = args[:n-1]
+		}
 	}
 
-	o := &overlay{}
+	if len(args) < 3 || len(args) == 6 || len(args) > 9 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
 
 	o.file, err = parse.EskipStringArg(args[0])
 	if err != nil {
@@ -196,6 +529,47 @@ func (f *overlay) CreateFilter(args []interface{}) (filters.Filter, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	var scale, angle interface{}
+	switch len(args) {
+	case 4:
+		scale = args[3]
+	case 5:
+		scale, angle = args[3], args[4]
+	case 8:
+		angle = args[7]
+	case 9:
+		scale, angle = args[7], args[8]
+	}
+
+	//a scale of 0 keeps the size of the overlay
+	if scale != nil {
+		o.scalePercent, err = parse.EskipFloatArg(scale)
+		if err != nil {
+			return nil, err
+		}
+		if o.scalePercent < 0 || o.scalePercent > maxOverlayScale {
+			return nil, filters.ErrInvalidFilterParameters
+		}
+	}
+
+	if angle != nil {
+		o.angle, err = parse.EskipIntArg(angle)
+		if err != nil {
+			return nil, err
+		}
+		o.angle = int(normalizeAngle(o.angle))
+	}
+
+	//the tiles cover the whole image, so there are no margins
+	if gravity == Tile {
+		if len(args) > 5 {
+			return nil, filters.ErrInvalidFilterParameters
+		}
+		o.tile = true
+		return o, nil
+	}
+
 	if !gravityType[gravity] {
 		return nil, filters.ErrInvalidFilterParameters
 	}
@@ -203,26 +577,26 @@ func (f *overlay) CreateFilter(args []interface{}) (filters.Filter, error) {
 	o.verticalGravity = verticalGravity[gravity]
 	o.horizontalGravity = horizontalGravity[gravity]
 
-	if len(args) == 3 {
+	if len(args) < 7 {
 		return o, nil
 	}
 
-	o.topMargin, err = parse.EskipIntArg(args[3])
+	o.topMargin, o.topPercent, err = parseMargin(args[3])
 	if err != nil {
 		return nil, err
 	}
 
-	o.rightMargin, err = parse.EskipIntArg(args[4])
+	o.rightMargin, o.rightPercent, err = parseMargin(args[4])
 	if err != nil {
 		return nil, err
 	}
 
-	o.bottomMargin, err = parse.EskipIntArg(args[5])
+	o.bottomMargin, o.bottomPercent, err = parseMargin(args[5])
 	if err != nil {
 		return nil, err
 	}
 
-	o.leftMargin, err = parse.EskipIntArg(args[6])
+	o.leftMargin, o.leftPercent, err = parseMargin(args[6])
 	if err != nil {
 		return nil, err
 	}
@@ -234,5 +608,10 @@ func (f *overlay) CreateFilter(args []interface{}) (filters.Filter, error) {
 func (f *overlay) Request(ctx filters.FilterContext) {}
 
 func (f *overlay) Response(ctx filters.FilterContext) {
+	//bimg only composites the watermarks over the image, so the other blend modes are applied on the pixels
+	if f.blend != "" && f.blend != BlendOver {
+		HandlePixelResponse(ctx, (*overlayBlend)(f))
+		return
+	}
 	HandleImageResponse(ctx, f)
 }
@@ -0,0 +1,87 @@
+package filters
+
+import (
+	"image"
+
+	"github.com/h2non/bimg"
+)
+
+// Blend modes of the overlay image
+const (
+	BlendOver     = "over"
+	BlendMultiply = "multiply"
+	BlendScreen   = "screen"
+)
+
+var blendModes = map[string]bool{
+	BlendOver:     true,
+	BlendMultiply: true,
+	BlendScreen:   true,
+}
+
+// overlayBlend composites the overlay image on the pixels of the image with a blend mode bimg does not support
+type overlayBlend overlay
+
+func (f *overlayBlend) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	o := (*overlay)(f)
+
+	watermark, err := o.watermark(bimg.ImageSize{Width: img.Rect.Dx(), Height: img.Rect.Dy()})
+	if err != nil {
+		return nil, err
+	}
+
+	over, err := decodePixels(watermark.Buf)
+	if err != nil {
+		return nil, err
+	}
+
+	blendPixels(img, over, image.Pt(watermark.Left, watermark.Top), f.opacity, f.blend)
+	return img, nil
+}
+
+// blendPixels blends the overlay placed at the position with the image, the transparent parts of the overlay
+// and the opacity leaving the image as it is
+func blendPixels(img *image.NRGBA, over *image.NRGBA, at image.Point, opacity float64, mode string) {
+	area := over.Rect.Sub(over.Rect.Min).Add(at).Intersect(img.Rect)
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			o := over.NRGBAAt(over.Rect.Min.X+x-at.X, over.Rect.Min.Y+y-at.Y)
+			alpha := float64(o.A) / 255 * opacity
+			if alpha == 0 {
+				continue
+			}
+
+			i := img.PixOffset(x, y)
+			p := img.Pix[i : i+4 : i+4]
+			p[0] = blendChannel(p[0], o.R, alpha, mode)
+			p[1] = blendChannel(p[1], o.G, alpha, mode)
+			p[2] = blendChannel(p[2], o.B, alpha, mode)
+			p[3] = clampUint8(float64(p[3]) + (255-float64(p[3]))*alpha)
+		}
+	}
+}
+
+func blendChannel(base, over uint8, alpha float64, mode string) uint8 {
+	b, o := float64(base)/255, float64(over)/255
+
+	var blended float64
+	switch mode {
+	case BlendMultiply:
+		blended = b * o
+	case BlendScreen:
+		blended = b + o - b*o
+	default:
+		blended = o
+	}
+
+	return clampUint8(255 * (b*(1-alpha) + blended*alpha))
+}
+
+func (f *overlayBlend) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *overlayBlend) Merge(other PixelFilter) PixelFilter {
+	return f
+}
@@ -0,0 +1,88 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlendPixels_Multiply(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	over := uniformImage(color.NRGBA{R: 255, G: 0, B: 128, A: 255})
+
+	blendPixels(img, over, image.Pt(0, 0), 1, BlendMultiply)
+
+	assert.Equal(t, color.NRGBA{R: 128, G: 0, B: 64, A: 255}, img.NRGBAAt(0, 0))
+}
+
+func TestBlendPixels_Screen(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	over := uniformImage(color.NRGBA{R: 255, G: 0, B: 128, A: 255})
+
+	blendPixels(img, over, image.Pt(0, 0), 1, BlendScreen)
+
+	assert.Equal(t, color.NRGBA{R: 255, G: 128, B: 192, A: 255}, img.NRGBAAt(0, 0))
+}
+
+func TestBlendPixels_Over(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	over := uniformImage(color.NRGBA{R: 255, G: 0, B: 128, A: 255})
+
+	blendPixels(img, over, image.Pt(0, 0), 0.5, BlendOver)
+
+	assert.Equal(t, color.NRGBA{R: 192, G: 64, B: 128, A: 255}, img.NRGBAAt(0, 0))
+}
+
+func TestBlendPixels_Position(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	over := uniformImage(color.NRGBA{A: 255})
+
+	//the overlay is partly out of the image
+	blendPixels(img, over, image.Pt(2, 3), 1, BlendMultiply)
+
+	assert.Equal(t, color.NRGBA{R: 128, G: 128, B: 128, A: 255}, img.NRGBAAt(1, 3))
+	assert.Equal(t, color.NRGBA{R: 128, G: 128, B: 128, A: 255}, img.NRGBAAt(2, 2))
+	assert.Equal(t, color.NRGBA{A: 255}, img.NRGBAAt(2, 3))
+	assert.Equal(t, color.NRGBA{A: 255}, img.NRGBAAt(3, 3))
+}
+
+func TestBlendPixels_Transparent(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	over := uniformImage(color.NRGBA{R: 255})
+
+	blendPixels(img, over, image.Pt(0, 0), 1, BlendScreen)
+
+	assert.Equal(t, color.NRGBA{R: 128, G: 128, B: 128, A: 255}, img.NRGBAAt(0, 0))
+}
+
+func TestOverlayBlend_TransformPixels(t *testing.T) {
+	f, _ := NewOverlayImage().CreateFilter([]interface{}{"../images/star.png", 1.0, "NW", BlendMultiply})
+	o := f.(*overlay)
+	img := uniformImage(color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+
+	star, _ := readImage("../images/star.png")
+	starPixels, _ := decodePixels(star)
+
+	result, err := (*overlayBlend)(o).TransformPixels(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//multiplied by white, the star keeps its colors
+	expected := starPixels.NRGBAAt(0, 0)
+	assert.Equal(t, blendChannel(255, expected.R, float64(expected.A)/255, BlendMultiply), result.NRGBAAt(0, 0).R)
+}
+
+func TestOverlay_Response_Blend(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[hasMergedFilters] = false
+
+	f, _ := NewOverlayImage().CreateFilter([]interface{}{"../images/star.png", 1.0, "NW", BlendScreen})
+	f.Response(fc)
+
+	queue := fc.FStateBag[skropPixelFilters].([]PixelFilter)
+	assert.Len(t, queue, 1)
+	assert.IsType(t, &overlayBlend{}, queue[0])
+}
@@ -1,8 +1,23 @@
 package filters
 
 import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing/fstest"
+	"time"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+	"github.com/zalando-stups/skrop/parse"
 	"github.com/h2non/bimg"
 	"testing"
 )
@@ -105,6 +120,39 @@ func TestOverlay_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestOverlay_CanBeMerged_OrientationPending(t *testing.T) {
+	s := overlay{}
+	self := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Opacity: 1, Left: 10, Top: 20}}
+
+	assert.False(t, s.CanBeMerged(&bimg.Options{Rotate: 90}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Flip: true}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Flop: true}, self))
+}
+
+func TestOverlay_CanBeMerged_GeometryPending(t *testing.T) {
+	s := overlay{}
+	self := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Opacity: 1, Left: 10, Top: 20}}
+
+	assert.False(t, s.CanBeMerged(&bimg.Options{Zoom: 2}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Top: 10, Left: 10, AreaWidth: 50, AreaHeight: 50}, self))
+	assert.False(t, s.CanBeMerged(&bimg.Options{Trim: true, Threshold: 10}, self))
+}
+
+func TestOverlay_AfterRotate(t *testing.T) {
+	fc := createContext(t, "GET", "url", imagefiltertest.LandscapeImageFile, map[string]interface{}{})
+	buf, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+	size, _ := bimg.NewImage(buf).Size()
+
+	assert.Nil(t, HandleImageResponse(fc, &rotate{angle: 90}))
+	f, _ := NewOverlayImage().CreateFilter([]interface{}{imagefiltertest.PNGImageFile, 1.0, "SE"})
+	assert.Nil(t, HandleImageResponse(fc, f.(*overlay)))
+
+	//the overlay is placed on the rotated image, so its position is based on the rotated size
+	rotated, _ := fc.FStateBag[skropImage].(*bimg.Image).Size()
+	assert.Equal(t, bimg.ImageSize{Width: size.Height, Height: size.Width}, rotated)
+	assert.Equal(t, &bimg.Options{}, fc.FStateBag[skropOptions], "the overlay should not be merged with the rotation")
+}
+
 func TestOverlay_Merge(t *testing.T) {
 	s := overlay{}
 	self := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Opacity: 3.4, Left: 10, Top: 20}}
@@ -140,8 +188,8 @@ func TestOverlay_CreateFilter(t *testing.T) {
 		Args: []interface{}{"abc", "", "NE"},
 		Err:  true,
 	}, {
-		Msg:  "five args error",
-		Args: []interface{}{"abc", 2.6, "NE", 1.0, 2.0},
+		Msg:  "six args error",
+		Args: []interface{}{"abc", 2.6, "NE", 1.0, 2.0, 3.0},
 		Err:  true,
 	}, {
 		Msg:  "seven args",
@@ -163,9 +211,543 @@ func TestOverlay_CreateFilter(t *testing.T) {
 		Msg:  "wrong type args",
 		Args: []interface{}{"abc", 2.6, "NE", 1.0, 2.0, 3.0, ""},
 		Err:  true,
+	}, {
+		Msg:  "tile",
+		Args: []interface{}{"abc", 0.5, "TILE"},
+		Err:  false,
+	}, {
+		Msg:  "tile with margins",
+		Args: []interface{}{"abc", 0.5, "TILE", 1.0, 2.0, 3.0, 4.0},
+		Err:  true,
+	}, {
+		Msg:  "percentage margins",
+		Args: []interface{}{"abc", 0.5, "SE", "5%", "5%", "5%", "5%"},
+		Err:  false,
+	}, {
+		Msg:  "pixels and percentage margins",
+		Args: []interface{}{"abc", 0.5, "SE", 10.0, "5%", 0.0, "2.5%"},
+		Err:  false,
+	}, {
+		Msg:  "percentage margin over 100",
+		Args: []interface{}{"abc", 0.5, "SE", "100%", "5%", "5%", "5%"},
+		Err:  true,
+	}, {
+		Msg:  "margin without percent sign",
+		Args: []interface{}{"abc", 0.5, "SE", "5", "5%", "5%", "5%"},
+		Err:  true,
+	}, {
+		Msg:  "last margin without percent sign",
+		Args: []interface{}{"abc", 0.5, "SE", 0.0, 0.0, 0.0, "10"},
+		Err:  true,
+	}, {
+		Msg:  "scale",
+		Args: []interface{}{"abc", 0.5, "SE", 10.0},
+		Err:  false,
+	}, {
+		Msg:  "margins and angle",
+		Args: []interface{}{"abc", 0.5, "SE", 1.0, 2.0, 3.0, 4.0, 45.0},
+		Err:  false,
+	}, {
+		Msg:  "scale and angle",
+		Args: []interface{}{"abc", 0.5, "SE", 10.0, 45.0},
+		Err:  false,
+	}, {
+		Msg:  "tile, scale and angle",
+		Args: []interface{}{"abc", 0.5, "TILE", 10.0, 45.0},
+		Err:  false,
+	}, {
+		Msg:  "tile and scale",
+		Args: []interface{}{"abc", 0.5, "TILE", 10.0},
+		Err:  false,
+	}, {
+		Msg:  "zero scale",
+		Args: []interface{}{"abc", 0.5, "SE", 0.0},
+		Err:  false,
+	}, {
+		Msg:  "negative scale",
+		Args: []interface{}{"abc", 0.5, "SE", -10.0},
+		Err:  true,
+
+	}, {
+		Msg:  "margins, scale and angle",
+		Args: []interface{}{"abc", 0.5, "SE", 1.0, 2.0, 3.0, 4.0, 10.0, 45.0},
+		Err:  false,
+	}, {
+		Msg:  "angle not integer",
+		Args: []interface{}{"abc", 0.5, "SE", 1.0, 2.0, 3.0, 4.0, 10.0, 45.5},
+		Err:  true,
+	}, {
+		Msg:  "short form angle not integer",
+		Args: []interface{}{"abc", 0.5, "SE", 10.0, 45.5},
+		Err:  true,
+
+	}, {
+		Msg:  "more than 9 args",
+		Args: []interface{}{"abc", 0.5, "SE", 1.0, 2.0, 3.0, 4.0, 10.0, 45.0, 1.0},
+		Err:  true,
+	}, {
+		Msg:  "scale over 100",
+		Args: []interface{}{"abc", 0.5, "SE", 150.0},
+		Err:  true,
+	}, {
+		Msg:  "blend mode",
+		Args: []interface{}{"abc", 0.5, "SE", "multiply"},
+		Err:  false,
+	}, {
+		Msg:  "margins and blend mode",
+		Args: []interface{}{"abc", 0.5, "SE", 1.0, 2.0, 3.0, 4.0, "screen"},
+		Err:  false,
+	}, {
+		Msg:  "margins, scale, angle and blend mode",
+		Args: []interface{}{"abc", 0.5, "SE", 1.0, 2.0, 3.0, 4.0, 10.0, 45.0, "over"},
+		Err:  false,
+	}, {
+		Msg:  "invalid blend mode",
+		Args: []interface{}{"abc", 0.5, "SE", "overlay"},
+		Err:  true,
 	}, {
 		Msg:  "gravity error",
 		Args: []interface{}{"abc", 2.6, "NA", 1.0, 2.0, 3.0, 4.0},
 		Err:  true,
 	}})
 }
+
+func TestOverlay_CreateFilter_StringMarginIsNotABlendMode(t *testing.T) {
+	_, err := NewOverlayImage().CreateFilter([]interface{}{"../images/star.png", 1.0, "SE", 0.0, 0.0, 0.0, "10"})
+
+	//the margin is rejected as a margin, instead of as an unknown blend mode
+	var argErr *parse.ArgError
+	if assert.True(t, errors.As(err, &argErr)) {
+		assert.Equal(t, "10", argErr.Got)
+	}
+}
+
+func TestReadImage_URL(t *testing.T) {
+	overArr, _ := readImage("../images/star.png")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(overArr)
+	}))
+	defer server.Close()
+
+	buf, err := readImage(server.URL + "/star.png")
+	assert.Nil(t, err)
+	assert.Equal(t, overArr, buf)
+
+	//the second time the image is not fetched again
+	buf, err = readImage(server.URL + "/star.png")
+	assert.Nil(t, err)
+	assert.Equal(t, overArr, buf)
+	assert.Equal(t, 1, requests)
+}
+
+func TestReadImage_URLConcurrent(t *testing.T) {
+	overArr, _ := readImage("../images/star.png")
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(overArr)
+	}))
+	defer server.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf, err := readImage(server.URL + "/star.png")
+			assert.Nil(t, err)
+			assert.Equal(t, overArr, buf)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "the image should be fetched once")
+}
+
+func TestReadImage_URLTooLarge(t *testing.T) {
+	overArr, _ := readImage("../images/star.png")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		//without the length, the body is limited while it is read
+		w.(http.Flusher).Flush()
+		w.Write(overArr)
+	}))
+	defer server.Close()
+
+	SetMaxInputBytes(int64(len(overArr) - 1))
+	defer SetMaxInputBytes(0)
+
+	_, err := readImage(server.URL + "/star.png")
+	assert.True(t, errors.Is(err, ErrImageTooLarge))
+
+	_, cached := fetchedImages.Load(server.URL + "/star.png")
+	assert.False(t, cached, "the failed download should not be kept")
+}
+
+func TestReadImage_URLNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := readImage(server.URL + "/star.png")
+	assert.NotNil(t, err)
+}
+
+func TestReadImage_URLNotImage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	_, err := readImage(server.URL + "/star.png")
+	assert.NotNil(t, err)
+}
+
+func TestReadImage_File(t *testing.T) {
+	_, err := readImage("../images/star.png")
+	assert.Nil(t, err)
+
+	_, err = readImage("../images/nonExisting.png")
+	assert.NotNil(t, err)
+}
+
+// redPNG is a 2x2 red PNG, base64 encoded
+const redPNG = "iVBORw0KGgoAAAANSUhEUgAAAAIAAAACCAIAAAD91JpzAAAAG0lEQVR4nAAOAPH/BP8AAAAAAAIAAAAAAAADAA1HAQZvRqE8AAAAAElFTkSuQmCC"
+
+func TestReadImage_DataURI(t *testing.T) {
+	buf, err := readImage("data:image/png;base64," + redPNG)
+	assert.Nil(t, err)
+
+	pixels, err := decodePixels(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 2, 2), pixels.Rect)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, pixels.NRGBAAt(1, 1))
+}
+
+func TestReadImage_DataURIMalformed(t *testing.T) {
+	for _, uri := range []string{
+		"data:image/png;base64",
+		"data:text/plain;base64," + redPNG,
+		"data:," + redPNG,
+		"data:image/png," + redPNG,
+		"data:image/png;base64,not base64!",
+	} {
+		_, err := readImage(uri)
+		assert.NotNil(t, err, uri)
+	}
+}
+
+func TestOverlay_DataURIWithFS(t *testing.T) {
+	f, _ := NewOverlayImageFromFS(fstest.MapFS{}).CreateFilter([]interface{}{"data:image/png;base64," + redPNG, 1.0, "NW"})
+
+	over, err := f.(*overlay).loadOverlay()
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.ImageSize{Width: 2, Height: 2}, over.size)
+}
+
+func TestLoadOverlay_ModifiedFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "overlay.png")
+	small, _ := encodePixels(image.NewNRGBA(image.Rect(0, 0, 10, 10)))
+	ioutil.WriteFile(file, small, 0644)
+
+	o := &overlay{file: file}
+	over, err := o.loadOverlay()
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.ImageSize{Width: 10, Height: 10}, over.size)
+
+	//the cached image is used while the file is not modified
+	cached, _ := o.loadOverlay()
+	assert.True(t, over == cached)
+
+	big, _ := encodePixels(image.NewNRGBA(image.Rect(0, 0, 20, 20)))
+	ioutil.WriteFile(file, big, 0644)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(file, later, later)
+
+	over, err = o.loadOverlay()
+	assert.Nil(t, err)
+	assert.Equal(t, bimg.ImageSize{Width: 20, Height: 20}, over.size)
+	assert.Equal(t, big, over.buf)
+}
+
+func BenchmarkOverlay_CreateOptions(b *testing.B) {
+	image := imagefiltertest.LandscapeImage()
+	overlay := &overlay{file: "../images/star.png",
+		opacity:           0.9,
+		horizontalGravity: bimg.GravityEast,
+		verticalGravity:   bimg.GravitySouth,
+	}
+	parameters := buildParameters(nil, image)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		overlay.CreateOptions(parameters)
+	}
+}
+
+func TestOverlay_CreateOptions_Tile(t *testing.T) {
+	overArr, _ := readImage("../images/star.png")
+	star, _ := decodePixels(overArr)
+	w, h := star.Rect.Dx(), star.Rect.Dy()
+
+	base := detailedImage(3*w-1, 3*h-1, 0)
+	o := &overlay{file: "../images/star.png", opacity: 0.5, tile: true}
+
+	options, err := o.CreateOptions(buildParameters(nil, base))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, float32(0.5), options.WatermarkImage.Opacity)
+	assert.Zero(t, options.WatermarkImage.Left)
+	assert.Zero(t, options.WatermarkImage.Top)
+
+	tiled, err := decodePixels(options.WatermarkImage.Buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3*w-1, tiled.Rect.Dx())
+	assert.Equal(t, 3*h-1, tiled.Rect.Dy())
+
+	//the star is repeated on every position of the grid, the last ones being cut
+	x, y := w/2, h/2
+	for _, tile := range []image.Point{{0, 0}, {w, 0}, {0, h}, {w, h}, {2 * w, 2 * h}} {
+		assert.Equal(t, star.NRGBAAt(x, y), tiled.NRGBAAt(tile.X+x, tile.Y+y), "tile at %v", tile)
+	}
+}
+
+func TestScaledOverlaySize(t *testing.T) {
+	size := bimg.ImageSize{Width: 40, Height: 20}
+
+	assert.Equal(t, bimg.ImageSize{Width: 10, Height: 5}, scaledOverlaySize(size, 100, 10))
+	assert.Equal(t, bimg.ImageSize{Width: 100, Height: 50}, scaledOverlaySize(size, 1000, 10))
+}
+
+func TestOverlay_CreateOptions_Scale(t *testing.T) {
+	for _, width := range []int{200, 1000} {
+		o := &overlay{file: "../images/star.png", opacity: 1, scalePercent: 10,
+			horizontalGravity: bimg.GravityEast, verticalGravity: bimg.GravitySouth}
+
+		options, err := o.CreateOptions(buildParameters(nil, detailedImage(width, width, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overSize, _ := bimg.NewImage(options.WatermarkImage.Buf).Size()
+		assert.Equal(t, width/10, overSize.Width)
+		assert.Equal(t, width-overSize.Width, options.WatermarkImage.Left)
+		assert.Equal(t, width-overSize.Height, options.WatermarkImage.Top)
+	}
+}
+
+func TestOverlay_CreateOptions_PercentageMargins(t *testing.T) {
+	overArr, _ := readImage("../images/star.png")
+	overSize, _ := bimg.NewImage(overArr).Size()
+
+	f, _ := NewOverlayImage().CreateFilter([]interface{}{"../images/star.png", 1.0, "SE", "5%", "5%", "5%", 20.0})
+	options, _ := f.(*overlay).CreateOptions(buildParameters(nil, detailedImage(1000, 500, 0)))
+
+	assert.Equal(t, 1000-50-overSize.Width, options.WatermarkImage.Left)
+	assert.Equal(t, 500-25-overSize.Height, options.WatermarkImage.Top)
+
+	f, _ = NewOverlayImage().CreateFilter([]interface{}{"../images/star.png", 1.0, "NW", "5%", 0.0, 0.0, "5%"})
+	options, _ = f.(*overlay).CreateOptions(buildParameters(nil, detailedImage(1000, 1000, 0)))
+
+	assert.Equal(t, 50, options.WatermarkImage.Left)
+	assert.Equal(t, 50, options.WatermarkImage.Top)
+}
+
+func TestMarginPixels(t *testing.T) {
+	assert.Equal(t, 50, marginPixels(0, 5, 1000))
+	assert.Equal(t, 20, marginPixels(20, 0, 1000))
+}
+
+func TestRotatePixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+
+	rotated := rotatePixels(img, 90)
+	assert.Equal(t, 10, rotated.Rect.Dx())
+	assert.Equal(t, 20, rotated.Rect.Dy())
+	//the top left corner goes to the top right one
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, rotated.NRGBAAt(9, 0))
+
+	rotated = rotatePixels(img, 180)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, rotated.NRGBAAt(19, 9))
+
+	rotated = rotatePixels(image.NewNRGBA(image.Rect(0, 0, 10, 10)), 45)
+	assert.Equal(t, 15, rotated.Rect.Dx())
+	assert.Equal(t, 15, rotated.Rect.Dy())
+}
+
+func TestOverlay_CreateOptions_Rotated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "overlay.png")
+	buf, _ := encodePixels(image.NewNRGBA(image.Rect(0, 0, 20, 10)))
+	ioutil.WriteFile(file, buf, 0644)
+
+	o := &overlay{file: file, opacity: 1, angle: 90,
+		horizontalGravity: bimg.GravityEast, verticalGravity: bimg.GravitySouth}
+	options, err := o.CreateOptions(buildParameters(nil, detailedImage(100, 100, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the rotated overlay is 10 pixels wide and 20 pixels high
+	assert.Equal(t, 90, options.WatermarkImage.Left)
+	assert.Equal(t, 80, options.WatermarkImage.Top)
+}
+
+func TestOverlay_CreateFilter_Angle(t *testing.T) {
+	f, err := NewOverlayImage().CreateFilter([]interface{}{"abc", 1.0, "NE", 10.0, 45.0})
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, f.(*overlay).scalePercent)
+	assert.Equal(t, 45, f.(*overlay).angle)
+
+	f, err = NewOverlayImage().CreateFilter([]interface{}{"abc", 1.0, "NE", 1.0, 2.0, 3.0, 4.0, -90.0})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, f.(*overlay).scalePercent)
+	assert.Equal(t, 270, f.(*overlay).angle)
+	assert.Equal(t, 4, f.(*overlay).leftMargin)
+}
+
+func TestOverlay_CreateOptions_Margins(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "overlay.png")
+	buf, _ := encodePixels(image.NewNRGBA(image.Rect(0, 0, 20, 10)))
+	ioutil.WriteFile(file, buf, 0644)
+	base := buildParameters(nil, detailedImage(100, 80, 0))
+
+	for _, tt := range []struct {
+		gravity string
+		margins []interface{}
+		left    int
+		top     int
+	}{
+		{NE, []interface{}{4.0, 6.0, 8.0, 2.0}, 74, 4},
+		{NC, []interface{}{4.0, 6.0, 8.0, 2.0}, 36, 4},
+		{NW, []interface{}{4.0, 6.0, 8.0, 2.0}, 2, 4},
+		{CE, []interface{}{4.0, 6.0, 8.0, 2.0}, 74, 31},
+		{CC, []interface{}{4.0, 6.0, 8.0, 2.0}, 36, 31},
+		{CW, []interface{}{4.0, 6.0, 8.0, 2.0}, 2, 31},
+		{SE, []interface{}{4.0, 6.0, 8.0, 2.0}, 74, 62},
+		{SC, []interface{}{4.0, 6.0, 8.0, 2.0}, 36, 62},
+		{SW, []interface{}{4.0, 6.0, 8.0, 2.0}, 2, 62},
+		//a single margin shifts the centered overlay by exactly its size
+		{CC, []interface{}{10.0, 0.0, 0.0, 0.0}, 40, 45},
+		{CC, []interface{}{0.0, 0.0, 0.0, 10.0}, 50, 35},
+		{NC, []interface{}{0.0, 10.0, 0.0, 0.0}, 30, 0},
+		{CW, []interface{}{0.0, 0.0, 10.0, 0.0}, 0, 25},
+	} {
+		args := append([]interface{}{file, 1.0, tt.gravity}, tt.margins...)
+		f, err := NewOverlayImage().CreateFilter(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		options, err := f.(*overlay).CreateOptions(base)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, tt.left, options.WatermarkImage.Left, "left of %s with margins %v", tt.gravity, tt.margins)
+		assert.Equal(t, tt.top, options.WatermarkImage.Top, "top of %s with margins %v", tt.gravity, tt.margins)
+	}
+}
+
+func TestOverlay_CanBeMerged_DifferentOverlay(t *testing.T) {
+	s := overlay{}
+	opt := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte{1, 2}, Opacity: 1, Left: 10, Top: 20}}
+	self := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte{3, 4}, Opacity: 1, Left: 10, Top: 20}}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestOverlay_StackedOverlays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overlay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	red := filepath.Join(dir, "red.png")
+	buf, _ := encodePixels(uniformImage(color.NRGBA{R: 255, A: 255}))
+	ioutil.WriteFile(red, buf, 0644)
+
+	blue := filepath.Join(dir, "blue.png")
+	buf, _ = encodePixels(uniformImage(color.NRGBA{B: 255, A: 255}))
+	ioutil.WriteFile(blue, buf, 0644)
+
+	white := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	draw.Draw(white, white.Rect, image.NewUniform(color.White), image.ZP, draw.Src)
+	buf, _ = encodePixels(white)
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(buf)
+	fc.FStateBag[hasMergedFilters] = false
+
+	f, _ := NewOverlayImage().CreateFilter([]interface{}{red, 1.0, NW})
+	f.Response(fc)
+	f, _ = NewOverlayImage().CreateFilter([]interface{}{blue, 1.0, SE})
+	f.Response(fc)
+	FinalizeResponse(fc)
+
+	result, err := decodePixels(readResultImage(fc.Response().Body, t).Image())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, result.NRGBAAt(1, 1))
+	assert.Equal(t, color.NRGBA{B: 255, A: 255}, result.NRGBAAt(38, 38))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, result.NRGBAAt(20, 20))
+}
+
+func TestOverlayImageFromFS(t *testing.T) {
+	buf, _ := encodePixels(image.NewNRGBA(image.Rect(0, 0, 4, 2)))
+	fsys := fstest.MapFS{"watermarks/wm.png": &fstest.MapFile{Data: buf}}
+
+	f, err := NewOverlayImageFromFS(fsys).CreateFilter([]interface{}{"watermarks/wm.png", 1.0, SE})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := f.(*overlay).CreateOptions(buildParameters(nil, detailedImage(20, 10, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, buf, options.WatermarkImage.Buf)
+	assert.Equal(t, 16, options.WatermarkImage.Left)
+	assert.Equal(t, 8, options.WatermarkImage.Top)
+}
+
+func TestOverlayImageFromFS_NotExisting(t *testing.T) {
+	fsys := fstest.MapFS{}
+
+	//the file exists on the disk, but not in the file system of the filter
+	f, _ := NewOverlayImageFromFS(fsys).CreateFilter([]interface{}{"../images/star.png", 1.0, SE})
+	_, err := f.(*overlay).CreateOptions(buildParameters(nil, detailedImage(20, 10, 0)))
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,141 @@
+package filters
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"strings"
+
+	"github.com/h2non/bimg"
+	"github.com/zalando/skipper/filters"
+)
+
+// Pipeline describes the transformations planned for a route of filters
+type Pipeline struct {
+	// Options are the options of the last transformation, which encodes the image
+	Options *bimg.Options
+	// Passes describe the decode/encode passes of the image, in the order they are applied
+	Passes []string
+}
+
+// DescribePipeline plans the transformations of an image of the given size for the filters, given in the order of
+// the route, without fetching or processing any image. The options of the filters are created for a blank image of
+// that size, so the filters depending on its content or on the size of the previous transformations may differ
+// when the route is served. The filters which do not transform the image, like finalizeResponse, are ignored
+func DescribePipeline(route []filters.Filter, width int, height int) (*Pipeline, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("the size of the image needs to be positive, got %dx%d", width, height)
+	}
+
+	buf, err := encodePixels(image.NewNRGBA(image.Rect(0, 0, width, height)))
+	if err != nil {
+		return nil, err
+	}
+	blank := &ImageFilterContext{Image: bimg.NewImage(buf)}
+
+	p := &Pipeline{}
+	merged, hasMerged := &bimg.Options{}, false
+	var queue []PixelFilter
+
+	transform := func(options *bimg.Options) {
+		p.Options = options
+		p.Passes = append(p.Passes, "transform the image with "+describeOptions(options))
+	}
+
+	//as in applyPixelFilters, the options merged so far are applied before decoding the pixels
+	applyQueue := func() {
+		if len(queue) == 0 {
+			return
+		}
+		if hasMerged {
+			transform(merged)
+		}
+		names := make([]string, len(queue))
+		for i, f := range queue {
+			names[i] = fmt.Sprintf("%T", f)
+		}
+		p.Passes = append(p.Passes, "transform the pixels with "+strings.Join(names, ", "))
+		merged, hasMerged, queue = outputOptions(merged), true, nil
+	}
+
+	//the responses are processed in the reverse order of the route
+	for i := len(route) - 1; i >= 0; i-- {
+		for _, f := range responseFilters(route[i]) {
+			switch f := f.(type) {
+			case PixelFilter:
+				queue = queuePixelFilter(queue, f)
+			case ImageFilter:
+				applyQueue()
+
+				options, err := f.CreateOptions(blank)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create the options of %T: %v", f, err)
+				}
+
+				if next, ok := mergeFilter(f, merged, options); ok {
+					merged, hasMerged = next, true
+					continue
+				}
+
+				if hasMerged {
+					transform(merged)
+				}
+				transform(options)
+				merged, hasMerged = &bimg.Options{}, false
+			}
+		}
+	}
+
+	//the last transformation is triggered by finalizeResponse
+	applyQueue()
+	if hasMerged {
+		transform(merged)
+	}
+
+	return p, nil
+}
+
+// responseFilters returns the image and pixel filters handled by the response of the filter
+func responseFilters(f filters.Filter) []interface{} {
+	switch f := f.(type) {
+	case *embed:
+		return []interface{}{f, (*embedCanvas)(f)}
+	case *overlay:
+		if f.blend != "" && f.blend != BlendOver {
+			return []interface{}{(*overlayBlend)(f)}
+		}
+		return []interface{}{f}
+	case ImageFilter, PixelFilter:
+		return []interface{}{f}
+	default:
+		return nil
+	}
+}
+
+// describeOptions lists the options which are set, without the content of the buffers and of the nested options
+func describeOptions(o *bimg.Options) string {
+	var fields []string
+
+	value := reflect.ValueOf(*o)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.IsZero() {
+			continue
+		}
+
+		name := value.Type().Field(i).Name
+		switch {
+		case name == "Type":
+			fields = append(fields, name+"="+bimg.ImageTypeName(o.Type))
+		case field.Kind() == reflect.Struct || field.Kind() == reflect.Slice:
+			fields = append(fields, name)
+		default:
+			fields = append(fields, fmt.Sprintf("%s=%v", name, field.Interface()))
+		}
+	}
+
+	if len(fields) == 0 {
+		return "no options"
+	}
+	return strings.Join(fields, ", ")
+}
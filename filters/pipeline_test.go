@@ -0,0 +1,74 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/skipper/filters"
+)
+
+func createRoute(t testing.TB, specs ...interface{}) []filters.Filter {
+	var route []filters.Filter
+	for i := 0; i < len(specs); i += 2 {
+		f, err := specs[i].(filters.Spec).CreateFilter(specs[i+1].([]interface{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		route = append(route, f)
+	}
+	return route
+}
+
+func TestDescribePipeline_CropOverlayQuality(t *testing.T) {
+	route := createRoute(t,
+		NewCrop(), []interface{}{400.0, 300.0, North},
+		NewOverlayImage(), []interface{}{"../images/star.png", 1.0, "NW"},
+		NewQuality(), []interface{}{80.0},
+		NewFinalizeResponse(), []interface{}{})
+
+	p, err := DescribePipeline(route, 800, 600)
+
+	assert.Nil(t, err)
+	assert.Len(t, p.Passes, 1, "the crop, the overlay and the quality should be merged in a single pass")
+	assert.Equal(t, 400, p.Options.Width)
+	assert.Equal(t, 300, p.Options.Height)
+	assert.True(t, p.Options.Crop)
+	assert.Equal(t, 80, p.Options.Quality)
+	assert.NotEmpty(t, p.Options.WatermarkImage.Buf)
+}
+
+func TestDescribePipeline_Passes(t *testing.T) {
+	route := createRoute(t,
+		NewOverlayImage(), []interface{}{"../images/star.png", 1.0, "NW"},
+		NewBrightness(), []interface{}{1.2},
+		NewResize(), []interface{}{400.0, 300.0})
+
+	p, err := DescribePipeline(route, 800, 600)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"transform the image with Width=400",
+		"transform the pixels with *filters.brightness",
+		"transform the image with WatermarkImage",
+	}, p.Passes)
+}
+
+func TestDescribePipeline_NoTransformation(t *testing.T) {
+	p, err := DescribePipeline(createRoute(t, NewFinalizeResponse(), []interface{}{}), 800, 600)
+
+	assert.Nil(t, err)
+	assert.Empty(t, p.Passes)
+	assert.Nil(t, p.Options)
+}
+
+func TestDescribePipeline_InvalidSize(t *testing.T) {
+	_, err := DescribePipeline(nil, 0, 600)
+	assert.NotNil(t, err)
+}
+
+func TestDescribeOptions(t *testing.T) {
+	assert.Equal(t, "no options", describeOptions(&bimg.Options{}))
+	assert.Equal(t, "Width=10, Crop=true, Type=webp",
+		describeOptions(&bimg.Options{Width: 10, Crop: true, Type: bimg.WEBP}))
+}
@@ -0,0 +1,98 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// PixelateName is the name of the filter
+const PixelateName = "pixelate"
+
+type pixelate struct {
+	blockSize int
+}
+
+// NewPixelate creates a new filter of this type
+func NewPixelate() filters.Spec {
+	return &pixelate{}
+}
+
+func (f *pixelate) Name() string {
+	return PixelateName
+}
+
+func (f *pixelate) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for pixelate ", f)
+
+	//every block gets the average color of its pixels, the blocks on the right and bottom edges can be smaller
+	bounds := img.Bounds()
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += f.blockSize {
+		for left := bounds.Min.X; left < bounds.Max.X; left += f.blockSize {
+			block := image.Rect(left, top, left+f.blockSize, top+f.blockSize).Intersect(bounds)
+			fillAverage(img, block)
+		}
+	}
+
+	return img, nil
+}
+
+// fillAverage fills the rectangle with the average color of its pixels
+func fillAverage(img *image.NRGBA, r image.Rectangle) {
+	var sum [4]int
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)]
+		for i := range row {
+			sum[i%4] += int(row[i])
+		}
+	}
+
+	count := r.Dx() * r.Dy()
+	var average [4]uint8
+	for i := range average {
+		average[i] = uint8((sum[i] + count/2) / count)
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(r.Min.X, y):img.PixOffset(r.Max.X, y)]
+		for i := range row {
+			row[i] = average[i%4]
+		}
+	}
+}
+
+func (f *pixelate) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *pixelate) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *pixelate) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	p := &pixelate{}
+
+	p.blockSize, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if p.blockSize <= 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return p, nil
+}
+
+func (f *pixelate) Request(ctx filters.FilterContext) {}
+
+func (f *pixelate) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
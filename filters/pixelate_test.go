@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewPixelate(t *testing.T) {
+	name := NewPixelate().Name()
+	assert.Equal(t, "pixelate", name)
+}
+
+func TestPixelate_Name(t *testing.T) {
+	c := pixelate{}
+	assert.Equal(t, "pixelate", c.Name())
+}
+
+func TestPixelate_TransformPixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 5; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(10 * x), G: uint8(10 * y), B: 100, A: 255})
+		}
+	}
+
+	result, err := (&pixelate{blockSize: 2}).TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 5, 4), result.Bounds(), "the size should not change")
+
+	//the blocks are uniform, with the average color of their pixels
+	for _, block := range []struct {
+		r     image.Rectangle
+		color color.NRGBA
+	}{
+		{image.Rect(0, 0, 2, 2), color.NRGBA{R: 5, G: 5, B: 100, A: 255}},
+		{image.Rect(2, 2, 4, 4), color.NRGBA{R: 25, G: 25, B: 100, A: 255}},
+		{image.Rect(4, 0, 5, 2), color.NRGBA{R: 40, G: 5, B: 100, A: 255}},
+	} {
+		for y := block.r.Min.Y; y < block.r.Max.Y; y++ {
+			for x := block.r.Min.X; x < block.r.Max.X; x++ {
+				assert.Equal(t, block.color, result.NRGBAAt(x, y), "pixel %d,%d", x, y)
+			}
+		}
+	}
+}
+
+func TestPixelate_CanBeMerged(t *testing.T) {
+	f := pixelate{blockSize: 4}
+	assert.False(t, f.CanBeMerged(&pixelate{blockSize: 4}))
+}
+
+func TestPixelate_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewPixelate, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{8.0},
+		Err:  false,
+	}, {
+		Msg:  "block of one pixel",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "not an int",
+		Args: []interface{}{2.5},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{8.0, 8.0},
+		Err:  true,
+	}})
+}
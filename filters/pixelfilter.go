@@ -0,0 +1,257 @@
+package filters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"time"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+const skropPixelFilters = "skPixelFilters"
+
+// PixelFilter defines what a filter should implement when its transformation is not supported by bimg.
+// The transformation is applied directly on the pixels of the image, after the options merged so far.
+type PixelFilter interface {
+	TransformPixels(img *image.NRGBA) (*image.NRGBA, error)
+	CanBeMerged(other PixelFilter) bool
+	Merge(other PixelFilter) PixelFilter
+}
+
+// alphaPixelFilter is implemented by the pixel filters which make the image transparent,
+// so that the image is kept in a format supporting transparency
+type alphaPixelFilter interface {
+	needsAlpha() bool
+}
+
+// HandlePixelResponse should be called by the Response of every pixel filter. It queues the filter, so that all
+// the consecutive pixel filters are applied on the image in a single decode/encode pass
+func HandlePixelResponse(ctx filters.FilterContext, f PixelFilter) error {
+	start := time.Now()
+	statusSkipped := processingStopped(ctx)
+
+	err := handlePixelResponse(ctx, f)
+
+	name := filterName(f)
+	duration := time.Since(start)
+	metrics.ObserveDuration(name, duration)
+	if err != nil {
+		metrics.IncErrors(name, errorKind(err, statusSkipped))
+	}
+	logFilter(ctx, name, duration, err)
+	return err
+}
+
+func handlePixelResponse(ctx filters.FilterContext, f PixelFilter) error {
+
+	log.Debug("Handle Pixel Response")
+
+	//in case the response had an error from the backend or from a previous filter
+	if processingStopped(ctx) {
+		return fmt.Errorf("processing skipped, as the backend/filter reported %d status code", ctx.Response().StatusCode)
+	}
+
+	initOnce(ctx)
+
+	//the error response is already served when the image cannot be read
+	if processingStopped(ctx) {
+		return fmt.Errorf("processing failed, the image could not be read: %d status code", ctx.Response().StatusCode)
+	}
+
+	if ctx.StateBag()[skropAnimated] == true {
+		return ErrAnimatedImage
+	}
+
+	if ctx.StateBag()[skropNotImage] == true {
+		return ErrNotAnImage
+	}
+
+	if ctx.StateBag()[skropSkipped] == true {
+		log.Debug("Pixel filter ", f, " skipped, the image is smaller than the minimum dimensions")
+		return nil
+	}
+
+	if err := checkCanceled(ctx); err != nil {
+		log.Error("Failed to process image ", err.Error())
+		serveError(ctx, errorResponseFor(err))
+		return err
+	}
+
+	if _, ok := ctx.StateBag()[skropImage].(*bimg.Image); !ok {
+		log.Error("context state bag does not contains the key ", skropImage)
+		serveError(ctx, errorResponse())
+		return errors.New("processing failed, image not exists in the state bag")
+	}
+
+	queue, _ := ctx.StateBag()[skropPixelFilters].([]PixelFilter)
+
+	queue = queuePixelFilter(queue, f)
+	log.Debug("Pixel filter ", f, " queued in ", queue)
+
+	ctx.StateBag()[skropPixelFilters] = queue
+	return nil
+}
+
+// applyPixelFilters applies the options merged so far and then the queued pixel filters on the image.
+// The result is kept as PNG and the original type is restored by the next transformation of the image.
+func applyPixelFilters(ctx filters.FilterContext) error {
+	queue, _ := ctx.StateBag()[skropPixelFilters].([]PixelFilter)
+	if len(queue) == 0 {
+		return nil
+	}
+
+	image := ctx.StateBag()[skropImage].(*bimg.Image)
+	opts := ctx.StateBag()[skropOptions].(*bimg.Options)
+
+	output := outputOptions(opts)
+	if output.Type == bimg.UNKNOWN {
+		output.Type = bimg.DetermineImageType(image.Image())
+	}
+
+	buf := image.Image()
+	if ctx.StateBag()[hasMergedFilters] == true {
+		countPass(ctx)
+		opts.Type = bimg.PNG
+		keepProfile(ctx, opts)
+		var err error
+		buf, err = transformImage(image, opts, hasAlpha(ctx))
+		if err != nil {
+			return err
+		}
+	} else {
+		//no bimg pass rotates the image by its EXIF orientation, so it is baked before the pixels are decoded
+		var rotated bool
+		var err error
+		if buf, rotated, err = bakeOrientation(buf); err != nil {
+			return err
+		}
+		if rotated {
+			countPass(ctx)
+		}
+	}
+
+	for _, f := range queue {
+		if a, ok := f.(alphaPixelFilter); ok && a.needsAlpha() {
+			ctx.StateBag()[skropAlpha] = true
+		}
+	}
+
+	if hasAlpha(ctx) && !supportsAlpha(output.Type) {
+		log.Debugf("The image is converted to PNG to keep its transparency")
+		output.Type = bimg.PNG
+	}
+
+	countPass(ctx)
+	pixels, err := decodePixels(buf)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range queue {
+		log.Debugf("Transform the pixels of the image with %+v", f)
+		pixels, err = f.TransformPixels(pixels)
+		if err != nil {
+			return err
+		}
+	}
+
+	buf, err = encodePixels(pixels)
+	if err != nil {
+		return err
+	}
+	//the pixel filters are applied together, so the bytes are added for the last one, like the merged filters
+	metrics.AddBytes(filterName(queue[len(queue)-1]), len(image.Image()), len(buf))
+
+	ctx.StateBag()[skropImage] = bimg.NewImage(buf)
+	ctx.StateBag()[skropOptions] = output
+	ctx.StateBag()[hasMergedFilters] = true
+	ctx.StateBag()[skropLastMerged] = queue[len(queue)-1]
+	delete(ctx.StateBag(), skropPixelFilters)
+	return nil
+}
+
+// supportsAlpha tells if the image type can keep the transparency of the image
+func supportsAlpha(imageType bimg.ImageType) bool {
+	return imageType == bimg.PNG || imageType == bimg.WEBP || imageType == bimg.TIFF
+}
+
+// outputOptions returns the options which only concern the encoding of the image
+func outputOptions(o *bimg.Options) *bimg.Options {
+	return &bimg.Options{
+		Type:          o.Type,
+		Quality:       o.Quality,
+		Compression:   o.Compression,
+		Interlace:     o.Interlace,
+		StripMetadata: o.StripMetadata,
+		Lossless:      o.Lossless,
+		NoProfile:     o.NoProfile,
+		OutputICC:     o.OutputICC,
+	}
+}
+
+// decodePixels decodes the image in a NRGBA pixel buffer, converting it to PNG first if needed
+func decodePixels(buf []byte) (*image.NRGBA, error) {
+	if bimg.DetermineImageType(buf) != bimg.PNG {
+		var err error
+		buf, err = processImage(bimg.NewImage(buf), bimg.Options{Type: bimg.PNG})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	img, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba, nil
+	}
+
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	return nrgba, nil
+}
+
+// encodePixels encodes the pixel buffer as PNG
+func encodePixels(img *image.NRGBA) ([]byte, error) {
+	var buf bytes.Buffer
+
+	//the image is encoded again by the next transformation, so speed matters more than size
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// applyLookupTable maps the color channels of every pixel through the table, leaving the alpha channel unchanged
+func applyLookupTable(img *image.NRGBA, table *[256]uint8) {
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			row[i] = table[row[i]]
+			row[i+1] = table[row[i+1]]
+			row[i+2] = table[row[i+2]]
+		}
+	}
+}
+
+// clampUint8 rounds the value to the closest uint8
+func clampUint8(value float64) uint8 {
+	if value <= 0 {
+		return 0
+	}
+	if value >= 255 {
+		return 255
+	}
+	return uint8(value + 0.5)
+}
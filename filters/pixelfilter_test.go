@@ -0,0 +1,137 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePixelFilter struct {
+	calls int
+}
+
+func (f *fakePixelFilter) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	f.calls++
+	return img, nil
+}
+
+func (f *fakePixelFilter) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *fakePixelFilter) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func TestHandlePixelResponse_Queue(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	f := &fakePixelFilter{}
+
+	err := HandlePixelResponse(fc, f)
+	assert.Nil(t, err)
+	err = HandlePixelResponse(fc, f)
+	assert.Nil(t, err)
+
+	assert.Len(t, fc.FStateBag[skropPixelFilters], 2)
+	assert.Equal(t, 0, f.calls, "the pixels should be transformed only when the queue is applied")
+}
+
+func TestHandlePixelResponse_Merge(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	HandlePixelResponse(fc, &brightness{factor: 2})
+	HandlePixelResponse(fc, &brightness{factor: 1.5})
+
+	queue := fc.FStateBag[skropPixelFilters].([]PixelFilter)
+	assert.Len(t, queue, 1)
+	assert.Equal(t, 3.0, queue[0].(*brightness).factor)
+}
+
+func TestHandlePixelResponse_WithResponse304(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FResponse.StatusCode = 304
+
+	err := HandlePixelResponse(fc, &fakePixelFilter{})
+
+	assert.NotNil(t, err, "should not able to process when the backend response is 304")
+}
+
+func TestApplyPixelFilters(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[hasMergedFilters] = false
+	f := &fakePixelFilter{}
+	HandlePixelResponse(fc, f)
+	HandlePixelResponse(fc, f)
+
+	err := applyPixelFilters(fc)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, f.calls)
+	assert.Nil(t, fc.FStateBag[skropPixelFilters])
+	assert.Equal(t, true, fc.FStateBag[hasMergedFilters])
+	assert.Equal(t, bimg.PNG, fc.FStateBag[skropOptions].(*bimg.Options).Type)
+}
+
+func TestOutputOptions(t *testing.T) {
+	o := &bimg.Options{Width: 100, Crop: true, Quality: 67, Type: bimg.JPEG, StripMetadata: true}
+
+	output := outputOptions(o)
+
+	assert.Equal(t, 0, output.Width)
+	assert.False(t, output.Crop)
+	assert.Equal(t, 67, output.Quality)
+	assert.Equal(t, bimg.JPEG, output.Type)
+	assert.True(t, output.StripMetadata)
+}
+
+func TestEncodeDecodePixels(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 10, G: 20, B: 30, A: 40})
+
+	buf, err := encodePixels(img)
+	assert.Nil(t, err)
+
+	decoded, err := decodePixels(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, img.Bounds(), decoded.Bounds())
+	assert.Equal(t, img.NRGBAAt(2, 2), decoded.NRGBAAt(2, 2))
+}
+
+func TestApplyLookupTable(t *testing.T) {
+	img := uniformImage(color.NRGBA{R: 1, G: 2, B: 3, A: 4})
+	var table [256]uint8
+	for i := range table {
+		table[i] = uint8(i * 10)
+	}
+
+	applyLookupTable(img, &table)
+
+	assert.Equal(t, color.NRGBA{R: 10, G: 20, B: 30, A: 4}, img.NRGBAAt(3, 3))
+}
+
+func TestClampUint8(t *testing.T) {
+	assert.Equal(t, uint8(0), clampUint8(-3))
+	assert.Equal(t, uint8(13), clampUint8(12.6))
+	assert.Equal(t, uint8(255), clampUint8(300))
+}
+
+func TestApplyPixelFilters_Alpha(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[hasMergedFilters] = false
+	fc.FStateBag[skropOptions] = &bimg.Options{Type: bimg.JPEG}
+	HandlePixelResponse(fc, &roundedCorners{radius: 10})
+
+	err := applyPixelFilters(fc)
+
+	assert.Nil(t, err)
+	assert.True(t, hasAlpha(fc))
+	assert.Equal(t, bimg.PNG, fc.FStateBag[skropOptions].(*bimg.Options).Type)
+}
+
+func TestSupportsAlpha(t *testing.T) {
+	assert.True(t, supportsAlpha(bimg.PNG))
+	assert.True(t, supportsAlpha(bimg.WEBP))
+	assert.False(t, supportsAlpha(bimg.JPEG))
+}
@@ -0,0 +1,112 @@
+package filters
+
+import (
+	"bytes"
+	"image/png"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// PngCompressionName is the name of the filter
+const PngCompressionName = "pngCompression"
+
+const maxPngCompression = 9
+
+// pngCompression sets the compression level of the PNG images, from 0 to 9. bimg uses its default level
+// instead of 0, so the images without compression are encoded again once they are transformed
+type pngCompression struct {
+	level int
+}
+
+// NewPngCompression creates a new filter of this type
+func NewPngCompression() filters.Spec {
+	return &pngCompression{}
+}
+
+func (f *pngCompression) Name() string {
+	return PngCompressionName
+}
+
+func (f *pngCompression) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for png compression ", f)
+
+	return &bimg.Options{Compression: f.level}, nil
+}
+
+func (f *pngCompression) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//it only concerns the encoding of the image, so the last one wins
+	return true
+}
+
+func (f *pngCompression) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Compression = self.Compression
+	return other
+}
+
+func (f *pngCompression) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	level, err := parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if level < 0 || level > maxPngCompression {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &pngCompression{level: level}, nil
+}
+
+func (f *pngCompression) Request(ctx filters.FilterContext) {}
+
+func (f *pngCompression) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	if f.level == 0 {
+		ctx.StateBag()[skropNoCompression] = true
+	} else {
+		delete(ctx.StateBag(), skropNoCompression)
+	}
+}
+
+// checkCompression ignores the compression level when the image is not encoded as PNG
+func checkCompression(ctx filters.FilterContext, image *bimg.Image, opts *bimg.Options) {
+	if opts.Compression == 0 && ctx.StateBag()[skropNoCompression] != true {
+		return
+	}
+
+	imageType := opts.Type
+	if imageType == bimg.UNKNOWN {
+		imageType = bimg.DetermineImageType(image.Image())
+	}
+
+	if imageType != bimg.PNG {
+		log.Warnf("The compression level is ignored, as the image is encoded as %s instead of PNG", bimg.ImageTypeName(imageType))
+		opts.Compression = 0
+		delete(ctx.StateBag(), skropNoCompression)
+	}
+}
+
+// encodeWithoutCompression encodes the PNG image again without any compression
+func encodeWithoutCompression(buf []byte) ([]byte, error) {
+	img, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.NoCompression}
+	if err := encoder.Encode(&out, img); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,112 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewPngCompression(t *testing.T) {
+	name := NewPngCompression().Name()
+	assert.Equal(t, "pngCompression", name)
+}
+
+func TestPngCompression_CreateOptions(t *testing.T) {
+	options, _ := (&pngCompression{level: 9}).CreateOptions(nil)
+	assert.Equal(t, 9, options.Compression)
+}
+
+func TestPngCompression_CanBeMerged(t *testing.T) {
+	s := pngCompression{}
+	self := &bimg.Options{Compression: 9}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 100, Compression: 3}, self))
+}
+
+func TestPngCompression_Merge(t *testing.T) {
+	s := pngCompression{}
+
+	opt := s.Merge(&bimg.Options{Width: 100, Compression: 3}, &bimg.Options{Compression: 9})
+
+	assert.Equal(t, 9, opt.Compression)
+	assert.Equal(t, 100, opt.Width)
+}
+
+func TestPngCompression_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewPngCompression, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "no compression",
+		Args: []interface{}{0.0},
+		Err:  false,
+	}, {
+		Msg:  "best compression",
+		Args: []interface{}{9.0},
+		Err:  false,
+	}, {
+		Msg:  "negative level",
+		Args: []interface{}{-1.0},
+		Err:  true,
+	}, {
+		Msg:  "too high level",
+		Args: []interface{}{10.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{1.0, 2.0},
+		Err:  true,
+	}})
+}
+
+func TestCheckCompression(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	opts := &bimg.Options{Compression: 9}
+	checkCompression(fc, imagefiltertest.LandscapeImage(), opts)
+	assert.Equal(t, 0, opts.Compression, "the JPEG image has no compression level")
+
+	fc.FStateBag[skropNoCompression] = true
+	checkCompression(fc, imagefiltertest.LandscapeImage(), &bimg.Options{})
+	assert.Nil(t, fc.FStateBag[skropNoCompression])
+
+	opts = &bimg.Options{Compression: 9, Type: bimg.PNG}
+	checkCompression(fc, imagefiltertest.LandscapeImage(), opts)
+	assert.Equal(t, 9, opts.Compression)
+}
+
+func TestEncodeWithoutCompression(t *testing.T) {
+	original, _ := bimg.Read(imagefiltertest.PNGImageFile)
+
+	buf, err := encodeWithoutCompression(original)
+	assert.Nil(t, err)
+	assert.True(t, len(buf) > len(original), "the image without compression should be bigger")
+
+	expected, _ := decodePixels(original)
+	result, _ := decodePixels(buf)
+	assert.Equal(t, expected.Pix, result.Pix)
+}
+
+func TestPngCompression_Response(t *testing.T) {
+	size := func(level float64) int {
+		fc := createDefaultContext(t, "doesNotMatter.com")
+		fc.FStateBag[hasMergedFilters] = false
+
+		f, _ := NewPngCompression().CreateFilter([]interface{}{level})
+		f.Response(fc)
+		FinalizeResponse(fc)
+
+		result := readResultImage(fc.Response().Body, t)
+		assert.Equal(t, "png", result.Type())
+		return len(result.Image())
+	}
+
+	best, none := size(9), size(0)
+
+	assert.NotZero(t, best)
+	assert.True(t, best < none, "the image with level 9 should be smaller, %d >= %d", best, none)
+}
@@ -0,0 +1,80 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// PosterizeName is the name of the filter
+const PosterizeName = "posterize"
+
+type posterize struct {
+	levels int
+}
+
+// NewPosterize creates a new filter of this type
+func NewPosterize() filters.Spec {
+	return &posterize{}
+}
+
+func (f *posterize) Name() string {
+	return PosterizeName
+}
+
+func (f *posterize) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for posterize ", f)
+
+	if f.levels >= 256 {
+		return img, nil
+	}
+
+	//every value is rounded to the nearest of the levels, spread evenly from black to white
+	step := 255 / float64(f.levels-1)
+	var table [256]uint8
+	for i := range table {
+		table[i] = clampUint8(math.Round(float64(i)/step) * step)
+	}
+
+	applyLookupTable(img, &table)
+	return img, nil
+}
+
+// CanBeMerged merges the posterize filters with the same levels, as posterizing an image twice changes nothing
+func (f *posterize) CanBeMerged(other PixelFilter) bool {
+	o, ok := other.(*posterize)
+	return ok && o.levels == f.levels
+}
+
+func (f *posterize) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *posterize) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	p := &posterize{}
+
+	if p.levels, err = parse.EskipIntArg(args[0]); err != nil {
+		return nil, parse.AtArg(err, PosterizeName, 1, "levels")
+	}
+	if p.levels < 2 || p.levels > 256 {
+		log.Errorf("Failed to create the posterize filter, the levels %d are not between 2 and 256", p.levels)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return p, nil
+}
+
+func (f *posterize) Request(ctx filters.FilterContext) {}
+
+func (f *posterize) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
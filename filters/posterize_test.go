@@ -0,0 +1,76 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewPosterize(t *testing.T) {
+	name := NewPosterize().Name()
+	assert.Equal(t, "posterize", name)
+}
+
+func TestPosterize_TransformPixels(t *testing.T) {
+	for _, levels := range []int{2, 4, 7, 100} {
+		result, err := (&posterize{levels: levels}).TransformPixels(grayGradient())
+		assert.Nil(t, err)
+
+		distinct := map[uint8]bool{}
+		for x := 0; x < 256; x++ {
+			distinct[result.NRGBAAt(x, 0).R] = true
+		}
+		assert.Equal(t, levels, len(distinct), "the gradient should have %d bands", levels)
+		assert.True(t, distinct[0] && distinct[255], "the bands should go from black to white")
+	}
+}
+
+func TestPosterize_TransformPixels_KeepsAlpha(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 100}
+
+	result, err := (&posterize{levels: 2}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 255, G: 0, B: 0, A: 100}, result.NRGBAAt(1, 1))
+}
+
+func TestPosterize_TransformPixels_NoOp(t *testing.T) {
+	result, err := (&posterize{levels: 256}).TransformPixels(grayGradient())
+
+	assert.Nil(t, err)
+	assert.Equal(t, grayGradient(), result)
+}
+
+func TestPosterize_Merge(t *testing.T) {
+	f := posterize{levels: 4}
+
+	assert.True(t, f.CanBeMerged(&posterize{levels: 4}))
+	assert.False(t, f.CanBeMerged(&posterize{levels: 8}))
+	assert.Equal(t, &posterize{levels: 4}, f.Merge(&posterize{levels: 4}))
+}
+
+func TestPosterize_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewPosterize, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "levels",
+		Args: []interface{}{4.0},
+		Err:  false,
+	}, {
+		Msg:  "one level",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "too many levels",
+		Args: []interface{}{257.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{4.0, 8.0},
+		Err:  true,
+	}})
+}
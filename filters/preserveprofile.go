@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"os"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// PreserveProfileName is the name of the filter
+const PreserveProfileName = "preserveProfile"
+
+// preserveProfile keeps the ICC profile of the image, even when the metadata is stripped, so the wide-gamut
+// images keep their colors. With an output profile, the colors are converted to it instead
+type preserveProfile struct {
+	outputProfile string
+}
+
+// NewPreserveProfile creates a new filter of this type
+func NewPreserveProfile() filters.Spec {
+	return &preserveProfile{}
+}
+
+func (f *preserveProfile) Name() string {
+	return PreserveProfileName
+}
+
+func (f *preserveProfile) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for preserve profile ", f)
+
+	return &bimg.Options{OutputICC: f.outputProfile}, nil
+}
+
+func (f *preserveProfile) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return other.OutputICC == "" || self.OutputICC == "" || other.OutputICC == self.OutputICC
+}
+
+func (f *preserveProfile) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.NoProfile = false
+	if self.OutputICC != "" {
+		other.OutputICC = self.OutputICC
+	}
+	return other
+}
+
+func (f *preserveProfile) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) > 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	p := &preserveProfile{}
+
+	if len(args) == 1 {
+		var err error
+		p.outputProfile, err = parse.EskipStringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		//libvips reads the profile only when transforming the image, so a missing file is reported early
+		if _, err := os.Stat(p.outputProfile); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (f *preserveProfile) Request(ctx filters.FilterContext) {}
+
+func (f *preserveProfile) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	ctx.StateBag()[skropPreserveProfile] = true
+}
+
+// keepProfile makes the options keep the ICC profile when it is preserved. The metadata to strip is then removed
+// from the encoded image, as libvips would remove the profile with it
+func keepProfile(ctx filters.FilterContext, opts *bimg.Options) {
+	if ctx.StateBag()[skropPreserveProfile] != true {
+		return
+	}
+
+	if opts.StripMetadata {
+		ctx.StateBag()[skropStripKeepingProfile] = true
+	}
+
+	opts.StripMetadata = false
+	opts.NoProfile = false
+}
@@ -0,0 +1,179 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// displayP3Profile builds an ICC v4 Display P3 profile, with the primaries adapted to D50 and the sRGB curve
+func displayP3Profile() []byte {
+	s15f16 := func(values ...float64) []byte {
+		buf := make([]byte, 4*len(values))
+		for i, v := range values {
+			binary.BigEndian.PutUint32(buf[4*i:], uint32(int32(math.Round(v*65536))))
+		}
+		return buf
+	}
+	xyz := func(x, y, z float64) []byte {
+		return append([]byte("XYZ \x00\x00\x00\x00"), s15f16(x, y, z)...)
+	}
+	mluc := func(text string) []byte {
+		buf := []byte("mluc\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x0cenUS")
+		buf = append(buf, 0, 0, 0, byte(2*len(text)), 0, 0, 0, 28)
+		for _, r := range text {
+			buf = append(buf, 0, byte(r))
+		}
+		return buf
+	}
+	trc := append([]byte("para\x00\x00\x00\x00\x00\x03\x00\x00"), s15f16(2.4, 1/1.055, 0.055/1.055, 1/12.92, 0.04045)...)
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", mluc("Display P3")},
+		{"cprt", mluc("No copyright")},
+		{"wtpt", xyz(0.9642, 1, 0.8249)},
+		{"rXYZ", xyz(0.5151, 0.2412, -0.0011)},
+		{"gXYZ", xyz(0.2919, 0.6922, 0.0419)},
+		{"bXYZ", xyz(0.1572, 0.0666, 0.7841)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	table := make([]byte, 4, 4+12*len(tags))
+	binary.BigEndian.PutUint32(table, uint32(len(tags)))
+	var data []byte
+	offset := 128 + 4 + 12*len(tags)
+	for _, tag := range tags {
+		entry := make([]byte, 12)
+		copy(entry, tag.signature)
+		binary.BigEndian.PutUint32(entry[4:], uint32(offset+len(data)))
+		binary.BigEndian.PutUint32(entry[8:], uint32(len(tag.data)))
+		table = append(table, entry...)
+		data = append(data, tag.data...)
+		//the tags are aligned on 4 bytes
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header, uint32(128+len(table)+len(data)))
+	binary.BigEndian.PutUint32(header[8:], 0x04300000)
+	copy(header[12:], "mntrRGB XYZ ")
+	copy(header[36:], "acsp")
+	copy(header[68:], s15f16(0.9642, 1, 0.8249))
+
+	return append(append(header, table...), data...)
+}
+
+// withProfile embeds the ICC profile in the JPEG
+func withProfile(buf []byte, profile []byte) []byte {
+	data := append(append(append([]byte{}, iccProfileHeader...), 1, 1), profile...)
+	return withSegments(buf, jpegSegment(0xE2, data))
+}
+
+func TestNewPreserveProfile(t *testing.T) {
+	name := NewPreserveProfile().Name()
+	assert.Equal(t, "preserveProfile", name)
+}
+
+func TestPreserveProfile_CreateOptions(t *testing.T) {
+	options, _ := (&preserveProfile{outputProfile: "p3.icc"}).CreateOptions(nil)
+	assert.Equal(t, "p3.icc", options.OutputICC)
+	assert.False(t, options.NoProfile)
+}
+
+func TestPreserveProfile_CanBeMerged(t *testing.T) {
+	s := preserveProfile{}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 100, StripMetadata: true}, &bimg.Options{}))
+	assert.True(t, s.CanBeMerged(&bimg.Options{OutputICC: "p3.icc"}, &bimg.Options{}))
+	assert.True(t, s.CanBeMerged(&bimg.Options{OutputICC: "p3.icc"}, &bimg.Options{OutputICC: "p3.icc"}))
+	assert.False(t, s.CanBeMerged(&bimg.Options{OutputICC: "p3.icc"}, &bimg.Options{OutputICC: "srgb.icc"}))
+}
+
+func TestPreserveProfile_Merge(t *testing.T) {
+	s := preserveProfile{}
+
+	opt := s.Merge(&bimg.Options{Width: 100, NoProfile: true}, &bimg.Options{OutputICC: "p3.icc"})
+
+	assert.False(t, opt.NoProfile)
+	assert.Equal(t, "p3.icc", opt.OutputICC)
+	assert.Equal(t, 100, opt.Width)
+}
+
+func TestPreserveProfile_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewPreserveProfile, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "output profile",
+		Args: []interface{}{"../images/star.png"},
+		Err:  false,
+	}, {
+		Msg:  "missing output profile",
+		Args: []interface{}{"../images/missing.icc"},
+		Err:  true,
+	}, {
+		Msg:  "not a string",
+		Args: []interface{}{1.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{"a.icc", "b.icc"},
+		Err:  true,
+	}})
+}
+
+func TestKeepProfile(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	opts := &bimg.Options{StripMetadata: true, NoProfile: true}
+
+	keepProfile(fc, opts)
+	assert.True(t, opts.StripMetadata, "the profile is not preserved")
+
+	fc.FStateBag[skropPreserveProfile] = true
+	keepProfile(fc, opts)
+
+	assert.False(t, opts.StripMetadata)
+	assert.False(t, opts.NoProfile)
+	assert.Equal(t, true, fc.FStateBag[skropStripKeepingProfile], "the metadata is stripped from the encoded image")
+}
+
+func TestPreserveProfile_Response(t *testing.T) {
+	original, _ := bimg.Read(imagefiltertest.PortraitImageFile)
+	profile := displayP3Profile()
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = bimg.NewImage(withProfile(original, profile))
+	fc.FStateBag[hasMergedFilters] = false
+
+	//the filters are called in the reverse order of the route
+	stripMetadata, _ := NewStripMetadata().CreateFilter(nil)
+	stripMetadata.Response(fc)
+	convert, _ := NewConvert().CreateFilter([]interface{}{"png"})
+	convert.Response(fc)
+	resize, _ := NewResize().CreateFilter([]interface{}{400.0, 600.0})
+	resize.Response(fc)
+	(&preserveProfile{}).Response(fc)
+	FinalizeResponse(fc)
+
+	result := readResultImage(fc.Response().Body, t)
+	size, _ := result.Size()
+	metadata, _ := result.Metadata()
+
+	assert.Equal(t, "png", result.Type())
+	assert.Equal(t, 400, size.Width)
+	assert.True(t, metadata.Profile, "the Display P3 profile should be kept")
+	assert.True(t, bytes.Contains(result.Image(), []byte("iCCP")))
+}
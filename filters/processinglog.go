@@ -0,0 +1,75 @@
+package filters
+
+import (
+	"time"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando/skipper/filters"
+)
+
+// requestLog is the processing of the image of a request, logged when the response is finalized
+type requestLog struct {
+	start      time.Time
+	inputBytes int
+	filters    []string
+}
+
+// startRequestLog starts recording the processing of the image, when the response is read
+func startRequestLog(ctx filters.FilterContext) {
+	ctx.StateBag()[skropRequestLog] = &requestLog{start: time.Now()}
+}
+
+func getRequestLog(ctx filters.FilterContext) *requestLog {
+	if l, ok := ctx.StateBag()[skropRequestLog].(*requestLog); ok {
+		return l
+	}
+	return &requestLog{start: time.Now()}
+}
+
+// logFilter records the filter applied on the image and logs how long it took to handle its response
+func logFilter(ctx filters.FilterContext, name string, duration time.Duration, err error) {
+	if log.GetLevel() < log.DebugLevel {
+		return
+	}
+
+	if err == nil && ctx.StateBag()[skropSkipped] != true {
+		l := getRequestLog(ctx)
+		l.filters = append(l.filters, name)
+		ctx.StateBag()[skropRequestLog] = l
+	}
+
+	entry := log.WithFields(log.Fields{
+		"path":     ctx.Request().URL.Path,
+		"filter":   name,
+		"duration": duration,
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Debug("Filter response handled")
+}
+
+// logProcessing logs the whole processing of the image of the request, with the output image
+func logProcessing(ctx filters.FilterContext, output []byte) {
+	if log.GetLevel() < log.DebugLevel {
+		return
+	}
+
+	l := getRequestLog(ctx)
+	fields := log.Fields{
+		"path":        ctx.Request().URL.Path,
+		"filters":     l.filters,
+		"inputBytes":  l.inputBytes,
+		"outputBytes": len(output),
+		"passes":      Passes(ctx),
+		"duration":    time.Since(l.start),
+	}
+
+	if size, err := readImageSize(bimg.NewImage(output)); err == nil {
+		fields["width"] = size.Width
+		fields["height"] = size.Height
+	}
+
+	log.WithFields(fields).Debug("Image processed")
+}
@@ -0,0 +1,59 @@
+package filters
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func processedEntry(hook *logtest.Hook) *log.Entry {
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Image processed" {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestFinalizeResponse_LogsProcessing(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+	level := log.GetLevel()
+	log.SetLevel(log.DebugLevel)
+	defer log.SetLevel(level)
+
+	fc := createContext(t, "GET", "/images/bag.png", imagefiltertest.PNGImageFile, map[string]interface{}{})
+
+	assert.Nil(t, HandleImageResponse(fc, &resize{width: 100, height: 100}))
+	assert.Nil(t, HandlePixelResponse(fc, &negate{active: true}))
+	FinalizeResponse(fc)
+
+	entry := processedEntry(hook)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "/images/bag.png", entry.Data["path"])
+		assert.Equal(t, []string{"resize", "negate"}, entry.Data["filters"])
+		assert.True(t, entry.Data["inputBytes"].(int) > 0)
+		assert.Equal(t, int(fc.Response().ContentLength), entry.Data["outputBytes"])
+		assert.Equal(t, 100, entry.Data["width"])
+		assert.NotNil(t, entry.Data["duration"])
+	}
+}
+
+func TestFinalizeResponse_LogsNothingByDefault(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+	level := log.GetLevel()
+	log.SetLevel(log.InfoLevel)
+	defer log.SetLevel(level)
+
+	fc := createContext(t, "GET", "/images/bag.png", imagefiltertest.PNGImageFile, map[string]interface{}{})
+
+	HandleImageResponse(fc, &resize{width: 100, height: 100})
+	FinalizeResponse(fc)
+
+	assert.Nil(t, processedEntry(hook))
+	assert.Nil(t, fc.FStateBag[skropRequestLog].(*requestLog).filters)
+}
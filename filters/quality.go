@@ -10,6 +10,11 @@ import (
 // QualityName is the name of the filter
 const QualityName = "quality"
 
+const (
+	minQuality = 1
+	maxQuality = 100
+)
+
 type quality struct {
 	percentage int
 }
@@ -31,7 +36,8 @@ func (f *quality) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
 }
 
 func (f *quality) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
-	return other.Quality == 0 || other.Quality == self.Quality
+	//the quality is only used when encoding the image, so the last one wins whatever the other transformations are
+	return true
 }
 
 func (f *quality) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
@@ -52,8 +58,13 @@ func (f *quality) CreateFilter(args []interface{}) (filters.Filter, error) {
 
 	if err != nil {
 		return nil, err
-	} else if c.percentage > 100 {
-		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	//the quality is clamped, 0 would mean the default quality of bimg
+	if c.percentage < minQuality {
+		c.percentage = minQuality
+	} else if c.percentage > maxQuality {
+		c.percentage = maxQuality
 	}
 
 	return c, nil
@@ -33,12 +33,13 @@ func TestNewQuality_CanBeMerged_True(t *testing.T) {
 	assert.True(t, s.CanBeMerged(opt, self))
 }
 
-func TestNewQuality_CanBeMerged_False(t *testing.T) {
+func TestNewQuality_CanBeMerged_OtherQuality(t *testing.T) {
 	s := quality{}
-	opt := &bimg.Options{Quality: 27}
+	opt := &bimg.Options{Quality: 27, Width: 100, Crop: true}
 	self := &bimg.Options{Quality: 85}
 
-	assert.False(t, s.CanBeMerged(opt, self))
+	assert.True(t, s.CanBeMerged(opt, self))
+	assert.Equal(t, 85, s.Merge(opt, self).Quality)
 }
 
 func TestNewQuality_Merge(t *testing.T) {
@@ -62,10 +63,39 @@ func TestNewQuality_CreateFilter(t *testing.T) {
 	}, {
 		Msg:  "too high value",
 		Args: []interface{}{110.0},
-		Err:  true,
+		Err:  false,
+	}, {
+		Msg:  "too low value",
+		Args: []interface{}{-10.0},
+		Err:  false,
 	}, {
 		Msg:  "more than one args",
 		Args: []interface{}{80.0, 90.0},
 		Err:  true,
 	}})
 }
+
+func TestNewQuality_CreateFilter_Clamped(t *testing.T) {
+	f, _ := NewQuality().CreateFilter([]interface{}{110.0})
+	assert.Equal(t, 100, f.(*quality).percentage)
+
+	f, _ = NewQuality().CreateFilter([]interface{}{0.0})
+	assert.Equal(t, 1, f.(*quality).percentage)
+}
+
+func TestNewQuality_Response(t *testing.T) {
+	size := func(percentage float64) int {
+		fc := createContext(t, "GET", "url", imagefiltertest.LandscapeImageFile, map[string]interface{}{})
+
+		f, _ := NewQuality().CreateFilter([]interface{}{percentage})
+		f.Response(fc)
+		FinalizeResponse(fc)
+
+		return len(readResultImage(fc.Response().Body, t).Image())
+	}
+
+	low, high := size(20), size(90)
+
+	assert.NotZero(t, low)
+	assert.True(t, low < high, "the image with quality 20 should be smaller, %d >= %d", low, high)
+}
@@ -18,6 +18,7 @@ type resize struct {
 	width           int
 	height          int
 	keepAspectRatio bool
+	doNotEnlarge    bool
 }
 
 // NewResize creates a new filter of this type
@@ -32,34 +33,49 @@ func (f *resize) Name() string {
 func (f *resize) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
 	log.Debug("Create options for resize ", f)
 
-	if !f.keepAspectRatio {
+	if !f.keepAspectRatio && !f.doNotEnlarge {
 		return &bimg.Options{
 			Width:  f.width,
 			Height: f.height,
 			Force:  true}, nil
 	}
 
-	size, err := imageContext.Image.Size()
+	size, err := imageContext.ImageSize()
 	if err != nil {
 		return nil, err
 	}
 
+	if !f.keepAspectRatio {
+		return &bimg.Options{
+			Width:  f.clamp(f.width, size.Width),
+			Height: f.clamp(f.height, size.Height),
+			Force:  true}, nil
+	}
+
 	// calculate height keeping width
 	ht := int(math.Floor(float64(size.Height*f.width) / float64(size.Width)))
 
 	// if height is less or equal than desired, return transform by width
 	if ht <= f.height {
 		return &bimg.Options{
-			Width: f.width}, nil
+			Width: f.clamp(f.width, size.Width)}, nil
 	}
 	// otherwise transform by height
 	return &bimg.Options{
-		Height: f.height}, nil
+		Height: f.clamp(f.height, size.Height)}, nil
+
+}
 
+// clamp caps the dimension at the one of the source image, if the image must not be enlarged
+func (f *resize) clamp(dimension int, source int) int {
+	if f.doNotEnlarge && dimension > source {
+		return source
+	}
+	return dimension
 }
 
 func (f *resize) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
-	return (other.AreaWidth == 0 && other.AreaHeight == 0 ) && ((other.Width == 0 && other.Height == 0) ||
+	return (other.AreaWidth == 0 && other.AreaHeight == 0) && !other.Trim && ((other.Width == 0 && other.Height == 0) ||
 		(self.Width == other.Width && self.Height == other.Height))
 }
 
@@ -72,7 +88,7 @@ func (f *resize) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
 func (f *resize) CreateFilter(args []interface{}) (filters.Filter, error) {
 	var err error
 
-	if len(args) != 2 && len(args) != 3 {
+	if len(args) < 2 || len(args) > 4 {
 		return nil, filters.ErrInvalidFilterParameters
 	}
 
@@ -81,25 +97,33 @@ func (f *resize) CreateFilter(args []interface{}) (filters.Filter, error) {
 	c.width, err = parse.EskipIntArg(args[0])
 
 	if err != nil {
-		return nil, err
+		return nil, parse.AtArg(err, ResizeName, 1, "width")
 	}
 
 	c.height, err = parse.EskipIntArg(args[1])
 
 	if err != nil {
-		return nil, err
+		return nil, parse.AtArg(err, ResizeName, 2, "height")
 	}
 
-	if len(args) == 3 {
+	c.keepAspectRatio = true
+
+	if len(args) >= 3 {
 		ratio, err := parse.EskipStringArg(args[2])
 		if err != nil {
-			return nil, err
+			return nil, parse.AtArg(err, ResizeName, 3, "ratio")
 		}
 
 		c.keepAspectRatio = !(ratio == ignoreAspectRatioStr)
+	}
 
-	} else {
-		c.keepAspectRatio = true
+	//the fourth arg is only there to prevent the enlargement
+	if len(args) == 4 {
+		if cons, ok := args[3].(string); ok && cons == doNotEnlarge {
+			c.doNotEnlarge = true
+		} else {
+			return nil, filters.ErrInvalidFilterParameters
+		}
 	}
 
 	return c, nil
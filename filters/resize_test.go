@@ -63,6 +63,54 @@ func TestResize_CreateOptions_WithProportions2(t *testing.T) {
 	assert.Zero(t, options.Height)
 }
 
+func TestResize_CreateOptions_Enlarge(t *testing.T) {
+	r := resize{width: 2000, height: 2000, keepAspectRatio: true}
+	options, _ := r.CreateOptions(buildParameters(nil, detailedImage(1000, 750, 1000)))
+
+	assert.Equal(t, 2000, options.Width)
+	assert.Zero(t, options.Height)
+}
+
+func TestResize_CreateOptions_DoNotEnlarge(t *testing.T) {
+	r := resize{width: 2000, height: 2000, keepAspectRatio: true, doNotEnlarge: true}
+	options, _ := r.CreateOptions(buildParameters(nil, detailedImage(1000, 750, 1000)))
+
+	assert.Equal(t, 1000, options.Width)
+	assert.Zero(t, options.Height)
+}
+
+func TestResize_CreateOptions_IgnoreProportions_DoNotEnlarge(t *testing.T) {
+	r := resize{width: 2000, height: 500, doNotEnlarge: true}
+	options, _ := r.CreateOptions(buildParameters(nil, detailedImage(1000, 750, 1000)))
+
+	assert.Equal(t, 1000, options.Width)
+	assert.Equal(t, 500, options.Height)
+	assert.True(t, options.Force)
+}
+
+func TestResize_CreateOptions_AfterRotate(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+	fc.FStateBag[skropOptions] = &bimg.Options{Rotate: bimg.D90}
+	r := resize{width: 100, height: 100, keepAspectRatio: true}
+
+	//the rotated image is a portrait, so it is fitted by height
+	options, err := r.CreateOptions(buildParameters(fc, detailedImage(400, 200, 400)))
+	assert.Nil(t, err)
+	assert.Equal(t, &bimg.Options{Height: 100}, options)
+}
+
+func TestResize_DoNotEnlarge_NotMergedWithBiggerCrop(t *testing.T) {
+	image := detailedImage(1000, 750, 1000)
+	r := resize{width: 2000, height: 2000, keepAspectRatio: true, doNotEnlarge: true}
+	c := crop{width: 2000, height: 2000, cropType: Center}
+
+	resizeOptions, _ := r.CreateOptions(buildParameters(nil, image))
+	merged := r.Merge(&bimg.Options{}, resizeOptions)
+
+	cropOptions, _ := c.CreateOptions(buildParameters(nil, image))
+	assert.False(t, c.CanBeMerged(merged, cropOptions))
+}
+
 func TestResize_CanBeMerged_True(t *testing.T) {
 	s := resize{}
 	opt := &bimg.Options{}
@@ -79,6 +127,14 @@ func TestResize_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestResize_CanBeMerged_PendingTrim(t *testing.T) {
+	s := resize{}
+	opt := &bimg.Options{Trim: true, Threshold: 10}
+	self := &bimg.Options{Width: 265, Height: 365}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
 func TestResize_Merge(t *testing.T) {
 	s := resize{}
 	self := &bimg.Options{Width: 265, Height: 365}
@@ -102,10 +158,18 @@ func TestResize_CreateFilter(t *testing.T) {
 		Msg:  "three args",
 		Args: []interface{}{800.0, 600.0, "ignoreAspectRatio"},
 		Err:  false,
+	}, {
+		Msg:  "four args",
+		Args: []interface{}{800.0, 600.0, "ignoreAspectRatio", "DO_NOT_ENLARGE"},
+		Err:  false,
 	}, {
 		Msg:  "more than 3 args",
 		Args: []interface{}{800.0, 200.0, "test", "Whaaat!"},
 		Err:  true,
+	}, {
+		Msg:  "more than 4 args",
+		Args: []interface{}{800.0, 200.0, "test", "DO_NOT_ENLARGE", "DO_NOT_ENLARGE"},
+		Err:  true,
 	}})
 }
 
@@ -127,3 +191,14 @@ func TestResize_CreateFilter_KeepAspectRatio(t *testing.T) {
 
 	assert.Equal(t, true, i.keepAspectRatio)
 }
+
+func TestResize_CreateFilter_DoNotEnlarge(t *testing.T) {
+
+	f := &resize{}
+
+	nf, _ := f.CreateFilter([]interface{}{100.0, 50.0, "anything", "DO_NOT_ENLARGE"})
+	i := nf.(*resize)
+
+	assert.Equal(t, true, i.keepAspectRatio)
+	assert.Equal(t, true, i.doNotEnlarge)
+}
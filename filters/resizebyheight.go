@@ -28,7 +28,7 @@ func (f *resizeByHeight) CreateOptions(imageContext *ImageFilterContext) (*bimg.
 	log.Debug("Create options for resize by width ", f)
 
 	if !f.enlarge {
-		size, err := imageContext.Image.Size()
+		size, err := imageContext.ImageSize()
 		if err != nil {
 			return nil, err
 		}
@@ -44,7 +44,7 @@ func (f *resizeByHeight) CreateOptions(imageContext *ImageFilterContext) (*bimg.
 }
 
 func (f *resizeByHeight) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
-	return other.Height == 0 || other.Height == self.Height
+	return !extractPending(other) && (other.Height == 0 || other.Height == self.Height)
 }
 
 func (f *resizeByHeight) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
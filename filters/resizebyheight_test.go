@@ -73,6 +73,14 @@ func TestResizeByHeight_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestResizeByHeight_CanBeMerged_PendingExtract(t *testing.T) {
+	s := resizeByHeight{}
+	opt := &bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}
+	self := &bimg.Options{Height: 265}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
 func TestResizeByHeight_Merge(t *testing.T) {
 	s := resizeByHeight{}
 	self := &bimg.Options{Height: 365}
@@ -28,7 +28,7 @@ func (f *resizeByWidth) CreateOptions(imageContext *ImageFilterContext) (*bimg.O
 	log.Debug("Create options for resize by width ", f)
 
 	if !f.enlarge {
-		size, err := imageContext.Image.Size()
+		size, err := imageContext.ImageSize()
 		if err != nil {
 			return nil, err
 		}
@@ -44,7 +44,7 @@ func (f *resizeByWidth) CreateOptions(imageContext *ImageFilterContext) (*bimg.O
 }
 
 func (f *resizeByWidth) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
-	return other.Width == 0 || other.Width == self.Width
+	return !extractPending(other) && (other.Width == 0 || other.Width == self.Width)
 }
 
 func (f *resizeByWidth) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
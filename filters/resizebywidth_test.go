@@ -73,6 +73,14 @@ func TestResizeByWidth_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestResizeByWidth_CanBeMerged_PendingExtract(t *testing.T) {
+	s := resizeByWidth{}
+	opt := &bimg.Options{Top: 10, Left: 20, AreaWidth: 30, AreaHeight: 40}
+	self := &bimg.Options{Width: 265}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
 func TestResizeByWidth_Merge(t *testing.T) {
 	s := resizeByWidth{}
 	self := &bimg.Options{Width: 265}
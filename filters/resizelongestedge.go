@@ -0,0 +1,97 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ResizeLongestEdgeName is the name of the filter
+const ResizeLongestEdgeName = "resizeLongestEdge"
+
+type resizeLongestEdge struct {
+	maxPixels int
+}
+
+// NewResizeLongestEdge creates a new filter of this type
+func NewResizeLongestEdge() filters.Spec {
+	return &resizeLongestEdge{}
+}
+
+func (f *resizeLongestEdge) Name() string {
+	return ResizeLongestEdgeName
+}
+
+func (f *resizeLongestEdge) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for resize longest edge ", f)
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	longest := size.Width
+	if size.Height > longest {
+		longest = size.Height
+	}
+
+	//the image is never upscaled
+	if longest <= f.maxPixels {
+		return &bimg.Options{}, nil
+	}
+
+	factor := float64(f.maxPixels) / float64(longest)
+
+	return &bimg.Options{
+		Width:  scaleDimension(size.Width, factor),
+		Height: scaleDimension(size.Height, factor),
+		Force:  true}, nil
+}
+
+func (f *resizeLongestEdge) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//the image is already small enough, so there is nothing to merge
+	if self.Width == 0 && self.Height == 0 {
+		return true
+	}
+
+	return other.AreaWidth == 0 && other.AreaHeight == 0 && !other.Crop && !other.Embed && !other.Trim &&
+		((other.Width == 0 && other.Height == 0) || (other.Width == self.Width && other.Height == self.Height))
+}
+
+func (f *resizeLongestEdge) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	if self.Width == 0 && self.Height == 0 {
+		return other
+	}
+
+	other.Width = self.Width
+	other.Height = self.Height
+	other.Force = self.Force
+	return other
+}
+
+func (f *resizeLongestEdge) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	r := &resizeLongestEdge{}
+
+	r.maxPixels, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if r.maxPixels <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return r, nil
+}
+
+func (f *resizeLongestEdge) Request(ctx filters.FilterContext) {}
+
+func (f *resizeLongestEdge) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
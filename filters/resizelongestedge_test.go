@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewResizeLongestEdge(t *testing.T) {
+	name := NewResizeLongestEdge().Name()
+	assert.Equal(t, "resizeLongestEdge", name)
+}
+
+func TestResizeLongestEdge_Name(t *testing.T) {
+	r := resizeLongestEdge{}
+	assert.Equal(t, "resizeLongestEdge", r.Name())
+}
+
+func TestResizeLongestEdge_CreateOptions_Landscape(t *testing.T) {
+	r := resizeLongestEdge{maxPixels: 100}
+	options, _ := r.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, 100, options.Width)
+	assert.Equal(t, 75, options.Height)
+}
+
+func TestResizeLongestEdge_CreateOptions_Portrait(t *testing.T) {
+	r := resizeLongestEdge{maxPixels: 100}
+	options, _ := r.CreateOptions(buildParameters(nil, detailedImage(300, 400, 300)))
+
+	assert.Equal(t, 75, options.Width)
+	assert.Equal(t, 100, options.Height)
+}
+
+func TestResizeLongestEdge_CreateOptions_NoUpscale(t *testing.T) {
+	r := resizeLongestEdge{maxPixels: 800}
+	options, _ := r.CreateOptions(buildParameters(nil, detailedImage(400, 300, 400)))
+
+	assert.Equal(t, &bimg.Options{}, options)
+}
+
+func TestResizeLongestEdge_CanBeMerged(t *testing.T) {
+	r := resizeLongestEdge{}
+	self := &bimg.Options{Width: 100, Height: 75, Force: true}
+
+	assert.True(t, r.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, r.CanBeMerged(&bimg.Options{Width: 200, Crop: true}, &bimg.Options{}))
+	assert.False(t, r.CanBeMerged(&bimg.Options{Width: 200}, self))
+	assert.False(t, r.CanBeMerged(&bimg.Options{Width: 100, Height: 75, Crop: true}, self))
+}
+
+func TestResizeLongestEdge_Merge(t *testing.T) {
+	r := resizeLongestEdge{}
+	self := &bimg.Options{Width: 100, Height: 75, Force: true}
+
+	merged := r.Merge(&bimg.Options{Quality: 80}, self)
+	assert.Equal(t, &bimg.Options{Quality: 80, Width: 100, Height: 75, Force: true}, merged)
+
+	merged = r.Merge(&bimg.Options{Width: 200, Crop: true}, &bimg.Options{})
+	assert.Equal(t, &bimg.Options{Width: 200, Crop: true}, merged)
+}
+
+func TestResizeLongestEdge_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewResizeLongestEdge, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{800.0},
+		Err:  false,
+	}, {
+		Msg:  "zero pixels",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{800.0, 600.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"math"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ResizePercentName is the name of the filter
+const ResizePercentName = "resizePercent"
+
+type resizePercent struct {
+	percent float64
+}
+
+// NewResizePercent creates a new filter of this type
+func NewResizePercent() filters.Spec {
+	return &resizePercent{}
+}
+
+func (f *resizePercent) Name() string {
+	return ResizePercentName
+}
+
+func (f *resizePercent) CreateOptions(imageContext *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for resize percent ", f)
+
+	size, err := imageContext.ImageSize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bimg.Options{
+		Width:  scaleDimension(size.Width, f.percent/100),
+		Height: scaleDimension(size.Height, f.percent/100),
+		Force:  true}, nil
+}
+
+// scaleDimension scales the dimension by the factor, rounding it to at least one pixel
+func scaleDimension(dimension int, factor float64) int {
+	return int(math.Max(math.Floor(float64(dimension)*factor+0.5), 1))
+}
+
+func (f *resizePercent) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//the percentage is of the image before the other resizes, so it cannot override them
+	return other.AreaWidth == 0 && other.AreaHeight == 0 && !other.Crop && !other.Embed && !other.Trim &&
+		((other.Width == 0 && other.Height == 0) || (other.Width == self.Width && other.Height == self.Height))
+}
+
+func (f *resizePercent) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Width = self.Width
+	other.Height = self.Height
+	other.Force = self.Force
+	return other
+}
+
+func (f *resizePercent) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	r := &resizePercent{}
+
+	r.percent, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if r.percent <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return r, nil
+}
+
+func (f *resizePercent) Request(ctx filters.FilterContext) {}
+
+func (f *resizePercent) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
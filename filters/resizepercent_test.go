@@ -0,0 +1,107 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewResizePercent(t *testing.T) {
+	name := NewResizePercent().Name()
+	assert.Equal(t, "resizePercent", name)
+}
+
+func TestResizePercent_Name(t *testing.T) {
+	r := resizePercent{}
+	assert.Equal(t, "resizePercent", r.Name())
+}
+
+func TestResizePercent_CreateOptions_Half(t *testing.T) {
+	image := imagefiltertest.LandscapeImage()
+	size, _ := image.Size()
+
+	r := resizePercent{percent: 50}
+	options, _ := r.CreateOptions(buildParameters(nil, image))
+
+	assert.Equal(t, (size.Width+1)/2, options.Width)
+	assert.Equal(t, (size.Height+1)/2, options.Height)
+	assert.True(t, options.Force)
+}
+
+func TestResizePercent_CreateOptions_Double(t *testing.T) {
+	image := imagefiltertest.LandscapeImage()
+	size, _ := image.Size()
+
+	r := resizePercent{percent: 200}
+	options, _ := r.CreateOptions(buildParameters(nil, image))
+
+	assert.Equal(t, 2*size.Width, options.Width)
+	assert.Equal(t, 2*size.Height, options.Height)
+}
+
+func TestResizePercent_CreateOptions_Unchanged(t *testing.T) {
+	image := imagefiltertest.PortraitImage()
+	size, _ := image.Size()
+
+	r := resizePercent{percent: 100}
+	options, _ := r.CreateOptions(buildParameters(nil, image))
+
+	assert.Equal(t, size.Width, options.Width)
+	assert.Equal(t, size.Height, options.Height)
+}
+
+func TestScaleDimension(t *testing.T) {
+	assert.Equal(t, 3, scaleDimension(5, 0.5))
+	assert.Equal(t, 1, scaleDimension(5, 0.01))
+}
+
+func TestResizePercent_CanBeMerged_True(t *testing.T) {
+	r := resizePercent{}
+	self := &bimg.Options{Width: 400, Height: 300, Force: true}
+
+	assert.True(t, r.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, r.CanBeMerged(&bimg.Options{Quality: 80, Type: bimg.WEBP}, self))
+}
+
+func TestResizePercent_CanBeMerged_False(t *testing.T) {
+	r := resizePercent{}
+	self := &bimg.Options{Width: 400, Height: 300, Force: true}
+
+	assert.False(t, r.CanBeMerged(&bimg.Options{Width: 200}, self))
+	assert.False(t, r.CanBeMerged(&bimg.Options{Width: 200, Height: 200, Crop: true}, self))
+}
+
+func TestResizePercent_Merge(t *testing.T) {
+	r := resizePercent{}
+	self := &bimg.Options{Width: 400, Height: 300, Force: true}
+
+	merged := r.Merge(&bimg.Options{Quality: 80}, self)
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Width: 400, Height: 300, Force: true}, merged)
+}
+
+func TestResizePercent_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewResizePercent, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{50.0},
+		Err:  false,
+	}, {
+		Msg:  "zero percent",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "negative percent",
+		Args: []interface{}{-50.0},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{50.0, 50.0},
+		Err:  true,
+	}})
+}
@@ -0,0 +1,79 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// RotateName is the name of the filter
+const RotateName = "rotate"
+
+type rotate struct {
+	angle bimg.Angle
+}
+
+// NewRotate creates a new filter of this type
+func NewRotate() filters.Spec {
+	return &rotate{}
+}
+
+func (f *rotate) Name() string {
+	return RotateName
+}
+
+func (f *rotate) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for rotate ", f)
+
+	return &bimg.Options{
+		Rotate: f.angle}, nil
+}
+
+func (f *rotate) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//bimg flips the image after rotating it, so a pending flip has to be applied first
+	return orientationCanBeMerged(other) && !other.Flip && !other.Flop
+}
+
+func (f *rotate) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Rotate = normalizeAngle(int(other.Rotate) + int(self.Rotate))
+	return other
+}
+
+func (f *rotate) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	angle, err := parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	//bimg only supports rotations by multiples of 90 degrees
+	if angle%90 != 0 {
+		log.Errorf("%s: the angle %d is not a multiple of 90 degrees", RotateName, angle)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return &rotate{angle: normalizeAngle(angle)}, nil
+}
+
+func (f *rotate) Request(ctx filters.FilterContext) {}
+
+func (f *rotate) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
+
+// orientationCanBeMerged checks if a rotation, flip or flop can be merged in the options.
+// bimg changes the orientation of the image before resizing, cropping or adding overlays to it,
+// so it can be merged only if none of them is waiting to be applied
+func orientationCanBeMerged(other *bimg.Options) bool {
+	return other.Width == 0 && other.Height == 0 && other.AreaWidth == 0 && other.AreaHeight == 0 &&
+		len(other.WatermarkImage.Buf) == 0
+}
+
+// normalizeAngle brings the angle in the [0, 360) interval
+func normalizeAngle(angle int) bimg.Angle {
+	return bimg.Angle((angle%360 + 360) % 360)
+}
@@ -0,0 +1,114 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewRotate(t *testing.T) {
+	name := NewRotate().Name()
+	assert.Equal(t, "rotate", name)
+}
+
+func TestRotate_Name(t *testing.T) {
+	c := rotate{}
+	assert.Equal(t, "rotate", c.Name())
+}
+
+func TestRotate_CreateOptions(t *testing.T) {
+	r := rotate{angle: bimg.D90}
+	image := imagefiltertest.LandscapeImage()
+	options, _ := r.CreateOptions(buildParameters(nil, image))
+
+	assert.Equal(t, bimg.D90, options.Rotate)
+}
+
+func TestRotate_CanBeMerged_True(t *testing.T) {
+	s := rotate{}
+	opt := &bimg.Options{Rotate: bimg.D90, Quality: 80}
+	self := &bimg.Options{Rotate: bimg.D180}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
+func TestRotate_CanBeMerged_False(t *testing.T) {
+	s := rotate{}
+	opt := &bimg.Options{Width: 100, Height: 200, Crop: true}
+	self := &bimg.Options{Rotate: bimg.D90}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestRotate_CanBeMerged_AfterFlip(t *testing.T) {
+	s := rotate{}
+	opt := &bimg.Options{Flip: true}
+	self := &bimg.Options{Rotate: bimg.D90}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestRotate_CanBeMerged_AfterOverlay(t *testing.T) {
+	s := rotate{}
+	opt := &bimg.Options{WatermarkImage: bimg.WatermarkImage{Buf: []byte{1, 2, 3}}}
+	self := &bimg.Options{Rotate: bimg.D90}
+
+	assert.False(t, s.CanBeMerged(opt, self))
+}
+
+func TestRotate_Merge(t *testing.T) {
+	s := rotate{}
+	self := &bimg.Options{Rotate: bimg.D90}
+
+	opt := s.Merge(&bimg.Options{}, self)
+	assert.Equal(t, bimg.D90, opt.Rotate)
+
+	opt = s.Merge(opt, &bimg.Options{Rotate: bimg.D180})
+	assert.Equal(t, bimg.D270, opt.Rotate)
+
+	opt = s.Merge(opt, &bimg.Options{Rotate: bimg.D180})
+	assert.Equal(t, bimg.D90, opt.Rotate)
+}
+
+func TestRotate_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewRotate, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{90.0},
+		Err:  false,
+	}, {
+		Msg:  "not a multiple of 90",
+		Args: []interface{}{45.0},
+		Err:  true,
+	}, {
+		Msg:  "not an int",
+		Args: []interface{}{90.5},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{90.0, 180.0},
+		Err:  true,
+	}})
+}
+
+func TestRotate_CreateFilter_Normalize(t *testing.T) {
+	for _, ti := range []struct {
+		arg      float64
+		expected bimg.Angle
+	}{
+		{0, bimg.D0},
+		{90, bimg.D90},
+		{360, bimg.D0},
+		{450, bimg.D90},
+		{-90, bimg.D270},
+	} {
+		f, err := NewRotate().CreateFilter([]interface{}{ti.arg})
+		assert.Nil(t, err)
+		assert.Equal(t, ti.expected, f.(*rotate).angle)
+	}
+}
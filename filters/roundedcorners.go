@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// RoundedCornersName is the name of the filter
+const RoundedCornersName = "roundedCorners"
+
+type roundedCorners struct {
+	radius int
+}
+
+// NewRoundedCorners creates a new filter of this type
+func NewRoundedCorners() filters.Spec {
+	return &roundedCorners{}
+}
+
+func (f *roundedCorners) Name() string {
+	return RoundedCornersName
+}
+
+func (f *roundedCorners) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for rounded corners ", f)
+
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	radius := f.radius
+	if radius > width/2 {
+		radius = width / 2
+	}
+	if radius > height/2 {
+		radius = height / 2
+	}
+
+	r := float64(radius)
+	for y := 0; y < radius; y++ {
+		for x := 0; x < radius; x++ {
+			//distance of the center of the pixel from the center of the corner circle
+			distance := math.Hypot(r-float64(x)-0.5, r-float64(y)-0.5)
+			coverage := math.Min(math.Max(r-distance+0.5, 0), 1)
+			if coverage == 1 {
+				continue
+			}
+
+			for _, p := range [4]image.Point{
+				{x, y},
+				{width - 1 - x, y},
+				{x, height - 1 - y},
+				{width - 1 - x, height - 1 - y},
+			} {
+				i := img.PixOffset(p.X+img.Rect.Min.X, p.Y+img.Rect.Min.Y) + 3
+				img.Pix[i] = uint8(float64(img.Pix[i]) * coverage)
+			}
+		}
+	}
+
+	return img, nil
+}
+
+func (f *roundedCorners) needsAlpha() bool {
+	return true
+}
+
+func (f *roundedCorners) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *roundedCorners) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *roundedCorners) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	c := &roundedCorners{}
+
+	c.radius, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if c.radius <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return c, nil
+}
+
+func (f *roundedCorners) Request(ctx filters.FilterContext) {}
+
+func (f *roundedCorners) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
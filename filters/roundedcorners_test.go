@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewRoundedCorners(t *testing.T) {
+	name := NewRoundedCorners().Name()
+	assert.Equal(t, "roundedCorners", name)
+}
+
+func TestRoundedCorners_Name(t *testing.T) {
+	c := roundedCorners{}
+	assert.Equal(t, "roundedCorners", c.Name())
+}
+
+func TestRoundedCorners_TransformPixels(t *testing.T) {
+	f := roundedCorners{radius: 8}
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 30))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.NRGBA{R: 10, G: 20, B: 30, A: 255}), image.ZP, draw.Src)
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	for _, corner := range [][2]int{{0, 0}, {19, 0}, {0, 29}, {19, 29}} {
+		assert.Equal(t, uint8(0), result.NRGBAAt(corner[0], corner[1]).A, "the corners should be transparent")
+	}
+	assert.Equal(t, uint8(255), result.NRGBAAt(8, 0).A)
+	assert.Equal(t, uint8(255), result.NRGBAAt(0, 8).A)
+	assert.Equal(t, uint8(255), result.NRGBAAt(10, 15).A)
+	assert.Equal(t, color.NRGBA{R: 10, G: 20, B: 30, A: 0}, result.NRGBAAt(0, 0))
+}
+
+func TestRoundedCorners_TransformPixels_BigRadius(t *testing.T) {
+	f := roundedCorners{radius: 100}
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.NRGBA{R: 10, G: 20, B: 30, A: 255}), image.ZP, draw.Src)
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(0), result.NRGBAAt(0, 0).A)
+	assert.Equal(t, uint8(255), result.NRGBAAt(10, 10).A)
+}
+
+func TestRoundedCorners_NeedsAlpha(t *testing.T) {
+	f := roundedCorners{radius: 2}
+	assert.True(t, f.needsAlpha())
+}
+
+func TestRoundedCorners_CanBeMerged(t *testing.T) {
+	f := roundedCorners{radius: 2}
+	assert.False(t, f.CanBeMerged(&roundedCorners{radius: 2}))
+}
+
+func TestRoundedCorners_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewRoundedCorners, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{20.0},
+		Err:  false,
+	}, {
+		Msg:  "zero radius",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "not an int",
+		Args: []interface{}{2.5},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{20.0, 10.0},
+		Err:  true,
+	}})
+}
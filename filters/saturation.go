@@ -0,0 +1,89 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// SaturationName is the name of the filter
+	SaturationName = "saturation"
+	maxSaturation  = 3.0
+)
+
+type saturation struct {
+	factor float64
+}
+
+// NewSaturation creates a new filter of this type
+func NewSaturation() filters.Spec {
+	return &saturation{}
+}
+
+func (f *saturation) Name() string {
+	return SaturationName
+}
+
+func (f *saturation) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for saturation ", f)
+
+	//a factor of 1.0 leaves the image unchanged
+	if f.factor == 1 {
+		return img, nil
+	}
+
+	//the colors are moved away from, or toward, the gray with the same luminance
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			gray := float64(luminance(row[i], row[i+1], row[i+2]))
+			row[i] = clampUint8(gray + (float64(row[i])-gray)*f.factor)
+			row[i+1] = clampUint8(gray + (float64(row[i+1])-gray)*f.factor)
+			row[i+2] = clampUint8(gray + (float64(row[i+2])-gray)*f.factor)
+		}
+	}
+
+	return img, nil
+}
+
+func (f *saturation) CanBeMerged(other PixelFilter) bool {
+	_, ok := other.(*saturation)
+	return ok
+}
+
+func (f *saturation) Merge(other PixelFilter) PixelFilter {
+	return &saturation{factor: other.(*saturation).factor * f.factor}
+}
+
+func (f *saturation) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	s := &saturation{}
+
+	s.factor, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if s.factor < 0 {
+		log.Errorf("Failed to create the saturation filter, the factor %v is negative", s.factor)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+	if s.factor > maxSaturation {
+		s.factor = maxSaturation
+	}
+
+	return s, nil
+}
+
+func (f *saturation) Request(ctx filters.FilterContext) {}
+
+func (f *saturation) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
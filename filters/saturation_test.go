@@ -0,0 +1,107 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewSaturation(t *testing.T) {
+	name := NewSaturation().Name()
+	assert.Equal(t, "saturation", name)
+}
+
+func TestSaturation_Name(t *testing.T) {
+	c := saturation{}
+	assert.Equal(t, "saturation", c.Name())
+}
+
+func TestSaturation_TransformPixels_Grayscale(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 128}
+	gray := luminance(c.R, c.G, c.B)
+
+	result, err := (&saturation{factor: 0}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: gray, G: gray, B: gray, A: 128}, result.NRGBAAt(1, 1))
+}
+
+func TestSaturation_TransformPixels(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	gray := float64(luminance(c.R, c.G, c.B))
+
+	result, err := (&saturation{factor: 1.5}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	p := result.NRGBAAt(0, 0)
+	assert.Equal(t, clampUint8(gray+(200-gray)*1.5), p.R)
+	assert.Equal(t, clampUint8(gray+(100-gray)*1.5), p.G)
+	assert.Equal(t, clampUint8(gray+(50-gray)*1.5), p.B)
+}
+
+func TestSaturation_TransformPixels_NoOp(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	result, err := (&saturation{factor: 1}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result)
+}
+
+func TestSaturation_CanBeMerged_True(t *testing.T) {
+	f := saturation{factor: 2}
+	assert.True(t, f.CanBeMerged(&saturation{factor: 0.5}))
+}
+
+func TestSaturation_CanBeMerged_False(t *testing.T) {
+	f := saturation{factor: 2}
+	assert.False(t, f.CanBeMerged(&brightness{factor: 0.5}))
+}
+
+func TestSaturation_Merge(t *testing.T) {
+	f := saturation{factor: 2}
+
+	merged := f.Merge(&saturation{factor: 0.25})
+
+	assert.Equal(t, &saturation{factor: 0.5}, merged)
+	assert.Equal(t, 2.0, f.factor)
+}
+
+func TestSaturation_SingleDecodeWithOtherColorFilters(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	HandlePixelResponse(fc, &brightness{factor: 1.2})
+	HandlePixelResponse(fc, &saturation{factor: 2})
+	HandlePixelResponse(fc, &saturation{factor: 0.75})
+	HandlePixelResponse(fc, &contrast{factor: 1.1})
+
+	//all of them are applied on the same decoded pixels, with the saturations merged in one
+	assert.Equal(t, []PixelFilter{&brightness{factor: 1.2}, &saturation{factor: 1.5}, &contrast{factor: 1.1}},
+		fc.FStateBag[skropPixelFilters])
+}
+
+func TestSaturation_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewSaturation, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{1.5},
+		Err:  false,
+	}, {
+		Msg:  "zero factor",
+		Args: []interface{}{0.0},
+		Err:  false,
+	}, {
+		Msg:  "negative factor",
+		Args: []interface{}{-0.5},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{1.5, 1.0},
+		Err:  true,
+	}})
+}
@@ -10,8 +10,17 @@ import (
 // For infomations about the parameters meanings and default values have a look here:
 // http://www.vips.ecs.soton.ac.uk/supported/current/doc/html/libvips/libvips-convolution.html#vips-sharpen
 
-// SharpenName is the name of the filter
-const SharpenName = "sharpen"
+const (
+	// SharpenName is the name of the filter
+	SharpenName = "sharpen"
+
+	// defaults used by the short form sharpen(<radius>), as suggested by libvips
+	defaultSharpenX1 = 2
+	defaultSharpenY2 = 10
+	defaultSharpenY3 = 20
+	defaultSharpenM1 = 0
+	defaultSharpenM2 = 3
+)
 
 type sharpen struct {
 	Radius int
@@ -55,7 +64,7 @@ func (f *sharpen) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
 func (f *sharpen) CreateFilter(args []interface{}) (filters.Filter, error) {
 	var err error
 
-	if len(args) != 6 {
+	if len(args) != 1 && len(args) != 6 {
 		return nil, filters.ErrInvalidFilterParameters
 	}
 
@@ -65,6 +74,18 @@ func (f *sharpen) CreateFilter(args []interface{}) (filters.Filter, error) {
 	if err != nil {
 		return nil, err
 	}
+	if r.Radius <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	if len(args) == 1 {
+		r.X1 = defaultSharpenX1
+		r.Y2 = defaultSharpenY2
+		r.Y3 = defaultSharpenY3
+		r.M1 = defaultSharpenM1
+		r.M2 = defaultSharpenM2
+		return r, nil
+	}
 
 	r.X1, err = parse.EskipFloatArg(args[1])
 	if err != nil {
@@ -48,6 +48,14 @@ func TestSharpen_CanBeMerged_False(t *testing.T) {
 	assert.False(t, s.CanBeMerged(opt, self))
 }
 
+func TestSharpen_CanBeMerged_WithResize(t *testing.T) {
+	s := sharpen{}
+	opt := &bimg.Options{Width: 800, Height: 600, Crop: true}
+	self := &bimg.Options{Sharpen: bimg.Sharpen{Radius: 1, X1: 2, Y2: 3, Y3: 4, M1: 5, M2: 6}}
+
+	assert.True(t, s.CanBeMerged(opt, self))
+}
+
 func TestSharpen_Merge(t *testing.T) {
 	s := sharpen{}
 	self := &bimg.Options{Sharpen: bimg.Sharpen{Radius: 1, X1: 2, Y2: 3, Y3: 4, M1: 5, M2: 6}}
@@ -62,6 +70,26 @@ func TestSharpen_CreateFilter(t *testing.T) {
 		Msg:  "no args",
 		Args: nil,
 		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{2.0},
+		Err:  false,
+	}, {
+		Msg:  "zero radius",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "negative radius",
+		Args: []interface{}{-1.0},
+		Err:  true,
+	}, {
+		Msg:  "negative radius six args",
+		Args: []interface{}{-2.0, 3.0, 2.0, 1.0, 2.5, 3.7},
+		Err:  true,
+	}, {
+		Msg:  "zero radius six args",
+		Args: []interface{}{0.0, 3.0, 2.0, 1.0, 2.5, 3.7},
+		Err:  true,
 	}, {
 		Msg:  "three args",
 		Args: []interface{}{25.0, 35.0, 103.0},
@@ -76,3 +104,16 @@ func TestSharpen_CreateFilter(t *testing.T) {
 		Err:  false,
 	}})
 }
+
+func TestSharpen_CreateFilter_ShortForm(t *testing.T) {
+	f, err := NewSharpen().CreateFilter([]interface{}{2.0})
+	assert.Nil(t, err)
+
+	s := f.(*sharpen)
+	assert.Equal(t, 2, s.Radius)
+	assert.Equal(t, float64(2), s.X1)
+	assert.Equal(t, float64(10), s.Y2)
+	assert.Equal(t, float64(20), s.Y3)
+	assert.Equal(t, float64(0), s.M1)
+	assert.Equal(t, float64(3), s.M2)
+}
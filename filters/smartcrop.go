@@ -0,0 +1,210 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// SmartCropName is the name of the filter
+	SmartCropName = "smartCrop"
+	// Attention smart crop strategy, keeping the part of the image with features catching the eye
+	Attention = "attention"
+	// Entropy smart crop strategy, keeping the part of the image with the most details
+	Entropy = "entropy"
+	//number of windows compared on each axis by the entropy crop
+	entropyCropSteps = 16
+	//number of pixels sampled in each window by the entropy crop
+	entropyCropSamples = 10000
+)
+
+var smartCropStrategies = map[string]bool{
+	Attention: true,
+	Entropy:   true,
+}
+
+type smartCrop struct {
+	width  int
+	height int
+}
+
+// NewSmartCrop creates a new filter of this type
+func NewSmartCrop() filters.Spec {
+	return &smartCrop{}
+}
+
+func (f *smartCrop) Name() string {
+	return SmartCropName
+}
+
+func (f *smartCrop) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for smart crop ", f)
+
+	return &bimg.Options{
+		Width:   f.width,
+		Height:  f.height,
+		Gravity: bimg.GravitySmart,
+		Crop:    true}, nil
+}
+
+func (f *smartCrop) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	return cropCanBeMerged(other, self)
+}
+
+func (f *smartCrop) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Width = self.Width
+	other.Height = self.Height
+	other.Gravity = self.Gravity
+	other.Crop = self.Crop
+	return other
+}
+
+func (f *smartCrop) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 2 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	if !smartCropSupported() {
+		log.Error(ErrSmartCropNotSupported.Error())
+		return nil, ErrSmartCropNotSupported
+	}
+
+	c := &smartCrop{}
+
+	c.width, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c.height, err = parse.EskipIntArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if c.width <= 0 || c.height <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return c, nil
+}
+
+func (f *smartCrop) Request(ctx filters.FilterContext) {}
+
+func (f *smartCrop) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
+
+// entropyCropOptions resizes the image of the size to cover the target size, like the crop does, and extracts the
+// window with the highest entropy. bimg only supports the attention strategy of libvips, so the window is found here
+func entropyCropOptions(img *bimg.Image, size bimg.ImageSize, width, height int) (*bimg.Options, error) {
+	//like the crop, the image is not enlarged
+	if size.Width < width && size.Height < height {
+		return &bimg.Options{
+			Width:   width,
+			Height:  height,
+			Gravity: bimg.GravityCentre,
+			Crop:    true}, nil
+	}
+
+	scale, scaledWidth, scaledHeight := coverSize(size, width, height)
+
+	//the size is the one of the image as displayed, so the pixels are rotated by the EXIF orientation
+	buf, _, err := bakeOrientation(img.Image())
+	if err != nil {
+		return nil, err
+	}
+
+	pixels, err := decodePixels(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	window := image.Rect(0, 0,
+		int(math.Min(float64(width)/scale, float64(size.Width))),
+		int(math.Min(float64(height)/scale, float64(size.Height))))
+	best := highestEntropyWindow(pixels, window)
+
+	return &bimg.Options{
+		Width:      scaledWidth,
+		Height:     scaledHeight,
+		Force:      true,
+		Left:       int(math.Min(math.Floor(float64(best.X)*scale+0.5), float64(scaledWidth-width))),
+		Top:        int(math.Min(math.Floor(float64(best.Y)*scale+0.5), float64(scaledHeight-height))),
+		AreaWidth:  width,
+		AreaHeight: height}, nil
+}
+
+// coverSize returns the scale and the size of the image resized to cover the width and the height
+func coverSize(size bimg.ImageSize, width, height int) (float64, int, int) {
+	scale := math.Max(float64(width)/float64(size.Width), float64(height)/float64(size.Height))
+	scaledWidth := int(math.Max(math.Floor(float64(size.Width)*scale+0.5), float64(width)))
+	scaledHeight := int(math.Max(math.Floor(float64(size.Height)*scale+0.5), float64(height)))
+	return scale, scaledWidth, scaledHeight
+}
+
+// highestEntropyWindow slides the window over the image and returns the position with the highest entropy
+func highestEntropyWindow(img *image.NRGBA, window image.Rectangle) image.Point {
+	bounds := img.Bounds()
+	best := bounds.Min
+	bestEntropy := -1.0
+
+	for _, y := range windowPositions(bounds.Dy() - window.Dy()) {
+		for _, x := range windowPositions(bounds.Dx() - window.Dx()) {
+			position := bounds.Min.Add(image.Pt(x, y))
+			if e := entropy(img, window.Add(position)); e > bestEntropy {
+				best, bestEntropy = position, e
+			}
+		}
+	}
+
+	return best.Sub(bounds.Min)
+}
+
+// windowPositions returns the evenly spaced positions of a window which can move by slack pixels
+func windowPositions(slack int) []int {
+	if slack <= 0 {
+		return []int{0}
+	}
+
+	steps := entropyCropSteps
+	if slack < steps {
+		steps = slack
+	}
+
+	positions := make([]int, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		positions = append(positions, slack*i/steps)
+	}
+	return positions
+}
+
+// entropy returns the Shannon entropy of the luminance of the pixels in the rectangle
+func entropy(img *image.NRGBA, r image.Rectangle) float64 {
+	stride := int(math.Max(math.Sqrt(float64(r.Dx()*r.Dy())/entropyCropSamples), 1))
+
+	var histogram [256]int
+	total := 0
+	for y := r.Min.Y; y < r.Max.Y; y += stride {
+		for x := r.Min.X; x < r.Max.X; x += stride {
+			c := img.NRGBAAt(x, y)
+			histogram[luminance(c.R, c.G, c.B)]++
+			total++
+		}
+	}
+
+	result := 0.0
+	for _, count := range histogram {
+		if count > 0 {
+			p := float64(count) / float64(total)
+			result -= p * math.Log2(p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewSmartCrop(t *testing.T) {
+	name := NewSmartCrop().Name()
+	assert.Equal(t, "smartCrop", name)
+}
+
+func TestSmartCrop_Name(t *testing.T) {
+	c := smartCrop{}
+	assert.Equal(t, "smartCrop", c.Name())
+}
+
+func TestSmartCrop_CreateOptions(t *testing.T) {
+	c := smartCrop{width: 800, height: 600}
+	options, _ := c.CreateOptions(nil)
+
+	assert.Equal(t, 800, options.Width)
+	assert.Equal(t, 600, options.Height)
+	assert.True(t, options.Crop)
+	assert.Equal(t, bimg.GravitySmart, options.Gravity)
+}
+
+func TestSmartCrop_CanBeMerged(t *testing.T) {
+	c := smartCrop{}
+	self := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravitySmart, Crop: true}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravitySmart, Crop: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 100, Height: 350, Crop: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 225, Height: 365, Crop: true}, self))
+}
+
+func TestSmartCrop_Merge(t *testing.T) {
+	c := smartCrop{}
+	self := &bimg.Options{Width: 100, Height: 350, Gravity: bimg.GravitySmart, Crop: true}
+
+	merged := c.Merge(&bimg.Options{Quality: 80}, self)
+
+	assert.Equal(t, &bimg.Options{Quality: 80, Width: 100, Height: 350, Gravity: bimg.GravitySmart, Crop: true}, merged)
+}
+
+func TestSmartCropSupported(t *testing.T) {
+	assert.Equal(t, bimg.VipsMajorVersion > 8 || bimg.VipsMinorVersion >= 5, smartCropSupported())
+}
+
+func TestSmartCrop_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewSmartCrop, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "two args",
+		Args: []interface{}{800.0, 600.0},
+		Err:  !smartCropSupported(),
+	}, {
+		Msg:  "zero width",
+		Args: []interface{}{0.0, 600.0},
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{800.0, 600.0, North},
+		Err:  true,
+	}})
+}
+
+// detailedImage is gray, but the columns from detailFrom on, which are full of details
+func detailedImage(width, height, detailFrom int) *bimg.Image {
+	pixels := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(128)
+			if x >= detailFrom {
+				v = uint8(x*37 + y*91)
+			}
+			pixels.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	buf, _ := encodePixels(pixels)
+	return bimg.NewImage(buf)
+}
+
+func TestEntropyCropOptions(t *testing.T) {
+	options, err := entropyCropOptions(detailedImage(40, 10, 30), bimg.ImageSize{Width: 40, Height: 10}, 10, 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &bimg.Options{Width: 40, Height: 10, Force: true, Left: 30, Top: 0, AreaWidth: 10, AreaHeight: 10}, options)
+}
+
+func TestEntropyCropOptions_Resized(t *testing.T) {
+	options, err := entropyCropOptions(detailedImage(80, 20, 60), bimg.ImageSize{Width: 80, Height: 20}, 10, 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &bimg.Options{Width: 40, Height: 10, Force: true, Left: 30, Top: 0, AreaWidth: 10, AreaHeight: 10}, options)
+}
+
+func TestEntropyCropOptions_Small(t *testing.T) {
+	options, err := entropyCropOptions(detailedImage(8, 8, 4), bimg.ImageSize{Width: 8, Height: 8}, 10, 10)
+
+	assert.Nil(t, err)
+	assert.True(t, options.Crop)
+	assert.Equal(t, 0, options.AreaWidth)
+}
+
+func TestWindowPositions(t *testing.T) {
+	assert.Equal(t, []int{0}, windowPositions(0))
+	assert.Equal(t, []int{0, 1, 2}, windowPositions(2))
+	assert.Len(t, windowPositions(100), entropyCropSteps+1)
+	assert.Equal(t, 100, windowPositions(100)[entropyCropSteps])
+}
@@ -0,0 +1,175 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// StripMetadataName is the name of the filter
+const StripMetadataName = "stripMetadata"
+
+var (
+	errMalformedJPEG = errors.New("malformed JPEG, the metadata cannot be stripped")
+	errMalformedPNG  = errors.New("malformed PNG, the metadata cannot be stripped")
+	pngSignature     = []byte("\x89PNG\r\n\x1a\n")
+	iccProfileHeader = []byte("ICC_PROFILE\x00")
+)
+
+// stripMetadataFilter removes the metadata of the image, like the EXIF and the GPS tags. libvips removes the ICC profile
+// together with the rest, so when the profile is kept the metadata is removed from the encoded image instead
+type stripMetadataFilter struct {
+	keepProfile bool
+}
+
+// NewStripMetadata creates a new filter of this type
+func NewStripMetadata() filters.Spec {
+	return &stripMetadataFilter{}
+}
+
+func (f *stripMetadataFilter) Name() string {
+	return StripMetadataName
+}
+
+func (f *stripMetadataFilter) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for strip metadata ", f)
+
+	return &bimg.Options{StripMetadata: !f.keepProfile}, nil
+}
+
+func (f *stripMetadataFilter) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//the metadata only concerns the encoding of the image
+	return true
+}
+
+func (f *stripMetadataFilter) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.StripMetadata = other.StripMetadata || self.StripMetadata
+	return other
+}
+
+func (f *stripMetadataFilter) CreateFilter(args []interface{}) (filters.Filter, error) {
+	if len(args) > 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	s := &stripMetadataFilter{}
+
+	if len(args) == 1 {
+		var err error
+		s.keepProfile, err = parse.EskipBoolArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func (f *stripMetadataFilter) Request(ctx filters.FilterContext) {}
+
+func (f *stripMetadataFilter) Response(ctx filters.FilterContext) {
+	if err := HandleImageResponse(ctx, f); err != nil {
+		return
+	}
+
+	if f.keepProfile {
+		ctx.StateBag()[skropStripKeepingProfile] = true
+	}
+}
+
+// stripMetadataKeepingProfile removes the metadata of the encoded image, except the ICC profile.
+// Only JPEG and PNG are supported, the other types are left untouched, as libvips would remove the profile too
+func stripMetadataKeepingProfile(buf []byte) ([]byte, error) {
+	switch bimg.DetermineImageType(buf) {
+	case bimg.JPEG:
+		return stripJPEGMetadata(buf)
+	case bimg.PNG:
+		return stripPNGMetadata(buf)
+	default:
+		log.Warn("The metadata is kept, it can only be stripped keeping the ICC profile from JPEG and PNG images")
+		return buf, nil
+	}
+}
+
+// stripJPEGMetadata removes the application segments and the comments before the image data,
+// except the JFIF and Adobe segments needed to decode the image and the ICC profile
+func stripJPEGMetadata(buf []byte) ([]byte, error) {
+	if len(buf) < 2 || buf[0] != 0xFF || buf[1] != 0xD8 {
+		return nil, errMalformedJPEG
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(buf)))
+	out.Write(buf[:2])
+
+	for i := 2; ; {
+		if i+4 > len(buf) || buf[i] != 0xFF {
+			return nil, errMalformedJPEG
+		}
+
+		marker := buf[i+1]
+		//the start of scan is followed by the image data, without any more metadata
+		if marker == 0xDA {
+			out.Write(buf[i:])
+			return out.Bytes(), nil
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(buf[i+2:]))
+		if end > len(buf) {
+			return nil, errMalformedJPEG
+		}
+
+		if keepJPEGSegment(marker, buf[i+4:end]) {
+			out.Write(buf[i:end])
+		}
+		i = end
+	}
+}
+
+func keepJPEGSegment(marker byte, data []byte) bool {
+	switch {
+	case marker == 0xE2:
+		return bytes.HasPrefix(data, iccProfileHeader)
+	case marker == 0xE0 || marker == 0xEE:
+		return true
+	case marker >= 0xE1 && marker <= 0xEF, marker == 0xFE:
+		return false
+	default:
+		return true
+	}
+}
+
+// stripPNGMetadata removes the text, EXIF and time chunks of the image, keeping the iCCP chunk of the ICC profile
+func stripPNGMetadata(buf []byte) ([]byte, error) {
+	if !bytes.HasPrefix(buf, pngSignature) {
+		return nil, errMalformedPNG
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(buf)))
+	out.Write(pngSignature)
+
+	for i := len(pngSignature); i < len(buf); {
+		if i+8 > len(buf) {
+			return nil, errMalformedPNG
+		}
+
+		//length, type, data and CRC
+		end := i + 12 + int(binary.BigEndian.Uint32(buf[i:]))
+		if end > len(buf) || end < i {
+			return nil, errMalformedPNG
+		}
+
+		switch string(buf[i+4 : i+8]) {
+		case "tEXt", "zTXt", "iTXt", "eXIf", "tIME":
+		default:
+			out.Write(buf[i:end])
+		}
+		i = end
+	}
+
+	return out.Bytes(), nil
+}
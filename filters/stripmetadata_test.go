@@ -0,0 +1,197 @@
+package filters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+// gpsExif is an EXIF segment pointing to a GPS IFD with the latitude reference
+var gpsExif = []byte("Exif\x00\x00" +
+	"MM\x00\x2a\x00\x00\x00\x08" +
+	"\x00\x01\x88\x25\x00\x04\x00\x00\x00\x01\x00\x00\x00\x1a\x00\x00\x00\x00" +
+	"\x00\x01\x00\x01\x00\x02\x00\x00\x00\x02N\x00\x00\x00\x00\x00\x00\x00\x00")
+
+var iccProfile = append(append([]byte{}, iccProfileHeader...), "\x01\x01fake profile"...)
+
+// withSegments adds the segments after the start of image marker of the JPEG
+func withSegments(buf []byte, segments ...[]byte) []byte {
+	out := append([]byte{}, buf[:2]...)
+	for _, s := range segments {
+		out = append(out, s...)
+	}
+	return append(out, buf[2:]...)
+}
+
+func jpegSegment(marker byte, data []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(data)+2))
+	return append(segment, data...)
+}
+
+func pngChunk(name string, data []byte) []byte {
+	chunk := make([]byte, 8, len(data)+12)
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], name)
+	//the CRC is not checked when the chunks are stripped
+	return append(append(chunk, data...), 0, 0, 0, 0)
+}
+
+func TestNewStripMetadata(t *testing.T) {
+	name := NewStripMetadata().Name()
+	assert.Equal(t, "stripMetadata", name)
+}
+
+func TestStripMetadata_CreateOptions(t *testing.T) {
+	options, _ := (&stripMetadataFilter{}).CreateOptions(nil)
+	assert.True(t, options.StripMetadata)
+
+	options, _ = (&stripMetadataFilter{keepProfile: true}).CreateOptions(nil)
+	assert.False(t, options.StripMetadata)
+}
+
+func TestStripMetadata_CanBeMerged(t *testing.T) {
+	s := stripMetadataFilter{}
+	self := &bimg.Options{StripMetadata: true}
+
+	assert.True(t, s.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, s.CanBeMerged(&bimg.Options{Width: 100, Crop: true, Type: bimg.WEBP}, self))
+}
+
+func TestStripMetadata_Merge(t *testing.T) {
+	s := stripMetadataFilter{}
+
+	opt := s.Merge(&bimg.Options{Width: 100}, &bimg.Options{StripMetadata: true})
+	assert.True(t, opt.StripMetadata)
+	assert.Equal(t, 100, opt.Width)
+
+	//the metadata stripped by another filter stays stripped
+	opt = s.Merge(&bimg.Options{StripMetadata: true}, &bimg.Options{})
+	assert.True(t, opt.StripMetadata)
+}
+
+func TestStripMetadata_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewStripMetadata, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  false,
+	}, {
+		Msg:  "keep the profile",
+		Args: []interface{}{true},
+		Err:  false,
+	}, {
+		Msg:  "keep the profile as a string",
+		Args: []interface{}{"true"},
+		Err:  false,
+	}, {
+		Msg:  "not a boolean",
+		Args: []interface{}{"yes"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{true, false},
+		Err:  true,
+	}})
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	var img bytes.Buffer
+	jpeg.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 8, 8)), nil)
+
+	buf := withSegments(img.Bytes(),
+		jpegSegment(0xE1, gpsExif),
+		jpegSegment(0xE2, iccProfile),
+		jpegSegment(0xED, []byte("Photoshop 3.0\x00")),
+		jpegSegment(0xFE, []byte("a comment")))
+
+	result, err := stripJPEGMetadata(buf)
+	assert.Nil(t, err)
+
+	assert.False(t, bytes.Contains(result, []byte("Exif")), "the EXIF and its GPS tags should be removed")
+	assert.False(t, bytes.Contains(result, []byte("Photoshop")))
+	assert.False(t, bytes.Contains(result, []byte("a comment")))
+	assert.True(t, bytes.Contains(result, jpegSegment(0xE2, iccProfile)), "the ICC profile should be kept")
+
+	_, err = jpeg.Decode(bytes.NewReader(result))
+	assert.Nil(t, err)
+}
+
+func TestStripJPEGMetadata_Malformed(t *testing.T) {
+	_, err := stripJPEGMetadata([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF})
+	assert.NotNil(t, err)
+
+	_, err = stripJPEGMetadata([]byte("not a jpeg"))
+	assert.NotNil(t, err)
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	var img bytes.Buffer
+	png.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 8, 8)))
+
+	//the chunks are added after the header chunk
+	header := len(pngSignature) + 25
+	buf := append(append([]byte{}, img.Bytes()[:header]...), pngChunk("iCCP", []byte("profile"))...)
+	buf = append(buf, pngChunk("eXIf", gpsExif[6:])...)
+	buf = append(buf, pngChunk("tEXt", []byte("Comment\x00secret"))...)
+	buf = append(buf, img.Bytes()[header:]...)
+
+	result, err := stripPNGMetadata(buf)
+	assert.Nil(t, err)
+
+	assert.False(t, bytes.Contains(result, []byte("eXIf")), "the EXIF and its GPS tags should be removed")
+	assert.False(t, bytes.Contains(result, []byte("secret")))
+	assert.True(t, bytes.Contains(result, pngChunk("iCCP", []byte("profile"))), "the ICC profile should be kept")
+	assert.Equal(t, img.Bytes(), bytes.Replace(result, pngChunk("iCCP", []byte("profile")), nil, 1))
+}
+
+func TestStripMetadataKeepingProfile_OtherTypes(t *testing.T) {
+	buf := []byte("RIFF\x00\x00\x00\x00WEBPVP8 not really encoded")
+
+	result, err := stripMetadataKeepingProfile(buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, buf, result, "the image should be left untouched")
+}
+
+func TestStripMetadata_Response(t *testing.T) {
+	original, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+	image := bimg.NewImage(withSegments(original, jpegSegment(0xE1, gpsExif)))
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = image
+	fc.FStateBag[hasMergedFilters] = false
+
+	f, _ := NewStripMetadata().CreateFilter(nil)
+	f.Response(fc)
+	FinalizeResponse(fc)
+
+	result := readResultImage(fc.Response().Body, t)
+
+	assert.Equal(t, "jpeg", result.Type())
+	assert.False(t, bytes.Contains(result.Image(), []byte("Exif")), "the GPS tags should be removed")
+}
+
+func TestStripMetadata_Response_KeepProfile(t *testing.T) {
+	original, _ := bimg.Read(imagefiltertest.LandscapeImageFile)
+	image := bimg.NewImage(withSegments(original, jpegSegment(0xE1, gpsExif), jpegSegment(0xE2, iccProfile)))
+
+	fc := createDefaultContext(t, "doesNotMatter.com")
+	fc.FStateBag[skropImage] = image
+	fc.FStateBag[hasMergedFilters] = false
+
+	f, _ := NewStripMetadata().CreateFilter([]interface{}{true})
+	f.Response(fc)
+	FinalizeResponse(fc)
+
+	result := readResultImage(fc.Response().Body, t)
+
+	assert.False(t, bytes.Contains(result.Image(), []byte("Exif")), "the GPS tags should be removed")
+	assert.True(t, bytes.Contains(result.Image(), []byte("ICC_PROFILE")), "the ICC profile should be kept")
+}
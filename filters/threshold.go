@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"image"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ThresholdName is the name of the filter
+const ThresholdName = "threshold"
+
+type threshold struct {
+	level int
+}
+
+// NewThreshold creates a new filter of this type
+func NewThreshold() filters.Spec {
+	return &threshold{}
+}
+
+func (f *threshold) Name() string {
+	return ThresholdName
+}
+
+func (f *threshold) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for threshold ", f)
+
+	//the level is spread over the 257 cuts around the 256 luminances, so a level of 0 makes the whole image
+	//white and a level of 255 makes it black. The pixels with a luminance at or above the cut become white
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			value := uint8(0)
+			if int(luminance(row[i], row[i+1], row[i+2]))*255 >= f.level*256 {
+				value = 255
+			}
+			row[i], row[i+1], row[i+2] = value, value, value
+		}
+	}
+
+	return img, nil
+}
+
+func (f *threshold) CanBeMerged(other PixelFilter) bool {
+	o, ok := other.(*threshold)
+	return ok && o.level == f.level
+}
+
+func (f *threshold) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *threshold) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	t := &threshold{}
+
+	t.level, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if t.level < 0 || t.level > 255 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return t, nil
+}
+
+func (f *threshold) Request(ctx filters.FilterContext) {}
+
+func (f *threshold) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
+
+// luminance returns the perceived brightness of the color, as defined by ITU-R BT.601
+func luminance(r, g, b uint8) uint8 {
+	return clampUint8(0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b))
+}
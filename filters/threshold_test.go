@@ -0,0 +1,112 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewThreshold(t *testing.T) {
+	name := NewThreshold().Name()
+	assert.Equal(t, "threshold", name)
+}
+
+func TestThreshold_Name(t *testing.T) {
+	c := threshold{}
+	assert.Equal(t, "threshold", c.Name())
+}
+
+func TestThreshold_TransformPixels(t *testing.T) {
+	f := threshold{level: 128}
+	img := uniformImage(color.NRGBA{R: 200, G: 200, B: 200, A: 255})
+	img.SetNRGBA(0, 0, color.NRGBA{R: 20, G: 100, B: 20, A: 100})
+
+	result, err := f.TransformPixels(img)
+
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, result.NRGBAAt(1, 1))
+	assert.Equal(t, color.NRGBA{R: 0, G: 0, B: 0, A: 100}, result.NRGBAAt(0, 0))
+}
+
+func TestThreshold_TransformPixels_Limits(t *testing.T) {
+	white, _ := (&threshold{level: 0}).TransformPixels(uniformImage(color.NRGBA{A: 255}))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, white.NRGBAAt(0, 0))
+
+	black, _ := (&threshold{level: 255}).TransformPixels(uniformImage(color.NRGBA{R: 254, G: 254, B: 254, A: 255}))
+	assert.Equal(t, color.NRGBA{A: 255}, black.NRGBAAt(0, 0))
+}
+
+func TestThreshold_TransformPixels_MaxLevelOnWhite(t *testing.T) {
+	black, _ := (&threshold{level: 255}).TransformPixels(uniformImage(color.NRGBA{R: 255, G: 255, B: 255, A: 255}))
+	assert.Equal(t, color.NRGBA{A: 255}, black.NRGBAAt(0, 0), "the level 255 should make even the pure white black")
+
+	white, _ := (&threshold{level: 254}).TransformPixels(uniformImage(color.NRGBA{R: 255, G: 255, B: 255, A: 255}))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, white.NRGBAAt(0, 0))
+}
+
+func TestThreshold_TransformPixels_MinLevelOnBlack(t *testing.T) {
+	white, _ := (&threshold{level: 0}).TransformPixels(uniformImage(color.NRGBA{A: 255}))
+	assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, white.NRGBAAt(0, 0), "the level 0 should make even the pure black white")
+
+	black, _ := (&threshold{level: 1}).TransformPixels(uniformImage(color.NRGBA{A: 255}))
+	assert.Equal(t, color.NRGBA{A: 255}, black.NRGBAAt(0, 0))
+}
+
+func TestThreshold_CanBeMerged(t *testing.T) {
+	f := threshold{level: 100}
+	assert.True(t, f.CanBeMerged(&threshold{level: 100}))
+	assert.False(t, f.CanBeMerged(&threshold{level: 150}))
+	assert.False(t, f.CanBeMerged(&negate{active: true}))
+}
+
+func TestThreshold_Merge(t *testing.T) {
+	f := threshold{level: 100}
+
+	merged := f.Merge(&threshold{level: 100})
+
+	assert.Equal(t, 100, merged.(*threshold).level)
+}
+
+func TestThreshold_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewThreshold, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{128.0},
+		Err:  false,
+	}, {
+		Msg:  "lowest level",
+		Args: []interface{}{0.0},
+		Err:  false,
+	}, {
+		Msg:  "highest level",
+		Args: []interface{}{255.0},
+		Err:  false,
+	}, {
+		Msg:  "level too high",
+		Args: []interface{}{256.0},
+		Err:  true,
+	}, {
+		Msg:  "negative level",
+		Args: []interface{}{-1.0},
+		Err:  true,
+	}, {
+		Msg:  "not an int",
+		Args: []interface{}{12.5},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{12.0, 13.0},
+		Err:  true,
+	}})
+}
+
+func TestLuminance(t *testing.T) {
+	assert.Equal(t, uint8(0), luminance(0, 0, 0))
+	assert.Equal(t, uint8(255), luminance(255, 255, 255))
+	assert.Equal(t, uint8(150), luminance(0, 255, 0))
+}
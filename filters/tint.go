@@ -0,0 +1,92 @@
+package filters
+
+import (
+	"image"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// TintName is the name of the filter
+const TintName = "tint"
+
+type tint struct {
+	color    bimg.Color
+	strength float64
+}
+
+// NewTint creates a new filter of this type
+func NewTint() filters.Spec {
+	return &tint{}
+}
+
+func (f *tint) Name() string {
+	return TintName
+}
+
+func (f *tint) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for tint ", f)
+
+	if f.strength == 0 {
+		return img, nil
+	}
+
+	//the color is multiplied by the gray version of the image, and blended over it by the strength. The pixel
+	//filters are applied after the crops and the resizes before them, so the layer covers the final image
+	r, g, b := float64(f.color.R)/255, float64(f.color.G)/255, float64(f.color.B)/255
+	for y := 0; y < img.Rect.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+img.Rect.Dx()*4]
+		for i := 0; i < len(row); i += 4 {
+			gray := float64(luminance(row[i], row[i+1], row[i+2]))
+			row[i] = clampUint8(float64(row[i])*(1-f.strength) + gray*r*f.strength)
+			row[i+1] = clampUint8(float64(row[i+1])*(1-f.strength) + gray*g*f.strength)
+			row[i+2] = clampUint8(float64(row[i+2])*(1-f.strength) + gray*b*f.strength)
+		}
+	}
+
+	return img, nil
+}
+
+// CanBeMerged refuses the other tints, as a tint changes the gray version of the image the next one is based on
+func (f *tint) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *tint) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *tint) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 2 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	t := &tint{}
+
+	c, err := parse.EskipColorArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	t.color = bimgColor(c)
+
+	t.strength, err = parse.EskipFloatArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if t.strength < 0 || t.strength > 1 {
+		log.Errorf("Failed to create the tint filter, the strength %v is not between 0 and 1", t.strength)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return t, nil
+}
+
+func (f *tint) Request(ctx filters.FilterContext) {}
+
+func (f *tint) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
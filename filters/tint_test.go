@@ -0,0 +1,93 @@
+package filters
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewTint(t *testing.T) {
+	name := NewTint().Name()
+	assert.Equal(t, "tint", name)
+}
+
+func TestTint_TransformPixels_NoStrength(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+
+	result, err := (&tint{color: bimg.Color{R: 255}, strength: 0}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result)
+}
+
+func TestTint_TransformPixels_FullStrength(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 128}
+	gray := luminance(c.R, c.G, c.B)
+
+	result, err := (&tint{color: bimg.Color{R: 255, G: 0, B: 0}, strength: 1}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	//a monochrome red image, as bright as the gray version of the image
+	assert.Equal(t, color.NRGBA{R: gray, G: 0, B: 0, A: 128}, result.NRGBAAt(1, 1))
+}
+
+func TestTint_TransformPixels_HalfStrength(t *testing.T) {
+	c := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	gray := float64(luminance(c.R, c.G, c.B))
+
+	result, err := (&tint{color: bimg.Color{R: 255, G: 255, B: 0}, strength: 0.5}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	p := result.NRGBAAt(0, 0)
+	assert.Equal(t, clampUint8(200*0.5+gray*0.5), p.R)
+	assert.Equal(t, clampUint8(100*0.5+gray*0.5), p.G)
+	assert.Equal(t, clampUint8(50*0.5), p.B)
+}
+
+func TestTint_CanBeMerged(t *testing.T) {
+	f := tint{color: bimg.Color{R: 255}, strength: 0.5}
+	assert.False(t, f.CanBeMerged(&tint{color: bimg.Color{R: 255}, strength: 0.5}))
+	assert.False(t, f.CanBeMerged(&saturation{factor: 0.5}))
+}
+
+func TestTint_AfterCrop(t *testing.T) {
+	fc := createDefaultContext(t, "doesNotMatter.com")
+
+	HandleImageResponse(fc, &crop{width: 100, height: 100, cropType: Center})
+	HandlePixelResponse(fc, &tint{color: bimg.Color{R: 255}, strength: 0.5})
+
+	//the crop is merged in the options applied before the pixels are tinted
+	assert.Equal(t, true, fc.FStateBag[hasMergedFilters])
+	assert.Equal(t, []PixelFilter{&tint{color: bimg.Color{R: 255}, strength: 0.5}}, fc.FStateBag[skropPixelFilters])
+}
+
+func TestTint_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewTint, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "color and strength",
+		Args: []interface{}{"#ff8800", 0.5},
+		Err:  false,
+	}, {
+		Msg:  "invalid color",
+		Args: []interface{}{"orange", 0.5},
+		Err:  true,
+	}, {
+		Msg:  "strength bigger than 1",
+		Args: []interface{}{"#ff8800", 1.5},
+		Err:  true,
+	}, {
+		Msg:  "negative strength",
+		Args: []interface{}{"#ff8800", -0.5},
+		Err:  true,
+	}, {
+		Msg:  "more than two args",
+		Args: []interface{}{"#ff8800", 0.5, 1.0},
+		Err:  true,
+	}})
+}
@@ -40,7 +40,7 @@ func (t *transformFromQueryParams) CreateOptions(ctx *ImageFilterContext) (*bimg
 		return &bimg.Options{}, nil
 	}
 
-	imgSize, err := ctx.Image.Size()
+	imgSize, err := ctx.ImageSize()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,75 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// TrimName is the name of the filter
+	TrimName     = "trim"
+	maxThreshold = 255
+)
+
+type trim struct {
+	threshold float64
+}
+
+// NewTrim creates a new filter of this type
+func NewTrim() filters.Spec {
+	return &trim{}
+}
+
+func (f *trim) Name() string {
+	return TrimName
+}
+
+func (f *trim) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for trim ", f)
+
+	//the borders are compared with the background, which is white by default
+	return &bimg.Options{
+		Trim:      true,
+		Threshold: f.threshold}, nil
+}
+
+func (f *trim) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//bimg trims after resizing and before the watermarks, so the trim cannot be merged with them
+	return orientationCanBeMerged(other) && !other.Crop && !other.Embed && other.Top == 0 && other.Left == 0 &&
+		other.Watermark.Text == "" && (!other.Trim || other.Threshold == self.Threshold)
+}
+
+func (f *trim) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Trim = self.Trim
+	other.Threshold = self.Threshold
+	return other
+}
+
+func (f *trim) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	t := &trim{}
+
+	t.threshold, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if t.threshold < 0 || t.threshold > maxThreshold {
+		log.Errorf("Failed to create the trim filter, the threshold %v is not between 0 and %d", t.threshold, maxThreshold)
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return t, nil
+}
+
+func (f *trim) Request(ctx filters.FilterContext) {}
+
+func (f *trim) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
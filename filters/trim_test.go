@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewTrim(t *testing.T) {
+	name := NewTrim().Name()
+	assert.Equal(t, "trim", name)
+}
+
+func TestTrim_Name(t *testing.T) {
+	c := trim{}
+	assert.Equal(t, "trim", c.Name())
+}
+
+func TestTrim_CreateOptions(t *testing.T) {
+	c := trim{threshold: 10}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.PNGImage()))
+
+	assert.True(t, options.Trim)
+	assert.Equal(t, 10.0, options.Threshold)
+}
+
+func TestTrim_TrimsWhiteBorder(t *testing.T) {
+	//a black 20x10 rectangle on a white 40x30 canvas
+	pixels := image.NewNRGBA(image.Rect(0, 0, 40, 30))
+	draw.Draw(pixels, pixels.Bounds(), image.NewUniform(color.White), image.ZP, draw.Src)
+	draw.Draw(pixels, image.Rect(5, 10, 25, 20), image.NewUniform(color.Black), image.ZP, draw.Src)
+	buf, err := encodePixels(pixels)
+	assert.Nil(t, err)
+
+	c := trim{threshold: 10}
+	options, _ := c.CreateOptions(buildParameters(nil, bimg.NewImage(buf)))
+	result, err := transformImage(bimg.NewImage(buf), options, false)
+	assert.Nil(t, err)
+
+	size, err := bimg.NewImage(result).Size()
+	assert.Nil(t, err)
+	assert.Equal(t, 20, size.Width)
+	assert.Equal(t, 10, size.Height)
+}
+
+func TestTrim_CanBeMerged(t *testing.T) {
+	self := &bimg.Options{Trim: true, Threshold: 10}
+
+	assert.True(t, (&trim{}).CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, (&trim{}).CanBeMerged(&bimg.Options{Quality: 80, Type: bimg.PNG}, self))
+	assert.True(t, (&trim{}).CanBeMerged(&bimg.Options{Trim: true, Threshold: 10}, self))
+	assert.False(t, (&trim{}).CanBeMerged(&bimg.Options{Trim: true, Threshold: 20}, self))
+	assert.False(t, (&trim{}).CanBeMerged(&bimg.Options{Width: 200, Height: 100, Crop: true}, self))
+	assert.False(t, (&trim{}).CanBeMerged(&bimg.Options{Width: 200}, self))
+	assert.False(t, (&trim{}).CanBeMerged(&bimg.Options{Top: 10, AreaWidth: 20, AreaHeight: 20}, self))
+}
+
+func TestTrim_Merge(t *testing.T) {
+	other := &bimg.Options{Quality: 80}
+	merged := (&trim{}).Merge(other, &bimg.Options{Trim: true, Threshold: 10})
+
+	assert.True(t, merged.Trim)
+	assert.Equal(t, 10.0, merged.Threshold)
+	assert.Equal(t, 80, merged.Quality)
+}
+
+func TestTrim_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewTrim, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{10.0},
+		Err:  false,
+	}, {
+		Msg:  "zero threshold",
+		Args: []interface{}{0.0},
+		Err:  false,
+	}, {
+		Msg:  "negative threshold",
+		Args: []interface{}{-1.0},
+		Err:  true,
+	}, {
+		Msg:  "threshold too big",
+		Args: []interface{}{256.0},
+		Err:  true,
+	}, {
+		Msg:  "not a number",
+		Args: []interface{}{"10"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{10.0, 5.0},
+		Err:  true,
+	}})
+}
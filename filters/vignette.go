@@ -0,0 +1,90 @@
+package filters
+
+import (
+	"image"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// VignetteName is the name of the filter
+const VignetteName = "vignette"
+
+type vignette struct {
+	strength float64
+}
+
+// NewVignette creates a new filter of this type
+func NewVignette() filters.Spec {
+	return &vignette{}
+}
+
+func (f *vignette) Name() string {
+	return VignetteName
+}
+
+func (f *vignette) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for vignette ", f)
+
+	if f.strength == 0 {
+		return img, nil
+	}
+
+	//the radial gradient goes from 1 in the centre to 1 - strength in the corners and multiplies the colors
+	bounds := img.Bounds()
+	cx, cy := float64(bounds.Dx())/2, float64(bounds.Dy())/2
+	maxDistance := math.Hypot(cx, cy)
+
+	for y := 0; y < bounds.Dy(); y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+bounds.Dx()*4]
+		for x := 0; x < bounds.Dx(); x++ {
+			distance := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy) / maxDistance
+			factor := 1 - f.strength*distance*distance
+
+			i := x * 4
+			row[i] = clampUint8(float64(row[i]) * factor)
+			row[i+1] = clampUint8(float64(row[i+1]) * factor)
+			row[i+2] = clampUint8(float64(row[i+2]) * factor)
+		}
+	}
+
+	return img, nil
+}
+
+func (f *vignette) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *vignette) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *vignette) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	v := &vignette{}
+
+	v.strength, err = parse.EskipFloatArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if v.strength < 0 {
+		v.strength = 0
+	} else if v.strength > 1.0 {
+		v.strength = 1
+	}
+
+	return v, nil
+}
+
+func (f *vignette) Request(ctx filters.FilterContext) {}
+
+func (f *vignette) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
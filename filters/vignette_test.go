@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewVignette(t *testing.T) {
+	name := NewVignette().Name()
+	assert.Equal(t, "vignette", name)
+}
+
+func TestVignette_Name(t *testing.T) {
+	c := vignette{}
+	assert.Equal(t, "vignette", c.Name())
+}
+
+func TestVignette_TransformPixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+
+	result, err := (&vignette{strength: 0.5}).TransformPixels(img)
+
+	assert.Nil(t, err)
+	centre := result.NRGBAAt(10, 5)
+	corner := result.NRGBAAt(0, 0)
+	edge := result.NRGBAAt(0, 5)
+	assert.True(t, centre.R > 195, "the centre should not change, %v", centre)
+	assert.True(t, corner.R < edge.R && edge.R < centre.R, "the image should be darker toward the edges")
+	assert.InDelta(t, 112, int(corner.R), 2)
+	assert.Equal(t, uint8(200), corner.A, "the alpha should not change")
+}
+
+func TestVignette_TransformPixels_NoStrength(t *testing.T) {
+	c := color.NRGBA{R: 10, G: 20, B: 30, A: 255}
+
+	result, err := (&vignette{strength: 0}).TransformPixels(uniformImage(c))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uniformImage(c), result)
+}
+
+func TestVignette_CanBeMerged(t *testing.T) {
+	f := vignette{strength: 0.5}
+	assert.False(t, f.CanBeMerged(&vignette{strength: 0.5}))
+}
+
+func TestVignette_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewVignette, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{0.5},
+		Err:  false,
+	}, {
+		Msg:  "strength out of range",
+		Args: []interface{}{1.5},
+		Err:  false,
+	}, {
+		Msg:  "not a number",
+		Args: []interface{}{"0.5"},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{0.5, 0.5},
+		Err:  true,
+	}})
+}
+
+func TestVignette_CreateFilter_Clamp(t *testing.T) {
+	f, _ := NewVignette().CreateFilter([]interface{}{1.5})
+	assert.Equal(t, 1.0, f.(*vignette).strength)
+
+	f, _ = NewVignette().CreateFilter([]interface{}{-0.5})
+	assert.Equal(t, 0.0, f.(*vignette).strength)
+}
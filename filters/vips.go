@@ -0,0 +1,65 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+)
+
+const (
+	// DefaultVipsCacheMax is the number of libvips operations cached by default. bimg caches 500 of them, which is
+	// a lot as skrop rarely applies the same operation twice on the same image
+	DefaultVipsCacheMax = 100
+	// DefaultVipsCacheMaxMemory is the memory in bytes used by default by the cache of libvips
+	DefaultVipsCacheMaxMemory = 50 << 20
+)
+
+// VipsOptions configures the cache of libvips
+type VipsOptions struct {
+	// CacheMax is the number of operations kept in the cache, DefaultVipsCacheMax if 0
+	CacheMax int
+	// CacheMaxMemory is the memory in bytes used by the cache, DefaultVipsCacheMaxMemory if 0
+	CacheMaxMemory int
+}
+
+// vipsCache sets the limits of the cache of libvips
+type vipsCache interface {
+	SetMax(operations int)
+	SetMaxMemory(bytes int)
+}
+
+type bimgCache struct{}
+
+func (bimgCache) SetMax(operations int)  { bimg.VipsCacheSetMax(operations) }
+func (bimgCache) SetMaxMemory(bytes int) { bimg.VipsCacheSetMaxMem(bytes) }
+
+var vips vipsCache = bimgCache{}
+
+// InitVips configures libvips with the options, the ones which are not set getting their default. It needs to be
+// called before the routes are served
+func InitVips(o VipsOptions) {
+	if o.CacheMax == 0 {
+		o.CacheMax = DefaultVipsCacheMax
+	}
+	if o.CacheMaxMemory == 0 {
+		o.CacheMaxMemory = DefaultVipsCacheMaxMemory
+	}
+
+	SetVipsCacheMax(o.CacheMax)
+	SetVipsMaxMemory(o.CacheMaxMemory)
+}
+
+// SetVipsCacheMax limits the number of operations kept in the cache of libvips, 0 disabling the cache
+func SetVipsCacheMax(operations int) {
+	if operations < 0 {
+		return
+	}
+	vips.SetMax(operations)
+}
+
+// SetVipsMaxMemory limits the memory in bytes used by the cache of libvips, the oldest operations being dropped
+// from the cache when it is reached
+func SetVipsMaxMemory(bytes int) {
+	if bytes < 0 {
+		return
+	}
+	vips.SetMaxMemory(bytes)
+}
@@ -0,0 +1,53 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVipsCache struct {
+	max       []int
+	maxMemory []int
+}
+
+func (c *fakeVipsCache) SetMax(operations int)  { c.max = append(c.max, operations) }
+func (c *fakeVipsCache) SetMaxMemory(bytes int) { c.maxMemory = append(c.maxMemory, bytes) }
+
+func withFakeVipsCache() (*fakeVipsCache, func()) {
+	fake := &fakeVipsCache{}
+	previous := vips
+	vips = fake
+	return fake, func() { vips = previous }
+}
+
+func TestInitVips_Defaults(t *testing.T) {
+	fake, restore := withFakeVipsCache()
+	defer restore()
+
+	InitVips(VipsOptions{})
+
+	assert.Equal(t, []int{DefaultVipsCacheMax}, fake.max)
+	assert.Equal(t, []int{DefaultVipsCacheMaxMemory}, fake.maxMemory)
+}
+
+func TestInitVips_Options(t *testing.T) {
+	fake, restore := withFakeVipsCache()
+	defer restore()
+
+	InitVips(VipsOptions{CacheMax: 20, CacheMaxMemory: 10 << 20})
+
+	assert.Equal(t, []int{20}, fake.max)
+	assert.Equal(t, []int{10 << 20}, fake.maxMemory)
+}
+
+func TestSetVipsCache_Negative(t *testing.T) {
+	fake, restore := withFakeVipsCache()
+	defer restore()
+
+	SetVipsCacheMax(-1)
+	SetVipsMaxMemory(-1)
+
+	assert.Nil(t, fake.max)
+	assert.Nil(t, fake.maxMemory)
+}
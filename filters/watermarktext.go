@@ -0,0 +1,258 @@
+package filters
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+const (
+	// WatermarkTextName is the name of the filter
+	WatermarkTextName = "watermarkText"
+	//distance of the text from the borders of the image
+	watermarkTextMargin = 10
+	//bimg draws the text this many pixels away from the top left corner
+	vipsTextOffset = 100
+	//with 72 dpi the size of the font is in pixels
+	defaultWatermarkTextDPI  = 72
+	defaultWatermarkTextFont = "sans"
+	defaultWatermarkTextSize = 10
+)
+
+type watermarkText struct {
+	text              string
+	opacity           float64
+	verticalGravity   bimg.Gravity
+	horizontalGravity bimg.Gravity
+	dpi               int
+	font              string
+	color             color.NRGBA
+}
+
+// NewWatermarkText creates a new filter of this type
+func NewWatermarkText() filters.Spec {
+	return &watermarkText{}
+}
+
+func (f *watermarkText) Name() string {
+	return WatermarkTextName
+}
+
+func (f *watermarkText) TransformPixels(img *image.NRGBA) (*image.NRGBA, error) {
+	log.Debug("Transform pixels for watermark text ", f)
+
+	//bimg tiles the text or draws it in the top left corner, so the text is rendered by libvips and placed here
+	bounds := img.Bounds()
+	mask, err := renderText(f.text, f.font, f.dpi, bounds.Dx()-2*watermarkTextMargin, bounds.Dy())
+	if err != nil {
+		return nil, err
+	}
+
+	stampText(img, mask, f.color, f.opacity, f.horizontalGravity, f.verticalGravity)
+	return img, nil
+}
+
+// renderText renders the text with libvips, wrapped in the given width, and returns it as an alpha mask
+func renderText(text, font string, dpi, width, height int) (*image.Alpha, error) {
+	if width < 1 {
+		width = 1
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, width+vipsTextOffset, height+vipsTextOffset))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.Black), image.ZP, draw.Src)
+	buf, err := encodePixels(canvas)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err = processImage(bimg.NewImage(buf), bimg.Options{
+		Type: bimg.PNG,
+		Watermark: bimg.Watermark{
+			Text:        text,
+			Font:        font,
+			DPI:         dpi,
+			Width:       width,
+			Margin:      vipsTextOffset,
+			Opacity:     1,
+			NoReplicate: true,
+			Background:  bimg.Color{R: 255, G: 255, B: 255},
+		}})
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := decodePixels(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return textMask(rendered), nil
+}
+
+// textMask turns the white text rendered on black into an alpha mask, cut to the bounds of the text
+func textMask(img *image.NRGBA) *image.Alpha {
+	bounds := img.Bounds()
+	textBounds := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+			if luminance(c.R, c.G, c.B) > 0 {
+				textBounds = textBounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+
+	mask := image.NewAlpha(textBounds.Sub(textBounds.Min))
+	for y := textBounds.Min.Y; y < textBounds.Max.Y; y++ {
+		for x := textBounds.Min.X; x < textBounds.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+			mask.SetAlpha(x-textBounds.Min.X, y-textBounds.Min.Y, color.Alpha{A: luminance(c.R, c.G, c.B)})
+		}
+	}
+	return mask
+}
+
+// stampText paints the text mask on the image with the color and opacity, placing it based on the gravity
+func stampText(img *image.NRGBA, mask *image.Alpha, c color.NRGBA, opacity float64,
+	horizontal, vertical bimg.Gravity) {
+
+	faded := image.NewAlpha(mask.Bounds())
+	for i, a := range mask.Pix {
+		faded.Pix[i] = clampUint8(float64(a) * opacity)
+	}
+
+	bounds := img.Bounds()
+	position := bounds.Min.Add(image.Pt(
+		watermarkTextMargin+gravityOffset(horizontal, bimg.GravityWest, bimg.GravityEast,
+			bounds.Dx()-2*watermarkTextMargin-mask.Rect.Dx()),
+		watermarkTextMargin+gravityOffset(vertical, bimg.GravityNorth, bimg.GravitySouth,
+			bounds.Dy()-2*watermarkTextMargin-mask.Rect.Dy())))
+
+	draw.DrawMask(img, mask.Rect.Add(position), image.NewUniform(c), image.ZP, faded, image.ZP, draw.Over)
+}
+
+func (f *watermarkText) CanBeMerged(other PixelFilter) bool {
+	return false
+}
+
+func (f *watermarkText) Merge(other PixelFilter) PixelFilter {
+	return f
+}
+
+func (f *watermarkText) CreateFilter(args []interface{}) (filters.Filter, error) {
+	//watermarkText(<text>, <opacity>, <gravity>)
+	//watermarkText(<text>, <opacity>, <gravity>, <dpi>)
+	//watermarkText(<text>, <opacity>, <gravity>, <font>, <size>, <color>)
+	var err error
+
+	if len(args) != 3 && len(args) != 4 && len(args) != 6 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	w := &watermarkText{
+		dpi:   defaultWatermarkTextDPI,
+		font:  fmt.Sprintf("%s %d", defaultWatermarkTextFont, defaultWatermarkTextSize),
+		color: color.NRGBA{A: 255}}
+
+	w.text, err = parse.EskipStringArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if w.text == "" {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	w.opacity, err = parse.EskipFloatArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if w.opacity < 0 {
+		w.opacity = 0
+	} else if w.opacity > 1.0 {
+		w.opacity = 1
+	}
+
+	gravity, err := parse.EskipStringArg(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if !gravityType[gravity] {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+	w.verticalGravity = verticalGravity[gravity]
+	w.horizontalGravity = horizontalGravity[gravity]
+
+	switch len(args) {
+	case 4:
+		w.dpi, err = parse.EskipIntArg(args[3])
+		if err != nil {
+			return nil, err
+		}
+		if w.dpi <= 0 {
+			return nil, filters.ErrInvalidFilterParameters
+		}
+	case 6:
+		font, err := parse.EskipStringArg(args[3])
+		if err != nil {
+			return nil, err
+		}
+		if !validFont(font) {
+			err = fmt.Errorf("invalid font %q, expected a Pango font description in the "+
+				"\"[FAMILY-LIST] [STYLE-OPTIONS]\" format, like \"DejaVu Sans, Arial Bold Italic\", without the size", font)
+			log.Error("Failed to create the watermark text filter, ", err)
+			return nil, err
+		}
+
+		size, err := parse.EskipIntArg(args[4])
+		if err != nil {
+			return nil, err
+		}
+		if size <= 0 {
+			return nil, filters.ErrInvalidFilterParameters
+		}
+		w.font = fmt.Sprintf("%s %d", font, size)
+
+		c, err := parse.EskipColorArg(args[5])
+		if err != nil {
+			return nil, err
+		}
+		w.color = color.NRGBA{R: c.R, G: c.G, B: c.B, A: 255}
+	}
+
+	return w, nil
+}
+
+// validFont tells if the font is a Pango font description made of families and style options, like
+// "DejaVu Sans, Arial Bold Italic". The size is not part of it, as it is given separately
+func validFont(font string) bool {
+	words := strings.FieldsFunc(font, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(words) == 0 {
+		return false
+	}
+
+	for _, r := range font {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" ,-_", r) {
+			return false
+		}
+	}
+
+	//a trailing number would be taken as the size
+	last := words[len(words)-1]
+	_, err := strconv.Atoi(last)
+	return err != nil
+}
+
+func (f *watermarkText) Request(ctx filters.FilterContext) {}
+
+func (f *watermarkText) Response(ctx filters.FilterContext) {
+	HandlePixelResponse(ctx, f)
+}
@@ -0,0 +1,208 @@
+package filters
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewWatermarkText(t *testing.T) {
+	name := NewWatermarkText().Name()
+	assert.Equal(t, "watermarkText", name)
+}
+
+func TestWatermarkText_Name(t *testing.T) {
+	c := watermarkText{}
+	assert.Equal(t, "watermarkText", c.Name())
+}
+
+func TestWatermarkText_TransformPixels(t *testing.T) {
+	f := watermarkText{text: "SAMPLE", opacity: 1, dpi: 72, font: bimg.WatermarkFont, color: color.NRGBA{A: 255},
+		verticalGravity: bimg.GravityCentre, horizontalGravity: bimg.GravityCentre}
+	img := image.NewNRGBA(image.Rect(0, 0, 200, 100))
+
+	result, err := f.TransformPixels(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, uint8(0), result.NRGBAAt(0, 0).A, "the text should not be in the corner")
+	painted := false
+	for x := 0; x < 200; x++ {
+		painted = painted || result.NRGBAAt(x, 50).A > 0
+	}
+	assert.True(t, painted, "the text should be in the centre")
+}
+
+func TestTextMask(t *testing.T) {
+	rendered := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	rendered.SetNRGBA(3, 2, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	rendered.SetNRGBA(5, 6, color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+
+	mask := textMask(rendered)
+
+	assert.Equal(t, image.Rect(0, 0, 3, 5), mask.Bounds())
+	assert.Equal(t, uint8(255), mask.AlphaAt(0, 0).A)
+	assert.Equal(t, uint8(128), mask.AlphaAt(2, 4).A)
+	assert.Equal(t, uint8(0), mask.AlphaAt(1, 1).A)
+}
+
+func TestStampText(t *testing.T) {
+	mask := image.NewAlpha(image.Rect(0, 0, 2, 2))
+	for i := range mask.Pix {
+		mask.Pix[i] = 255
+	}
+	red := color.NRGBA{R: 255, A: 255}
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+
+	for _, item := range []struct {
+		gravity string
+		at      image.Point
+	}{
+		{NW, image.Pt(10, 10)},
+		{NE, image.Pt(28, 10)},
+		{CC, image.Pt(19, 19)},
+		{SW, image.Pt(10, 28)},
+	} {
+		img := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+		for i := range img.Pix {
+			img.Pix[i] = 255
+		}
+
+		stampText(img, mask, red, 1, horizontalGravity[item.gravity], verticalGravity[item.gravity])
+
+		assert.Equal(t, red, img.NRGBAAt(item.at.X, item.at.Y), item.gravity)
+		assert.Equal(t, red, img.NRGBAAt(item.at.X+1, item.at.Y+1), item.gravity)
+		assert.Equal(t, white, img.NRGBAAt(item.at.X-1, item.at.Y-1), item.gravity)
+	}
+}
+
+func TestStampText_Opacity(t *testing.T) {
+	mask := image.NewAlpha(image.Rect(0, 0, 1, 1))
+	mask.Pix[0] = 255
+	img := image.NewNRGBA(image.Rect(0, 0, 21, 21))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	stampText(img, mask, color.NRGBA{A: 255}, 0.5, bimg.GravityCentre, bimg.GravityCentre)
+
+	c := img.NRGBAAt(10, 10)
+	assert.InDelta(t, 127, int(c.R), 1)
+	assert.Equal(t, uint8(255), c.A)
+}
+
+func TestWatermarkText_CanBeMerged(t *testing.T) {
+	f := watermarkText{text: "SAMPLE"}
+	assert.False(t, f.CanBeMerged(&watermarkText{text: "SAMPLE"}))
+}
+
+func TestWatermarkText_AfterRotate(t *testing.T) {
+	fc := createDefaultContext(t, "url")
+	fc.FStateBag[skropOptions] = &bimg.Options{Rotate: 90}
+	f := &watermarkText{text: "SAMPLE", opacity: 1}
+
+	assert.Nil(t, HandlePixelResponse(fc, f))
+
+	//the text is placed on the pixels once the rotation is applied, so its position is based on the rotated size
+	assert.Equal(t, &bimg.Options{Rotate: 90}, fc.FStateBag[skropOptions])
+	assert.Equal(t, []PixelFilter{f}, fc.FStateBag[skropPixelFilters])
+}
+
+func TestWatermarkText_AfterGeometry(t *testing.T) {
+	for _, pending := range []*bimg.Options{
+		{Zoom: 2},
+		{Top: 10, Left: 10, AreaWidth: 50, AreaHeight: 50},
+		{Trim: true, Threshold: 10},
+	} {
+		fc := createDefaultContext(t, "url")
+		fc.FStateBag[skropOptions] = pending
+
+		assert.Nil(t, HandlePixelResponse(fc, &watermarkText{text: "SAMPLE", opacity: 1}))
+		assert.Equal(t, pending, fc.FStateBag[skropOptions], "the text should not be merged in %+v", pending)
+	}
+}
+
+func TestWatermarkText_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewWatermarkText, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "four args",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", 150.0},
+		Err:  false,
+	}, {
+		Msg:  "opacity out of range",
+		Args: []interface{}{"SAMPLE", 1.5, "SE", 150.0},
+		Err:  false,
+	}, {
+		Msg:  "empty text",
+		Args: []interface{}{"", 0.5, "SE", 150.0},
+		Err:  true,
+	}, {
+		Msg:  "invalid gravity",
+		Args: []interface{}{"SAMPLE", 0.5, "XX", 150.0},
+		Err:  true,
+	}, {
+		Msg:  "zero dpi",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", 0.0},
+		Err:  true,
+	}, {
+		Msg:  "three args",
+		Args: []interface{}{"SAMPLE", 0.5, "SE"},
+		Err:  false,
+	}, {
+		Msg:  "six args",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", "DejaVu Sans, Arial Bold", 24.0, "#ff0000"},
+		Err:  false,
+	}, {
+		Msg:  "font with the size",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", "sans 24", 24.0, "#ff0000"},
+		Err:  true,
+	}, {
+		Msg:  "empty font",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", "", 24.0, "#ff0000"},
+		Err:  true,
+	}, {
+		Msg:  "zero size",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", "sans", 0.0, "#ff0000"},
+		Err:  true,
+	}, {
+		Msg:  "invalid color",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", "sans", 24.0, "red"},
+		Err:  true,
+	}, {
+		Msg:  "five args",
+		Args: []interface{}{"SAMPLE", 0.5, "SE", "sans", 24.0},
+		Err:  true,
+	}})
+}
+
+func TestWatermarkText_CreateFilter_Font(t *testing.T) {
+	f, err := NewWatermarkText().CreateFilter([]interface{}{"SAMPLE", 0.5, "SE", "DejaVu Sans Bold", 24.0, "#ff0000"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "DejaVu Sans Bold 24", f.(*watermarkText).font)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, f.(*watermarkText).color)
+	assert.Equal(t, 72, f.(*watermarkText).dpi)
+}
+
+func TestWatermarkText_CreateFilter_InvalidFont(t *testing.T) {
+	_, err := NewWatermarkText().CreateFilter([]interface{}{"SAMPLE", 0.5, "SE", "sans; 24", 24.0, "#ff0000"})
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "[FAMILY-LIST] [STYLE-OPTIONS]")
+}
+
+func TestWatermarkText_CreateFilter_ShortForm(t *testing.T) {
+	f, err := NewWatermarkText().CreateFilter([]interface{}{"SAMPLE", 0.5, "SE"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "sans 10", f.(*watermarkText).font)
+	assert.Equal(t, color.NRGBA{A: 255}, f.(*watermarkText).color)
+}
@@ -0,0 +1,69 @@
+package filters
+
+import (
+	"github.com/h2non/bimg"
+	log "github.com/sirupsen/logrus"
+	"github.com/zalando-stups/skrop/parse"
+	"github.com/zalando/skipper/filters"
+)
+
+// ZoomName is the name of the filter
+const ZoomName = "zoom"
+
+type zoom struct {
+	factor int
+}
+
+// NewZoom creates a new filter of this type
+func NewZoom() filters.Spec {
+	return &zoom{}
+}
+
+func (f *zoom) Name() string {
+	return ZoomName
+}
+
+func (f *zoom) CreateOptions(_ *ImageFilterContext) (*bimg.Options, error) {
+	log.Debug("Create options for zoom ", f)
+
+	//bimg zooms by Zoom + 1, so 0 leaves the image as it is
+	return &bimg.Options{
+		Zoom: f.factor - 1}, nil
+}
+
+func (f *zoom) CanBeMerged(other *bimg.Options, self *bimg.Options) bool {
+	//bimg zooms before cropping and resizing, so the zoom cannot be merged with them
+	return orientationCanBeMerged(other) && !other.Crop && !other.Embed && !other.Trim &&
+		other.Top == 0 && other.Left == 0 && other.Watermark.Text == ""
+}
+
+func (f *zoom) Merge(other *bimg.Options, self *bimg.Options) *bimg.Options {
+	other.Zoom = (other.Zoom+1)*(self.Zoom+1) - 1
+	return other
+}
+
+func (f *zoom) CreateFilter(args []interface{}) (filters.Filter, error) {
+	var err error
+
+	if len(args) != 1 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	z := &zoom{}
+
+	z.factor, err = parse.EskipIntArg(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if z.factor <= 0 {
+		return nil, filters.ErrInvalidFilterParameters
+	}
+
+	return z, nil
+}
+
+func (f *zoom) Request(ctx filters.FilterContext) {}
+
+func (f *zoom) Response(ctx filters.FilterContext) {
+	HandleImageResponse(ctx, f)
+}
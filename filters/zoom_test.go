@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/h2non/bimg"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando-stups/skrop/filters/imagefiltertest"
+)
+
+func TestNewZoom(t *testing.T) {
+	name := NewZoom().Name()
+	assert.Equal(t, "zoom", name)
+}
+
+func TestZoom_Name(t *testing.T) {
+	c := zoom{}
+	assert.Equal(t, "zoom", c.Name())
+}
+
+func TestZoom_CreateOptions(t *testing.T) {
+	c := zoom{factor: 2}
+	options, _ := c.CreateOptions(buildParameters(nil, imagefiltertest.LandscapeImage()))
+
+	assert.Equal(t, 1, options.Zoom)
+}
+
+func TestZoom_DoublesSize(t *testing.T) {
+	image := imagefiltertest.LandscapeImage()
+	size, _ := image.Size()
+
+	c := zoom{factor: 2}
+	options, _ := c.CreateOptions(buildParameters(nil, image))
+	result, err := transformImage(image, options, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zoomed, _ := bimg.NewImage(result).Size()
+	assert.Equal(t, 2*size.Width, zoomed.Width)
+	assert.Equal(t, 2*size.Height, zoomed.Height)
+}
+
+func TestZoom_CanBeMerged(t *testing.T) {
+	c := zoom{}
+	self := &bimg.Options{Zoom: 1}
+
+	assert.True(t, c.CanBeMerged(&bimg.Options{}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Quality: 80, Type: bimg.PNG}, self))
+	assert.True(t, c.CanBeMerged(&bimg.Options{Zoom: 2}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 200, Height: 100, Crop: true}, self))
+	assert.False(t, c.CanBeMerged(&bimg.Options{Width: 200}, self))
+}
+
+func TestZoom_Merge(t *testing.T) {
+	c := zoom{}
+	merged := c.Merge(&bimg.Options{Zoom: 1, Quality: 80}, &bimg.Options{Zoom: 2})
+
+	//2x then 3x is 6x
+	assert.Equal(t, &bimg.Options{Zoom: 5, Quality: 80}, merged)
+}
+
+func TestZoom_CreateFilter(t *testing.T) {
+	imagefiltertest.TestCreate(t, NewZoom, []imagefiltertest.CreateTestItem{{
+		Msg:  "no args",
+		Args: nil,
+		Err:  true,
+	}, {
+		Msg:  "one arg",
+		Args: []interface{}{2.0},
+		Err:  false,
+	}, {
+		Msg:  "zero factor",
+		Args: []interface{}{0.0},
+		Err:  true,
+	}, {
+		Msg:  "not an int",
+		Args: []interface{}{1.5},
+		Err:  true,
+	}, {
+		Msg:  "more than one arg",
+		Args: []interface{}{2.0, 2.0},
+		Err:  true,
+	}})
+}
@@ -3,6 +3,18 @@ package messages
 const (
 	// Error500 is the message to output in case of 500
 	Error500 = "Internal error"
+	// Error400 is the message to output in case the parameters of a filter are not valid for the image
+	Error400 = "Invalid filter parameters"
+	// Error413 is the message to output in case the original image is bigger than the limits
+	Error413 = "The image is too large"
+	// Error415 is the message to output in case the type of the image is not supported
+	Error415 = "Unsupported image type"
+	// ErrorCorruptImage is the message to output in case the image cannot be decoded, like a truncated upload
+	ErrorCorruptImage = "The image is corrupt or truncated"
 	// Error404 is the message to output in case the image is not found
 	Error404 = "Cannot find the image"
+	// Error503 is the message to output in case too many images are processed concurrently
+	Error503 = "Too many images are being processed"
+	// Error504 is the message to output in case the processing of the image takes too long
+	Error504 = "The processing of the image timed out"
 )
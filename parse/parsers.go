@@ -1,16 +1,96 @@
 package parse
 
 import (
+	"fmt"
 	"github.com/zalando/skipper/filters"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// ArgError is returned when an eskip argument can not be parsed. It wraps filters.ErrInvalidFilterParameters,
+// so errors.Is still classifies it. The filter and the position of the argument are set with AtArg
+type ArgError struct {
+	Filter   string
+	Index    int
+	Name     string
+	Expected string
+	Got      interface{}
+}
+
+func (e *ArgError) Error() string {
+	var b strings.Builder
+	if e.Filter != "" {
+		b.WriteString(e.Filter + ": ")
+	}
+	if e.Index > 0 {
+		fmt.Fprintf(&b, "argument %d", e.Index)
+		if e.Name != "" {
+			fmt.Fprintf(&b, " (%s)", e.Name)
+		}
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "expected %s, got %s", e.Expected, describeArg(e.Got))
+	return b.String()
+}
+
+// Unwrap makes errors.Is(err, filters.ErrInvalidFilterParameters) true
+func (e *ArgError) Unwrap() error {
+	return filters.ErrInvalidFilterParameters
+}
+
+// AtArg sets the filter name and the position, starting from 1, of the argument which failed to be parsed.
+// The errors which are not an ArgError are returned unchanged
+func AtArg(err error, filter string, index int, name string) error {
+	argErr, ok := err.(*ArgError)
+	if !ok {
+		return err
+	}
+
+	located := *argErr
+	located.Filter, located.Index, located.Name = filter, index, name
+	return &located
+}
+
+func argError(expected string, got interface{}) error {
+	return &ArgError{Expected: expected, Got: got}
+}
+
+// describeArg tells the eskip type of the argument, with its value
+func describeArg(arg interface{}) string {
+	switch value := arg.(type) {
+	case nil:
+		return "nothing"
+	case string:
+		return fmt.Sprintf("string %q", value)
+	case float64, int:
+		return fmt.Sprintf("number %v", value)
+	case bool:
+		return fmt.Sprintf("bool %v", value)
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
 // EskipFloatArg parse an eskip argument into a Float
 func EskipFloatArg(arg interface{}) (float64, error) {
 	if number, ok := arg.(float64); ok {
 		return float64(number), nil
 	}
-	return 0, filters.ErrInvalidFilterParameters
+	return 0, argError("number", arg)
+}
+
+// EskipNumberArg parse an eskip argument which is either an int or a float into a Float, for the filters
+// accepting both 2 and 2.0
+func EskipNumberArg(arg interface{}) (float64, error) {
+	switch number := arg.(type) {
+	case float64:
+		return number, nil
+	case int:
+		return float64(number), nil
+	}
+	return 0, argError("number", arg)
 }
 
 // EskipIntArg parse an eskip argument into an Int
@@ -18,7 +98,7 @@ func EskipIntArg(arg interface{}) (int, error) {
 	if number, ok := arg.(float64); ok && math.Trunc(number) == number {
 		return int(number), nil
 	}
-	return 0, filters.ErrInvalidFilterParameters
+	return 0, argError("int", arg)
 }
 
 // EskipUint8Arg parse an eskip argument into an UInt8
@@ -26,7 +106,27 @@ func EskipUint8Arg(arg interface{}) (uint8, error) {
 	if number, ok := arg.(float64); ok && math.Trunc(number) == number {
 		return uint8(number), nil
 	}
-	return 0, filters.ErrInvalidFilterParameters
+	return 0, argError("int", arg)
+}
+
+// EskipPercentageArg parse an eskip argument which is either a number or a string with a trailing %, like "50%",
+// into a Float, telling if it is a percentage. The numbers can also be strings, like "100"
+func EskipPercentageArg(arg interface{}) (float64, bool, error) {
+	switch value := arg.(type) {
+	case float64:
+		return value, false, nil
+	case int:
+		return float64(value), false, nil
+	case string:
+		number := strings.TrimSuffix(value, "%")
+		parsed, err := strconv.ParseFloat(number, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return 0, false, argError("number or percentage", arg)
+		}
+		return parsed, number != value, nil
+	default:
+		return 0, false, argError("number or percentage", arg)
+	}
 }
 
 // EskipStringArg parse an eskip argument into a String
@@ -34,13 +134,124 @@ func EskipStringArg(arg interface{}) (string, error) {
 	if str, ok := arg.(string); ok {
 		return string(str), nil
 	}
-	return "", filters.ErrInvalidFilterParameters
+	return "", argError("string", arg)
+}
+
+// EskipStringArrayArg parse an eskip argument with comma separated values, like "a, b, c", into a slice of
+// Strings. The values are trimmed and none of them can be empty
+func EskipStringArrayArg(arg interface{}) ([]string, error) {
+	str, ok := arg.(string)
+	if !ok {
+		return nil, argError("comma separated strings", arg)
+	}
+
+	values := strings.Split(str, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] == "" {
+			return nil, argError("comma separated strings", arg)
+		}
+	}
+
+	return values, nil
 }
 
-// EskipBoolArg parse an eskip argument into a Boolean
+// EskipEnumArg parse an eskip argument into a String, which needs to be one of the allowed values
+func EskipEnumArg(arg interface{}, allowed map[string]bool) (string, error) {
+	if str, ok := arg.(string); ok && allowed[str] {
+		return str, nil
+	}
+	return "", argError("one of "+enumValues(allowed), arg)
+}
+
+// EskipBoolArg parse an eskip argument into a Boolean. As eskip has no boolean literals, the strings
+// "true", "false", "1" and "0" are accepted too, ignoring the case
 func EskipBoolArg(arg interface{}) (bool, error) {
-	if value, ok := arg.(bool); ok {
+	switch value := arg.(type) {
+	case bool:
 		return value, nil
+	case string:
+		switch strings.ToLower(value) {
+		case "true", "1":
+			return true, nil
+		case "false", "0":
+			return false, nil
+		}
+	}
+	return false, argError("bool", arg)
+}
+
+// enumValues lists the allowed values sorted, so that the error messages are stable
+func enumValues(allowed map[string]bool) string {
+	values := make([]string, 0, len(allowed))
+	for value, ok := range allowed {
+		if ok {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
+
+// Color is a color with its opacity, from 0 (transparent) to 1 (opaque)
+type Color struct {
+	R, G, B uint8
+	Opacity float64
+}
+
+// EskipColorArg parse an eskip argument in the RRGGBB or RGB hex format, with an optional leading #, into an
+// opaque Color
+func EskipColorArg(arg interface{}) (Color, error) {
+	str, ok := arg.(string)
+	if !ok {
+		return Color{}, argError(hexColor, arg)
+	}
+
+	return parseHexColor(str, false)
+}
+
+// EskipAlphaColorArg parse an eskip argument in the RRGGBBAA, RRGGBB or RGB hex format, with an optional
+// leading #, into a Color with its opacity. The colors without alpha are opaque
+func EskipAlphaColorArg(arg interface{}) (Color, error) {
+	str, ok := arg.(string)
+	if !ok {
+		return Color{}, argError(hexAlphaColor, arg)
+	}
+
+	return parseHexColor(str, true)
+}
+
+const (
+	hexColor      = "color in the RRGGBB or RGB hex format"
+	hexAlphaColor = "color in the RRGGBBAA, RRGGBB or RGB hex format"
+)
+
+func parseHexColor(str string, withAlpha bool) (Color, error) {
+	expected := hexColor
+	if withAlpha {
+		expected = hexAlphaColor
+	}
+
+	hex := strings.TrimPrefix(str, "#")
+	switch {
+	case len(hex) == 3:
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2], 'f', 'f'})
+	case len(hex) == 6:
+		hex += "ff"
+	case len(hex) == 8 && withAlpha:
+	default:
+		return Color{}, argError(expected, str)
 	}
-	return false, filters.ErrInvalidFilterParameters
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, argError(expected, str)
+	}
+
+	return Color{
+		R:       uint8(value >> 24),
+		G:       uint8(value >> 16),
+		B:       uint8(value >> 8),
+		Opacity: float64(uint8(value)) / 255,
+	}, nil
 }
@@ -1,7 +1,9 @@
 package parse
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/zalando/skipper/filters"
 	"testing"
 )
 
@@ -45,12 +47,207 @@ func TestParseEskipFloatArgFailure(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestEskipPercentageArg(t *testing.T) {
+	value, percentage, err := EskipPercentageArg("50%")
+	assert.Nil(t, err)
+	assert.True(t, percentage)
+	assert.Equal(t, 50.0, value)
+
+	value, percentage, err = EskipPercentageArg(12.5)
+	assert.Nil(t, err)
+	assert.False(t, percentage)
+	assert.Equal(t, 12.5, value)
+}
+
+func TestEskipPercentageArg_Numbers(t *testing.T) {
+	value, percentage, err := EskipPercentageArg("100")
+	assert.Nil(t, err)
+	assert.False(t, percentage)
+	assert.Equal(t, 100.0, value)
+
+	value, percentage, err = EskipPercentageArg(100)
+	assert.Nil(t, err)
+	assert.False(t, percentage)
+	assert.Equal(t, 100.0, value)
+
+	value, percentage, err = EskipPercentageArg("-12.5%")
+	assert.Nil(t, err)
+	assert.True(t, percentage)
+	assert.Equal(t, -12.5, value)
+}
+
+func TestEskipPercentageArgFailure(t *testing.T) {
+	for _, arg := range []interface{}{"%", "half%", "50%%", "", "NaN", "Inf%", "50 %", true, nil} {
+		_, _, err := EskipPercentageArg(arg)
+		assert.NotNil(t, err, "There should be an error for %v", arg)
+	}
+}
+
+func TestEskipNumberArg(t *testing.T) {
+	result, err := EskipNumberArg(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, result)
+
+	result, err = EskipNumberArg(2.5)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.5, result)
+}
+
+func TestEskipNumberArgFailure(t *testing.T) {
+	for _, arg := range []interface{}{"2", true, nil} {
+		_, err := EskipNumberArg(arg)
+		assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters), "There should be an error for %v", arg)
+	}
+}
+
+func TestEskipStringArrayArg(t *testing.T) {
+	result, err := EskipStringArrayArg("a,b,c")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, result)
+
+	result, err = EskipStringArrayArg(" a , b ")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, result)
+
+	result, err = EskipStringArrayArg("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, result)
+}
+
+func TestEskipStringArrayArgFailure(t *testing.T) {
+	for _, arg := range []interface{}{"", " ", "a,,b", "a,", ",a", 1.0, nil} {
+		_, err := EskipStringArrayArg(arg)
+		assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters), "There should be an error for %v", arg)
+	}
+}
+
+func TestEskipEnumArg(t *testing.T) {
+	allowed := map[string]bool{"north": true, "south": true}
+
+	result, err := EskipEnumArg("north", allowed)
+	assert.Nil(t, err)
+	assert.Equal(t, "north", result)
+
+	_, err = EskipEnumArg("east", allowed)
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+	assert.EqualError(t, err, `expected one of north, south, got string "east"`)
+
+	_, err = EskipEnumArg("North", allowed)
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+
+	_, err = EskipEnumArg(1.0, allowed)
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+}
+
+func TestArgError(t *testing.T) {
+	_, err := EskipIntArg("abc")
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+	assert.EqualError(t, err, `expected int, got string "abc"`)
+
+	err = AtArg(err, "crop", 2, "height")
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+	assert.EqualError(t, err, `crop: argument 2 (height): expected int, got string "abc"`)
+
+	var argErr *ArgError
+	assert.True(t, errors.As(err, &argErr))
+	assert.Equal(t, 2, argErr.Index)
+	assert.Equal(t, "abc", argErr.Got)
+}
+
+func TestArgError_Messages(t *testing.T) {
+	for _, test := range []struct {
+		err      error
+		expected string
+	}{
+		{AtArg(second(EskipFloatArg(nil)), "blur", 1, "sigma"), "blur: argument 1 (sigma): expected number, got nothing"},
+		{AtArg(second(EskipStringArg(12.0)), "resize", 3, ""), "resize: argument 3: expected string, got number 12"},
+		{second(EskipBoolArg("yes")), `expected bool, got string "yes"`},
+		{second(EskipColorArg("#ff80")), `expected color in the RRGGBB or RGB hex format, got string "#ff80"`},
+	} {
+		assert.True(t, errors.Is(test.err, filters.ErrInvalidFilterParameters))
+		assert.EqualError(t, test.err, test.expected)
+	}
+}
+
+func TestAtArg_OtherErrors(t *testing.T) {
+	assert.Equal(t, filters.ErrInvalidFilterParameters, AtArg(filters.ErrInvalidFilterParameters, "crop", 1, "width"))
+	assert.Nil(t, AtArg(nil, "crop", 1, "width"))
+}
+
+func second(_ interface{}, err error) error {
+	return err
+}
+
 func TestEskipBoolArg(t *testing.T) {
 	result, _ := EskipBoolArg(true)
 	assert.True(t, result)
 }
 
+func TestEskipBoolArg_String(t *testing.T) {
+	for arg, expected := range map[string]bool{
+		"true":  true,
+		"True":  true,
+		"1":     true,
+		"false": false,
+		"FALSE": false,
+		"0":     false,
+	} {
+		result, err := EskipBoolArg(arg)
+		assert.Nil(t, err, arg)
+		assert.Equal(t, expected, result, arg)
+	}
+}
+
+func TestEskipBoolArg_False(t *testing.T) {
+	result, err := EskipBoolArg(false)
+	assert.Nil(t, err)
+	assert.False(t, result)
+}
+
 func TestEskipBoolArgFailure(t *testing.T) {
-	_, err := EskipBoolArg(13)
-	assert.NotNil(t, err)
+	for _, arg := range []interface{}{13, 1.0, "yes", "", "2", " true", nil} {
+		_, err := EskipBoolArg(arg)
+		assert.NotNil(t, err, "There should be an error for %v", arg)
+	}
+}
+
+func TestEskipColorArg(t *testing.T) {
+	result, _ := EskipColorArg("#ff8000")
+	assert.Equal(t, Color{R: 255, G: 128, B: 0, Opacity: 1}, result)
+
+	result, _ = EskipColorArg("F80")
+	assert.Equal(t, Color{R: 255, G: 136, B: 0, Opacity: 1}, result)
+}
+
+func TestEskipColorArgFailure(t *testing.T) {
+	for _, arg := range []interface{}{"#ff80", "#gg8000", "#ff800000", "", 13.0} {
+		_, err := EskipColorArg(arg)
+		assert.NotNil(t, err, "There should be an error for %v", arg)
+	}
+}
+
+func TestEskipColorArg_Error(t *testing.T) {
+	_, err := EskipColorArg("#gg8000")
+	assert.True(t, errors.Is(err, filters.ErrInvalidFilterParameters))
+	assert.Contains(t, err.Error(), "#gg8000")
+}
+
+func TestEskipAlphaColorArg(t *testing.T) {
+	for arg, expected := range map[string]Color{
+		"#ff800080": {R: 255, G: 128, B: 0, Opacity: 128.0 / 255},
+		"00000000":  {Opacity: 0},
+		"#ff8000":   {R: 255, G: 128, B: 0, Opacity: 1},
+		"#F80":      {R: 255, G: 136, B: 0, Opacity: 1},
+	} {
+		result, err := EskipAlphaColorArg(arg)
+		assert.Nil(t, err, arg)
+		assert.Equal(t, expected, result, arg)
+	}
+}
+
+func TestEskipAlphaColorArgFailure(t *testing.T) {
+	for _, arg := range []interface{}{"#ff80", "#ff8000800", "#gg800080", "#ff8000-1", "##ff8000", "", 13.0} {
+		_, err := EskipAlphaColorArg(arg)
+		assert.NotNil(t, err, "There should be an error for %v", arg)
+	}
 }